@@ -15,14 +15,12 @@
 package commands
 
 import (
-	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/DarkMetrix/gofra/internal/pkg/directory"
 	"github.com/DarkMetrix/gofra/internal/pkg/generate"
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
-	"github.com/DarkMetrix/gofra/internal/pkg/utils/gomod"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
@@ -53,15 +51,8 @@ docker generate command will help to generate docker file.`,
 			log.Fatalf("checkLabels failed! error:%+v", err)
 		}
 
-		// get project name
-		if project == "" {
-			// get project name from go.mod
-			goModule, err := gomod.GetGoModule(filepath.Join(outputPath, "go.mod"))
-			if err != nil {
-				log.Fatalf("utils.GetGoModule failed! error:%v", err)
-			}
-			project = path.Base(goModule)
-		}
+		// get project name from flag, project manifest or go.mod
+		project = getProject(loadManifest())
 
 		opts := []option.Option{
 			option.WithOutputPath(outputPath),
@@ -0,0 +1,79 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/generate"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Render the API documents of all the services (*.proto) of project",
+	Long: `Gofra is a framework using gRPC as the communication layer.
+docs command will help to render the API documents of every service under api/protobuf_spec into api/docs,
+covering services, RPCs, message fields, enums and their leading comments in markdown, static html and OpenAPI 3.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Info("====== gofra docs ======")
+
+		// get go module & protoc flags from project manifest
+		projectManifest := loadManifest()
+		goModule := getGoModule(projectManifest)
+		applyManifestProtocFlags(cmd, projectManifest)
+
+		opts := []option.Option{
+			option.WithOutputPath(outputPath),
+			option.WithGoModule(goModule),
+			option.WithProtoFileIncludePath(protoFileIncludePath),
+			option.WithDocFormats(docFormats),
+		}
+
+		// render documents
+		layout := directory.NewGRPCLayout(opts...)
+		filePaths, err := generate.NewGRPCServiceGenerator().GenerateDocs(layout, opts...)
+		if err != nil {
+			log.Fatalf("generate.GenerateDocs failed! error:%+v", err)
+		}
+		if len(filePaths) == 0 {
+			log.Warnf("No service found in %v", layout.GetAPIProtobufBasePath())
+			return
+		}
+		for _, filePath := range filePaths {
+			log.Infof("document rendered: %v", filePath)
+		}
+	},
+}
+
+var (
+	docFormats []string
+)
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+
+	docsCmd.PersistentFlags().StringVar(&outputPath,
+		"output-path", filepath.Join("."), "output path, default is '.'")
+	docsCmd.PersistentFlags().StringSliceVar(&docFormats,
+		"format", []string{generate.DocFormatMarkdown, generate.DocFormatHTML, generate.DocFormatOpenAPI},
+		"document formats, markdown or html or openapi, can be separated by comma")
+	docsCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
+		"proto-include-path", []string{}, "proto files include path used by protoc's command '--proto_path'")
+}
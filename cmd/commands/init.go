@@ -37,7 +37,9 @@ var initCmd = &cobra.Command{
 			option.WithOverride(override),
 			option.WithGoModule(goModule),
 			option.WithGoVersion(goVersion),
+			option.WithProtoTool(protoTool),
 			option.WithProtocPath(protocPath),
+			option.WithBufPath(bufPath),
 			option.WithProtoFileIncludePath(protoFileIncludePath),
 		}
 
@@ -52,7 +54,9 @@ var initCmd = &cobra.Command{
 
 var (
 	outputPath           string
+	protoTool            string
 	protocPath           string
+	bufPath              string
 	protoFileIncludePath []string
 	override             bool
 	goModule             string
@@ -70,8 +74,12 @@ func init() {
 		"output-path", filepath.Join("."), "output path, default is '.'")
 	initCmd.PersistentFlags().BoolVar(&override,
 		"override", false, "If override when file exists")
+	initCmd.PersistentFlags().StringVar(&protoTool,
+		"proto-tool", "protoc", "tool used to compile .proto files, protoc or native(no protoc & plugins needed except the http gateway ones) or buf(buf.yaml & buf.gen.yaml in api/protobuf_spec)")
 	initCmd.PersistentFlags().StringVar(&protocPath,
 		"protoc-path", "protoc", "protoc binary path, in case user has multi versions of protoc")
+	initCmd.PersistentFlags().StringVar(&bufPath,
+		"buf-path", "buf", "buf binary path, used when proto tool is buf")
 	initCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
 		"proto-include-path", []string{}, "proto files include path used by protoc's command '--proto_path'")
 	initCmd.PersistentFlags().StringVar(&goModule,
@@ -15,13 +15,11 @@
 package commands
 
 import (
-	"path"
 	"path/filepath"
 
 	"github.com/DarkMetrix/gofra/internal/pkg/directory"
 	"github.com/DarkMetrix/gofra/internal/pkg/generate"
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
-	"github.com/DarkMetrix/gofra/internal/pkg/utils/gomod"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -46,15 +44,8 @@ istio virtual-service command will help to generate istio virtual service yaml f
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Info("====== gofra istio virtual-service ======")
 
-		// get project name
-		if project == "" {
-			// get project name from go.mod
-			goModule, err := gomod.GetGoModule(filepath.Join(outputPath, "go.mod"))
-			if err != nil {
-				log.Fatalf("utils.GetGoModule failed! error:%v", err)
-			}
-			project = path.Base(goModule)
-		}
+		// get project name from flag, project manifest or go.mod
+		project = getProject(loadManifest())
 
 		opts := []option.Option{
 			option.WithOutputPath(outputPath),
@@ -82,15 +73,8 @@ istio destination-rule command will help to generate istio destination rule yaml
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Info("====== gofra istio destination-rule ======")
 
-		// get project name
-		if project == "" {
-			// get project name from go.mod
-			goModule, err := gomod.GetGoModule(filepath.Join(outputPath, "go.mod"))
-			if err != nil {
-				log.Fatalf("utils.GetGoModule failed! error:%v", err)
-			}
-			project = path.Base(goModule)
-		}
+		// get project name from flag, project manifest or go.mod
+		project = getProject(loadManifest())
 
 		opts := []option.Option{
 			option.WithOutputPath(outputPath),
@@ -0,0 +1,78 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"path"
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/manifest"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/gomod"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// loadManifest loads the project manifest in output path, an empty manifest is returned if there is none
+func loadManifest() *manifest.Manifest {
+	projectManifest, err := manifest.LoadOrNew(outputPath)
+	if err != nil {
+		log.Fatalf("manifest.LoadOrNew failed! error:%+v", err)
+	}
+	return projectManifest
+}
+
+// getGoModule returns the go module recorded by project manifest, or read from go.mod
+func getGoModule(projectManifest *manifest.Manifest) string {
+	if projectManifest.GoModule != "" {
+		return projectManifest.GoModule
+	}
+
+	// get go module from go.mod
+	goModule, err := gomod.GetGoModule(filepath.Join(outputPath, "go.mod"))
+	if err != nil {
+		log.Fatalf("utils.GetGoModule failed! error:%v", err)
+	}
+	return goModule
+}
+
+// getProject returns the project specified by flag, or recorded by project manifest, or the base of go module
+func getProject(projectManifest *manifest.Manifest) string {
+	if project != "" {
+		return project
+	}
+	if projectManifest.Project != "" {
+		return projectManifest.Project
+	}
+	return path.Base(getGoModule(projectManifest))
+}
+
+// applyManifestProtocFlags sets protoc flags not specified by user to the ones recorded by project manifest
+func applyManifestProtocFlags(cmd *cobra.Command, projectManifest *manifest.Manifest) {
+	if !cmd.Flags().Changed("proto-tool") && projectManifest.ProtoTool != "" {
+		protoTool = projectManifest.ProtoTool
+	}
+	if !cmd.Flags().Changed("protoc-path") && projectManifest.ProtocPath != "" {
+		protocPath = projectManifest.ProtocPath
+	}
+	if !cmd.Flags().Changed("buf-path") && projectManifest.BufPath != "" {
+		bufPath = projectManifest.BufPath
+	}
+	if !cmd.Flags().Changed("http-gateway") && projectManifest.HTTPGateway {
+		httpGateway = true
+	}
+	if !cmd.Flags().Changed("proto-include-path") && projectManifest.ProtoFileIncludePath != nil {
+		protoFileIncludePath = projectManifest.ProtoFileIncludePath
+	}
+}
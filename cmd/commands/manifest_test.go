@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// newProtocFlagsCommand returns a command with the protoc flags of 'service add', the flags are reset to defaults
+func newProtocFlagsCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVar(&protoTool, "proto-tool", "protoc", "")
+	cmd.Flags().StringVar(&protocPath, "protoc-path", "protoc", "")
+	cmd.Flags().StringVar(&bufPath, "buf-path", "buf", "")
+	cmd.Flags().StringArrayVar(&protoFileIncludePath, "proto-include-path", []string{}, "")
+	cmd.Flags().BoolVar(&httpGateway, "http-gateway", false, "")
+	return cmd
+}
+
+func TestApplyManifestProtocFlags(t *testing.T) {
+	recorded := &manifest.Manifest{
+		ProtoTool:            "buf",
+		ProtocPath:           "/usr/local/bin/protoc",
+		BufPath:              "/usr/local/bin/buf",
+		ProtoFileIncludePath: []string{"third_party"},
+		HTTPGateway:          true,
+	}
+
+	tests := []struct {
+		name                     string
+		args                     []string
+		manifest                 *manifest.Manifest
+		wantProtoTool            string
+		wantProtocPath           string
+		wantBufPath              string
+		wantProtoFileIncludePath []string
+		wantHTTPGateway          bool
+	}{
+		{
+			name:                     "defaults without manifest",
+			manifest:                 &manifest.Manifest{},
+			wantProtoTool:            "protoc",
+			wantProtocPath:           "protoc",
+			wantBufPath:              "buf",
+			wantProtoFileIncludePath: []string{},
+		},
+		{
+			name:                     "manifest over defaults",
+			manifest:                 recorded,
+			wantProtoTool:            "buf",
+			wantProtocPath:           "/usr/local/bin/protoc",
+			wantBufPath:              "/usr/local/bin/buf",
+			wantProtoFileIncludePath: []string{"third_party"},
+			wantHTTPGateway:          true,
+		},
+		{
+			name: "flags over manifest",
+			args: []string{"--proto-tool", "native", "--protoc-path", "protoc3", "--buf-path", "buf1",
+				"--proto-include-path", "vendor", "--http-gateway=false"},
+			manifest:                 recorded,
+			wantProtoTool:            "native",
+			wantProtocPath:           "protoc3",
+			wantBufPath:              "buf1",
+			wantProtoFileIncludePath: []string{"vendor"},
+		},
+		{
+			name:                     "flags set to the defaults explicitly",
+			args:                     []string{"--proto-tool", "protoc"},
+			manifest:                 recorded,
+			wantProtoTool:            "protoc",
+			wantProtocPath:           "/usr/local/bin/protoc",
+			wantBufPath:              "/usr/local/bin/buf",
+			wantProtoFileIncludePath: []string{"third_party"},
+			wantHTTPGateway:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newProtocFlagsCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("cmd.ParseFlags failed! error:%v", err)
+			}
+
+			applyManifestProtocFlags(cmd, tt.manifest)
+
+			if protoTool != tt.wantProtoTool {
+				t.Errorf("protoTool = %v, want %v", protoTool, tt.wantProtoTool)
+			}
+			if protocPath != tt.wantProtocPath {
+				t.Errorf("protocPath = %v, want %v", protocPath, tt.wantProtocPath)
+			}
+			if bufPath != tt.wantBufPath {
+				t.Errorf("bufPath = %v, want %v", bufPath, tt.wantBufPath)
+			}
+			if !reflect.DeepEqual(protoFileIncludePath, tt.wantProtoFileIncludePath) {
+				t.Errorf("protoFileIncludePath = %v, want %v", protoFileIncludePath, tt.wantProtoFileIncludePath)
+			}
+			if httpGateway != tt.wantHTTPGateway {
+				t.Errorf("httpGateway = %v, want %v", httpGateway, tt.wantHTTPGateway)
+			}
+		})
+	}
+}
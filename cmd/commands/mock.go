@@ -0,0 +1,79 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/generate"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// mockCmd represents the mock command
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Generate mock servers of service (*.proto) for consumer-side testing",
+	Long: `Gofra is a framework using gRPC as the communication layer.
+mock command will help to generate the mock servers of the services in a .proto file added to project into pkg/mock,
+they return the canned responses of a YAML or JSON fixture file keyed by RPC name and optionally request fields,
+and record the requests received. A mock server runs with 'go run ./pkg/mock/<service>/cmd' or in the tests of
+consumers by importing the package. The mock servers are kept in sync by service update and removed by service remove.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Info("====== gofra mock ======")
+
+		// get go module & protoc flags from project manifest
+		projectManifest := loadManifest()
+		goModule := getGoModule(projectManifest)
+		applyManifestProtocFlags(cmd, projectManifest)
+
+		opts := []option.Option{
+			option.WithOutputPath(outputPath),
+			option.WithGoModule(goModule),
+			option.WithProtoFileIncludePath(protoFileIncludePath),
+		}
+
+		// check proto was added, --path is resolved the same way as service remove
+		layout := directory.NewGRPCLayout(opts...)
+		protoPath := getAddedProtoPath(layout)
+
+		// generate mock servers
+		serviceNames, err := generate.NewGRPCServiceGenerator().Mock(protoPath, layout, opts...)
+		if err != nil {
+			log.Fatalf("generate.Mock failed! error:%+v", err)
+		}
+		if len(serviceNames) == 0 {
+			log.Warnf("No service found in %v", protoPath)
+			return
+		}
+		for _, serviceName := range serviceNames {
+			log.Infof("mock server generated: %v, fixtures: %v",
+				layout.GetMockPath(serviceName), layout.GetMockFixtureFilePath(serviceName))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+
+	mockCmd.PersistentFlags().StringVar(&outputPath,
+		"output-path", filepath.Join("."), "output path, default is '.'")
+	mockCmd.PersistentFlags().StringVar(&protoFilePath,
+		"path", "", "A .proto file which was added to project, the same as service add or relative to api/protobuf_spec")
+	mockCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
+		"proto-include-path", []string{}, "proto files include path used by service add and to parse the imports of proto file")
+}
@@ -0,0 +1,125 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/generate"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// regenerateCmd represents the regenerate command
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate",
+	Short: "Regenerate all the services (*.proto) of project",
+	Long: `Gofra is a framework using gRPC as the communication layer.
+regenerate command will help to recompile every .proto file under api/protobuf_spec and update service frame & handler,
+e.g.: after upgrading gofra, protoc or protoc plugins.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Info("====== gofra regenerate ======")
+
+		// get go module & protoc flags from project manifest
+		projectManifest := loadManifest()
+		goModule := getGoModule(projectManifest)
+		applyManifestProtocFlags(cmd, projectManifest)
+
+		opts := []option.Option{
+			option.WithOutputPath(outputPath),
+			option.WithGoModule(goModule),
+			option.WithOverride(false),
+			option.WithProtoTool(protoTool),
+			option.WithProtocPath(protocPath),
+			option.WithBufPath(bufPath),
+			option.WithProtoFileIncludePath(protoFileIncludePath),
+			option.WithHTTPGateway(httpGateway),
+		}
+
+		// regenerate services
+		layout := directory.NewGRPCLayout(opts...)
+		results, err := generate.NewGRPCServiceGenerator().Regenerate(layout, opts...)
+		printRegenerateResults(results)
+		if err != nil {
+			log.Fatalf("generate.Regenerate failed! error:%+v", err)
+		}
+	},
+}
+
+// printRegenerateResults prints the summary table of regenerated protos
+func printRegenerateResults(results []*generate.RegenerateResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "PROTO\tSTATUS\tCHANGED .pb.go\tADDED HANDLERS\tADDED main.go STUBS")
+	for _, result := range results {
+		status := "ok"
+		if result.Err != nil {
+			status = "failed"
+		}
+
+		rows := len(result.ChangedPBFiles)
+		if len(result.AddedHandlers) > rows {
+			rows = len(result.AddedHandlers)
+		}
+		if len(result.AddedMainStubs) > rows {
+			rows = len(result.AddedMainStubs)
+		}
+		if rows == 0 {
+			rows = 1
+		}
+
+		// one line per changed file or stub, the proto & status are only printed on the first line
+		for index := 0; index < rows; index++ {
+			columns := []string{"", "", getOrDash(result.ChangedPBFiles, index),
+				getOrDash(result.AddedHandlers, index), getOrDash(result.AddedMainStubs, index)}
+			if index == 0 {
+				columns[0], columns[1] = result.ProtoPath, status
+			}
+			fmt.Fprintln(writer, strings.Join(columns, "\t"))
+		}
+	}
+	_ = writer.Flush()
+}
+
+// getOrDash returns values[index] or '-' if index is out of range
+func getOrDash(values []string, index int) string {
+	if index < len(values) {
+		return values[index]
+	}
+	if index == 0 {
+		return "-"
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(regenerateCmd)
+
+	regenerateCmd.PersistentFlags().StringVar(&outputPath,
+		"output-path", filepath.Join("."), "output path, default is '.'")
+	regenerateCmd.PersistentFlags().StringVar(&protoTool,
+		"proto-tool", "protoc", "tool used to compile .proto files, protoc or native or buf")
+	regenerateCmd.PersistentFlags().StringVar(&protocPath,
+		"protoc-path", "protoc", "protoc binary path, in case user has multi versions of protoc")
+	regenerateCmd.PersistentFlags().StringVar(&bufPath,
+		"buf-path", "buf", "buf binary path, used when proto tool is buf")
+	regenerateCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
+		"proto-include-path", []string{}, "proto files include path used by protoc's command '--proto_path'")
+}
@@ -18,13 +18,20 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	gofraVersion "github.com/DarkMetrix/gofra/internal/pkg/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	homedir "github.com/mitchellh/go-homedir"
 )
 
-var cfgFile string
+var (
+	cfgFile string
+	dryRun  bool
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -39,6 +46,20 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//	Run: func(cmd *cobra.Command, args []string) { },
+	Version: gofraVersion.Version,
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// print the changes kept in memory instead of written to the tree
+		if dryRun {
+			fsutil.PrintDryRunReport(os.Stdout)
+
+			// the native compiler writes through fsutil, the commands without --proto-tool compile nothing
+			hasProtoTool := cmd.Flags().Lookup("proto-tool") != nil
+			if hasProtoTool && (protoTool == pb.ProtoToolProtoc || protoTool == pb.ProtoToolBuf) {
+				fmt.Printf("dry-run: %v is not run, the changes of the .pb.go files it generates are not shown\n",
+					protoTool)
+			}
+		}
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -57,6 +78,12 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gofra.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun,
+		"dry-run", false, "Print the files would be created, overwritten or deleted with diffs, without touching the tree"+
+			" (protoc & buf are not run, so the changes of .pb.go files are not shown unless --proto-tool is native)")
+	rootCmd.PersistentFlags().String("template-dir",
+		"", "Directory of templates (e.g.: template-main.tmpl) used before the built-in ones, see 'gofra templates export'")
+	_ = viper.BindPFlag("template_dir", rootCmd.PersistentFlags().Lookup("template-dir"))
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -65,6 +92,8 @@ func init() {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	fsutil.SetDryRun(dryRun)
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -87,4 +116,14 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
+
+	// templates are loaded from '--template-dir' or 'template_dir' of config file
+	if templateDir := viper.GetString("template_dir"); templateDir != "" {
+		templateDir, err := homedir.Expand(templateDir)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		templates.SetTemplateDir(templateDir)
+	}
 }
@@ -16,12 +16,12 @@ package commands
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/DarkMetrix/gofra/internal/pkg/directory"
 	"github.com/DarkMetrix/gofra/internal/pkg/generate"
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
-	"github.com/DarkMetrix/gofra/internal/pkg/utils/gomod"
-	"github.com/DarkMetrix/gofra/pkg/utils"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -29,7 +29,7 @@ import (
 // serviceCmd represents the service command
 var serviceCmd = &cobra.Command{
 	Use:   "service",
-	Short: "Service operations [add, update]",
+	Short: "Service operations [add, update, remove]",
 	Long: `Gofra is a framework using gRPC as the communication layer.
 service command will help to manipulate .proto file to generate service frame & handler.`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -42,37 +42,59 @@ var addServiceCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add service (*.proto) to project",
 	Long: `Gofra is a framework using gRPC as the communication layer.
-service add command will help to manipulate .proto file to add service frame & handler.`,
+service add command will help to manipulate .proto file to add service frame & handler.
+--path accepts .proto files and directories, the path relative to the directory or the include path is kept
+in api/protobuf_spec so that the imports still work, and the imported .proto files are added as well.
+--http-gateway generates grpc-gateway handlers & OpenAPI specs for the RPCs with google.api.http annotations,
+and serves them on server.http_addr. protoc-gen-grpc-gateway & protoc-gen-openapiv2 must be in PATH for every
+proto tool, the native one included.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Info("====== gofra service add ======")
 
-		// get go module from go.mod
-		goModule, err := gomod.GetGoModule(filepath.Join(outputPath, "go.mod"))
-		if err != nil {
-			log.Fatalf("utils.GetGoModule failed! error:%v", err)
-		}
+		// get go module & protoc flags from project manifest
+		projectManifest := loadManifest()
+		goModule := getGoModule(projectManifest)
+		applyManifestProtocFlags(cmd, projectManifest)
 
 		opts := []option.Option{
 			option.WithOutputPath(outputPath),
 			option.WithGoModule(goModule),
 			option.WithOverride(false),
+			option.WithProtoTool(protoTool),
 			option.WithProtocPath(protocPath),
+			option.WithBufPath(bufPath),
 			option.WithProtoFileIncludePath(protoFileIncludePath),
+			option.WithBreakingCheck(breakingCheck),
+			option.WithHTTPGateway(httpGateway),
 		}
 
-		// copy proto
+		// init http gateway before collecting protos, google/api/annotations.proto is written into api/protobuf_spec
 		layout := directory.NewGRPCLayout(opts...)
-		if err := utils.CreatePaths(false, layout.GetAPIProtobufPath(protoFilePath)); err != nil {
-			log.Fatalf("utils.CreatePaths failed! error:%+v", err)
+		generator := generate.NewGRPCServiceGenerator()
+		if httpGateway {
+			if err := generator.InitHTTPGateway(layout, opts...); err != nil {
+				log.Fatalf("generate.InitHTTPGateway failed! error:%+v", err)
+			}
+		}
+
+		// collect protos & imports
+		protoFiles, err := generate.CollectProtoFiles(protoFilePaths, protoFileIncludePath, layout)
+		if err != nil {
+			log.Fatalf("generate.CollectProtoFiles failed! error:%+v", err)
 		}
-		if err := utils.CopyFile(protoFilePath, layout.GetAPIProtobufFilePath(protoFilePath)); err != nil {
-			log.Fatalf("utils.CopyFile failed! error:%+v", err)
+
+		// copy protos, all of them are copied before compiling so that the imports can be found
+		protoFiles = getProtoFilesToAdd(protoFiles)
+		for _, protoFile := range protoFiles {
+			copyProtoFile(protoFile)
 		}
 
-		// add service
-		if err := generate.NewGRPCServiceGenerator().Add(
-			layout.GetAPIProtobufFilePath(protoFilePath), layout, opts...); err != nil {
-			log.Fatalf("generate.Add failed! error:%+v", err)
+		// add services
+		for _, protoFile := range protoFiles {
+			log.Infof("Adding %v......", protoFile.ProjectPath)
+			if err := generator.Add(protoFile.ProjectPath, layout, opts...); err != nil {
+				log.Fatalf("generate.Add failed! error:%+v", err)
+			}
 		}
 	},
 }
@@ -82,49 +104,181 @@ var updateServiceCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update service (*.proto) to project",
 	Long: `Gofra is a framework using gRPC as the communication layer.
-service update command will help to manipulate .proto file to update service frame & handler.`,
+service update command will help to manipulate .proto file to update service frame & handler,
+handlers of removed or renamed RPCs and handlers whose signature changed are reported.
+Wire breaking changes against the proto added before are reported and stop the update unless --allow-breaking is specified.
+--http-gateway defaults to the one recorded by project manifest, --http-gateway=false skips generating the gateway of this update.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Info("====== gofra service update ======")
 
-		// get go module from go.mod
-		goModule, err := gomod.GetGoModule(filepath.Join(outputPath, "go.mod"))
-		if err != nil {
-			log.Fatalf("utils.GetGoModule failed! error:%v", err)
-		}
+		// get go module & protoc flags from project manifest
+		projectManifest := loadManifest()
+		goModule := getGoModule(projectManifest)
+		applyManifestProtocFlags(cmd, projectManifest)
 
 		opts := []option.Option{
 			option.WithOutputPath(outputPath),
 			option.WithGoModule(goModule),
 			option.WithOverride(false),
+			option.WithProtoTool(protoTool),
 			option.WithProtocPath(protocPath),
+			option.WithBufPath(bufPath),
 			option.WithProtoFileIncludePath(protoFileIncludePath),
+			option.WithFixHandlers(fixHandlers),
+			option.WithBreakingCheck(breakingCheck),
+			option.WithHTTPGateway(httpGateway),
 		}
 
-		// copy proto
+		// collect proto & imports
 		layout := directory.NewGRPCLayout(opts...)
-		if err := utils.CreatePaths(false, layout.GetAPIProtobufPath(protoFilePath)); err != nil {
-			log.Fatalf("utils.CreatePaths failed! error:%+v", err)
+		protoFiles, err := generate.CollectProtoFiles([]string{protoFilePath}, protoFileIncludePath, layout)
+		if err != nil {
+			log.Fatalf("generate.CollectProtoFiles failed! error:%+v", err)
+		}
+		protoFile := protoFiles[len(protoFiles)-1]
+
+		// check breaking changes before the proto added before is overridden
+		generator := generate.NewGRPCServiceGenerator()
+		changes, err := generator.CheckBreakingChanges(protoFile.ProjectPath, protoFilePath, layout, opts...)
+		if err != nil {
+			log.Fatalf("generate.CheckBreakingChanges failed! error:%+v", err)
 		}
-		if err := utils.CopyFile(protoFilePath, layout.GetAPIProtobufFilePath(protoFilePath)); err != nil {
-			log.Fatalf("utils.CopyFile failed! error:%+v", err)
+		if len(changes) != 0 {
+			log.Warnf("%v breaking change(s) found in %v:", len(changes), protoFilePath)
+			for _, change := range changes {
+				log.Warnf("  %v", change)
+			}
+			if !allowBreaking {
+				log.Fatalf("Breaking changes found! use --allow-breaking to update anyway")
+			}
 		}
 
+		// copy & add the imports not in project, then copy proto
+		imports := getProtoFilesToAdd(protoFiles[:len(protoFiles)-1])
+		for _, importFile := range imports {
+			copyProtoFile(importFile)
+		}
+		for _, importFile := range imports {
+			if err := generator.Add(importFile.ProjectPath, layout, opts...); err != nil {
+				log.Fatalf("generate.Add failed! error:%+v", err)
+			}
+		}
+		copyProtoFile(protoFile)
+
 		// update service
-		if err := generate.NewGRPCServiceGenerator().Update(
-			layout.GetAPIProtobufFilePath(protoFilePath), layout, opts...); err != nil {
+		if err := generator.Update(protoFile.ProjectPath, layout, opts...); err != nil {
 			log.Fatalf("generate.Update failed! error:%+v", err)
 		}
 	},
 }
 
+// removeServiceCmd represents the service remove command
+var removeServiceCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove service (*.proto) from project",
+	Long: `Gofra is a framework using gRPC as the communication layer.
+service remove command will help to remove service frame & handler generated by service add.
+--path accepts the .proto file & --proto-include-path used by service add, e.g.: --path protos/pay/pay.proto,
+or the path relative to api/protobuf_spec, e.g.: --path pay/pay.proto.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Info("====== gofra service remove ======")
+
+		// get go module & protoc flags from project manifest
+		projectManifest := loadManifest()
+		goModule := getGoModule(projectManifest)
+		applyManifestProtocFlags(cmd, projectManifest)
+
+		opts := []option.Option{
+			option.WithOutputPath(outputPath),
+			option.WithGoModule(goModule),
+			option.WithKeepHandlers(keepHandlers),
+			option.WithProtoFileIncludePath(protoFileIncludePath),
+		}
+
+		// check proto was added
+		layout := directory.NewGRPCLayout(opts...)
+		protoPath := getAddedProtoPath(layout)
+
+		// remove service
+		if err := generate.NewGRPCServiceGenerator().Remove(protoPath, layout, opts...); err != nil {
+			log.Fatalf("generate.Remove failed! error:%+v", err)
+		}
+	},
+}
+
+// getProtoFilesToAdd skips the imported protos which are already in project, they're not overridden
+func getProtoFilesToAdd(protoFiles []*generate.ProtoFile) []*generate.ProtoFile {
+	protoFilesToAdd := make([]*generate.ProtoFile, 0, len(protoFiles))
+	for _, protoFile := range protoFiles {
+		if protoFile.Imported {
+			isExist, err := fsutil.CheckPathExists(protoFile.ProjectPath)
+			if err != nil {
+				log.Fatalf("fsutil.CheckPathExists failed! error:%+v", err)
+			}
+			if isExist {
+				log.Infof("Imported proto already exists, skip it! proto file:%v", protoFile.ProjectPath)
+				continue
+			}
+		}
+		protoFilesToAdd = append(protoFilesToAdd, protoFile)
+	}
+	return protoFilesToAdd
+}
+
+// copyProtoFile copies the proto into api/protobuf_spec
+func copyProtoFile(protoFile *generate.ProtoFile) {
+	if err := fsutil.CreatePaths(false, filepath.Dir(protoFile.ProjectPath)); err != nil {
+		log.Fatalf("fsutil.CreatePaths failed! error:%+v", err)
+	}
+	if err := fsutil.CopyFile(protoFile.SourcePath, protoFile.ProjectPath); err != nil {
+		log.Fatalf("fsutil.CopyFile failed! error:%+v", err)
+	}
+}
+
+// getAddedProtoPath returns the path in api/protobuf_spec of the proto specified by --path, which is either the
+// .proto file & include paths used by service add or the path relative to api/protobuf_spec
+func getAddedProtoPath(layout directory.GRPCServiceLayout) string {
+	candidates := []string{
+		generate.GetProtoProjectPath(protoFilePath, protoFileIncludePath, layout),
+		filepath.Join(layout.GetAPIProtobufBasePath(), protoFilePath),
+		layout.GetAPIProtobufFilePath(protoFilePath),
+	}
+	// the path in project, e.g.: api/protobuf_spec/pay/pay.proto
+	if relativePath, err := filepath.Rel(layout.GetAPIProtobufBasePath(), protoFilePath); err == nil &&
+		!strings.HasPrefix(relativePath, "..") {
+		candidates = append([]string{protoFilePath}, candidates...)
+	}
+
+	for _, candidate := range candidates {
+		isExist, err := fsutil.CheckPathExists(candidate)
+		if err != nil {
+			log.Fatalf("fsutil.CheckPathExists failed! error:%+v", err)
+		}
+		if isExist {
+			return candidate
+		}
+	}
+	log.Fatalf("service proto not found in %v, add it by 'gofra service add' first! "+
+		"--path accepts the .proto file & --proto-include-path used by add, or the path relative to %v, proto file:%v",
+		layout.GetAPIProtobufBasePath(), layout.GetAPIProtobufBasePath(), protoFilePath)
+	return ""
+}
+
 var (
-	protoFilePath string
+	protoFilePaths []string
+	protoFilePath  string
+	keepHandlers   bool
+	fixHandlers    bool
+	breakingCheck  bool
+	allowBreaking  bool
+	httpGateway    bool
 )
 
 func init() {
 	rootCmd.AddCommand(serviceCmd)
 	serviceCmd.AddCommand(addServiceCmd)
 	serviceCmd.AddCommand(updateServiceCmd)
+	serviceCmd.AddCommand(removeServiceCmd)
 
 	// Here you will define your flags and configuration settings.
 
@@ -135,12 +289,20 @@ func init() {
 		"output-path", filepath.Join("."), "output path, default is '.'")
 	addServiceCmd.PersistentFlags().BoolVar(&override,
 		"override", false, "If override when file exists")
-	addServiceCmd.PersistentFlags().StringVar(&protoFilePath,
-		"path", "", "A .proto file to generate codes")
+	addServiceCmd.PersistentFlags().StringArrayVar(&protoFilePaths,
+		"path", []string{}, "A .proto file or a directory of .proto files to generate codes, can be specified multiple times")
+	addServiceCmd.PersistentFlags().StringVar(&protoTool,
+		"proto-tool", "protoc", "tool used to compile .proto files, protoc or native or buf")
 	addServiceCmd.PersistentFlags().StringVar(&protocPath,
 		"protoc-path", "protoc", "protoc binary path, in case user has multi versions of protoc")
+	addServiceCmd.PersistentFlags().StringVar(&bufPath,
+		"buf-path", "buf", "buf binary path, used when proto tool is buf")
 	addServiceCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
 		"proto-include-path", []string{}, "proto files include path used by protoc's command '--proto_path'")
+	addServiceCmd.PersistentFlags().BoolVar(&httpGateway,
+		"http-gateway", false, "Generate grpc-gateway handlers & OpenAPI specs for RPCs with google.api.http annotations, protoc-gen-grpc-gateway & protoc-gen-openapiv2 are needed in PATH even for native")
+	addServiceCmd.PersistentFlags().BoolVar(&breakingCheck,
+		"breaking-check", false, "If check breaking changes against api/protobuf_spec/descriptor_set.bin, only for buf")
 
 	updateServiceCmd.PersistentFlags().StringVar(&outputPath,
 		"output-path", filepath.Join("."), "output path, default is '.'")
@@ -148,10 +310,31 @@ func init() {
 		"override", false, "If override when file exists")
 	updateServiceCmd.PersistentFlags().StringVar(&protoFilePath,
 		"path", "", "A .proto file to generate codes")
+	updateServiceCmd.PersistentFlags().StringVar(&protoTool,
+		"proto-tool", "protoc", "tool used to compile .proto files, protoc or native or buf")
 	updateServiceCmd.PersistentFlags().StringVar(&protocPath,
 		"protoc-path", "protoc", "protoc binary path, in case user has multi versions of protoc")
+	updateServiceCmd.PersistentFlags().StringVar(&bufPath,
+		"buf-path", "buf", "buf binary path, used when proto tool is buf")
 	updateServiceCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
 		"proto-include-path", []string{}, "proto files path include used by protoc's command '--proto_path'")
+	updateServiceCmd.PersistentFlags().BoolVar(&httpGateway,
+		"http-gateway", false, "Generate grpc-gateway handlers & OpenAPI specs for RPCs with google.api.http annotations, default is the one of project manifest")
+	updateServiceCmd.PersistentFlags().BoolVar(&breakingCheck,
+		"breaking-check", false, "If check breaking changes against api/protobuf_spec/descriptor_set.bin, only for buf")
+	updateServiceCmd.PersistentFlags().BoolVar(&allowBreaking,
+		"allow-breaking", false, "If update even though wire breaking changes are found")
+	updateServiceCmd.PersistentFlags().BoolVar(&fixHandlers,
+		"fix-handlers", false, "If move orphan handlers to _deprecated.go, rename renamed handlers, rewrite changed handler signatures and regenerate handlers whose streaming modes changed")
+
+	removeServiceCmd.PersistentFlags().StringVar(&outputPath,
+		"output-path", filepath.Join("."), "output path, default is '.'")
+	removeServiceCmd.PersistentFlags().StringVar(&protoFilePath,
+		"path", "", "A .proto file which was added to project, the same as add or relative to api/protobuf_spec")
+	removeServiceCmd.PersistentFlags().BoolVar(&keepHandlers,
+		"keep-handlers", false, "If keep the service handlers which contain business codes")
+	removeServiceCmd.PersistentFlags().StringArrayVar(&protoFileIncludePath,
+		"proto-include-path", []string{}, "proto files include path used by add and to parse the imports of proto file")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -0,0 +1,64 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// templatesCmd represents the templates command
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Template operations [export]",
+	Long: `Gofra is a framework using gRPC as the communication layer.
+templates command will help to customize the templates of generated files,
+templates in '--template-dir' (or 'template_dir' in ~/.gofra.yaml) take precedence over the built-in ones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+// exportTemplatesCmd represents the templates export command
+var exportTemplatesCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export built-in templates to directory",
+	Long: `Gofra is a framework using gRPC as the communication layer.
+templates export command will help to dump the built-in templates as the starting point of customization,
+edit them and pass the directory by '--template-dir' to generate files with them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Info("====== gofra templates export ======")
+
+		if err := templates.ExportBuiltins(templatesOutputPath, override); err != nil {
+			log.Fatalf("templates.ExportBuiltins failed! error:%+v", err)
+		}
+		log.Infof("templates exported to %v", templatesOutputPath)
+	},
+}
+
+var templatesOutputPath string
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(exportTemplatesCmd)
+
+	exportTemplatesCmd.PersistentFlags().StringVar(&templatesOutputPath,
+		"output-path", filepath.Join(".", "templates"), "output path of templates, default is './templates'")
+	exportTemplatesCmd.PersistentFlags().BoolVar(&override,
+		"override", false, "If override when file exists")
+}
@@ -7,6 +7,7 @@ require (
 	github.com/cihub/seelog v0.0.0-20170130134532-f561c5e57575
 	github.com/codahale/hdrhistogram v0.0.0-20161010025455-3a0bb77429bd // indirect
 	github.com/gin-gonic/gin v1.5.0
+	github.com/golang/protobuf v1.4.2
 	github.com/grpc-ecosystem/go-grpc-middleware v1.0.0
 	github.com/iancoleman/strcase v0.1.3
 	github.com/jhump/protoreflect v1.8.2
@@ -14,6 +15,7 @@ require (
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/openzipkin-contrib/zipkin-go-opentracing v0.4.5
 	github.com/openzipkin/zipkin-go v0.2.1
+	github.com/prometheus/client_golang v1.7.1
 	github.com/silenceper/pool v0.0.0-20200216122550-efd46f34321f
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
@@ -24,5 +26,12 @@ require (
 	golang.org/x/net v0.0.0-20200625001655-4c5254603344
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1
 	google.golang.org/grpc v1.27.0
+	google.golang.org/protobuf v1.25.1-0.20200805231151-a709e31e5d12
 	gopkg.in/alexcesaro/statsd.v2 v2.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.5
 )
+
+// google.golang.org/protobuf is pinned since internal/pkg/pb imports cmd/protoc-gen-go/internal_gengo, which has no
+// compatibility promise, and grpc_gen.go is a port of protoc-gen-go-grpc built on compiler/protogen of this version.
+// Upgrade it together with the golden files: go test ./internal/pkg/pb -run TestNativeCompilerGolden -update
+replace google.golang.org/protobuf => google.golang.org/protobuf v1.25.1-0.20200805231151-a709e31e5d12
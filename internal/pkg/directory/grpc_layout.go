@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
-	"github.com/DarkMetrix/gofra/pkg/utils"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
 	"github.com/iancoleman/strcase"
 	"golang.org/x/xerrors"
 )
@@ -32,14 +32,20 @@ The layout looks like below
 ├── go.mod
 ├── go.sum
 ├── gofra
-└── internal
-      ├── config
-      │     └── config.go
-      └── service
-          └── grpc
-              └── health_check
-                  ├── health_check.go
-                  └── implementation.go
+├── internal
+│     ├── config
+│     │     └── config.go
+│     └── service
+│         └── grpc
+│             └── health_check
+│                 ├── health_check.go
+│                 ├── health_check_test.go
+│                 ├── implementation.go
+│                 └── implementation_test.go
+└── pkg
+      └── client
+          └── health_check
+              └── client.go
  *********************************************************************************/
 
 // GRPCServiceLayout defines the layout interface of a gRPC service without
@@ -48,12 +54,24 @@ type GRPCServiceLayout interface {
 
 	// GetMainFilePath returns the main.go file path, e.g.: /.../cmd/main.go
 	GetMainFilePath() string
+	// GetGatewayFilePath returns the http gateway file path, e.g.: /.../cmd/gateway.go
+	GetGatewayFilePath() string
 	// GetAPIProtobufBasePath returns the API protobuf root directory path, e.g.: /.../api/protobuf_spec
 	GetAPIProtobufBasePath() string
 	// GetAPIProtobufPath returns the specific API directory path, e.g.: /.../api/protobuf_spec/health_check
 	GetAPIProtobufPath(protoPath string) string
 	// GetAPIProtobufFilePath returns the specific API file path, e.g.: /.../api/protobuf_spec/health_check/health_check.proto
 	GetAPIProtobufFilePath(protoFile string) string
+	// GetAPIProtobufBufFilePath returns the buf.yaml file path, e.g.: /.../api/protobuf_spec/buf.yaml
+	GetAPIProtobufBufFilePath() string
+	// GetAPIProtobufBufGenFilePath returns the buf.gen.yaml file path, e.g.: /.../api/protobuf_spec/buf.gen.yaml
+	GetAPIProtobufBufGenFilePath() string
+	// GetAPIProtobufDescriptorSetFilePath returns the descriptor set file path, e.g.: /.../api/protobuf_spec/descriptor_set.bin
+	GetAPIProtobufDescriptorSetFilePath() string
+	// GetAPIDocsBasePath returns the API documents directory path, e.g.: /.../api/docs
+	GetAPIDocsBasePath() string
+	// GetAPIDocsFilePath returns the specific service API document file path, e.g.: /.../api/docs/health_check.md
+	GetAPIDocsFilePath(service, ext string) string
 
 	// GetGRPCServiceBasePath returns the gRPC gateway directory path, e.g.: /.../internal/service/grpc
 	GetGRPCServiceBasePath() string
@@ -63,6 +81,30 @@ type GRPCServiceLayout interface {
 	GetGRPCServiceFilePath(service string) string
 	// GetGRPCRPCFilePath returns the specific gRPC gateway RPC file path, e.g.: /.../internal/service/grpc/health_check/check.go
 	GetGRPCRPCFilePath(service, RPC string) string
+	// GetGRPCServiceTestFilePath returns the specific gRPC service test helper file path, e.g.: /.../internal/service/grpc/health_check/implementation_test.go
+	GetGRPCServiceTestFilePath(service string) string
+	// GetGRPCRPCTestFilePath returns the specific gRPC RPC test file path, e.g.: /.../internal/service/grpc/health_check/check_test.go
+	GetGRPCRPCTestFilePath(service, RPC string) string
+
+	// GetClientBasePath returns the client SDK directory path, e.g.: /.../pkg/client
+	GetClientBasePath() string
+	// GetClientPath returns the specific client SDK directory path, e.g.: /.../pkg/client/health_check
+	GetClientPath(service string) string
+	// GetClientFilePath returns the specific client SDK file path, e.g.: /.../pkg/client/health_check/client.go
+	GetClientFilePath(service string) string
+
+	// GetMockBasePath returns the mock server directory path, e.g.: /.../pkg/mock
+	GetMockBasePath() string
+	// GetMockPath returns the specific mock server directory path, e.g.: /.../pkg/mock/health_check
+	GetMockPath(service string) string
+	// GetMockFilePath returns the specific mock server file path, e.g.: /.../pkg/mock/health_check/mock.go
+	GetMockFilePath(service string) string
+	// GetMockMainFilePath returns the specific mock server main file path, e.g.: /.../pkg/mock/health_check/cmd/main.go
+	GetMockMainFilePath(service string) string
+	// GetMockFixtureFilePath returns the specific mock server fixture file path, e.g.: /.../pkg/mock/health_check/fixtures.yaml
+	GetMockFixtureFilePath(service string) string
+	// GetMockPackagePath returns the package path of specific mock server, e.g.: github.com/foo/bar/pkg/mock/health_check
+	GetMockPackagePath(goModule, service string) string
 
 	// GetConfigGoBasePath returns the config path, e.g.: /.../internal/config
 	GetConfigGoBasePath() string
@@ -75,16 +117,21 @@ type GRPCServiceLayout interface {
 
 	// GetPackageAlias returns the alias of specific package name
 	GetPackageAlias(packageName string) string
+	// GetProtoPackageAlias returns the default alias of specific go package name of proto, e.g.: userProto
+	GetProtoPackageAlias(packageName string) string
 	// GetServicePackagePath returns the package path of specific service
 	GetServicePackagePath(goModule, service string) string
 	// GetProtoPackagePath returns the package path of specific proto file
 	GetProtoPackagePath(goModule, protoFile string) string
-	// GetServiceRegisterStub returns the stub of specific service of proto
-	GetServiceRegisterStub(protoFile, serviceName string) string
+	// GetServiceRegisterStub returns the stub of specific service of proto package imported as the alias
+	GetServiceRegisterStub(protoAlias, serviceName string) string
 	// GetServiceImportStub returns the service import line of code
 	GetServiceImportStub(goModule, service string) string
-	// GetProtoImportStub returns the proto import line of code
-	GetProtoImportStub(goModule, protoPath string) string
+	// GetProtoImportStub returns the proto package import line of code
+	GetProtoImportStub(protoAlias, packagePath string) string
+	// GetGatewayRegisterStub returns the http gateway handler register of specific service of proto package
+	// imported as the alias
+	GetGatewayRegisterStub(protoAlias, serviceName string) string
 }
 
 // GRPCLayout implements Layout interface to generate gRPC service directory structure
@@ -115,12 +162,12 @@ func (layout *GRPCLayout) Save() error {
 	}
 
 	// create proto base & service base directories
-	if err := utils.CreatePaths(
+	if err := fsutil.CreatePaths(
 		layout.Options.Override,
 		layout.GetAPIProtobufBasePath(),
 		layout.GetGRPCServiceBasePath(),
 		layout.GetConfigGoBasePath()); err != nil {
-		return xerrors.Errorf("utils.CreatePaths failed! "+
+		return xerrors.Errorf("fsutil.CreatePaths failed! "+
 			"api base:%v, service base:%v, error:%w", layout.GetAPIProtobufBasePath(), layout.GetServiceBasePath(), err)
 	}
 	return nil
@@ -131,6 +178,11 @@ func (layout *GRPCLayout) GetMainFilePath() string {
 	return filepath.Join(layout.GetCommandBasePath(), "main.go")
 }
 
+// GetGatewayFilePath returns the http gateway file path next to main.go
+func (layout *GRPCLayout) GetGatewayFilePath() string {
+	return filepath.Join(layout.GetCommandBasePath(), "gateway.go")
+}
+
 // GetAPIProtobufBasePath returns the API protobuf base path
 func (layout *GRPCLayout) GetAPIProtobufBasePath() string {
 	return filepath.Join(layout.GetAPIBasePath(), "protobuf_spec")
@@ -138,16 +190,47 @@ func (layout *GRPCLayout) GetAPIProtobufBasePath() string {
 
 // GetAPIProtobufPath returns the the API protobuf path connected with protobuf file path
 func (layout *GRPCLayout) GetAPIProtobufPath(protoFile string) string {
-	filename := strings.TrimSuffix(protoFile, filepath.Ext(protoFile))
-	return filepath.Join(layout.GetAPIProtobufBasePath(), strcase.ToSnake(filename))
+	return filepath.Dir(layout.GetAPIProtobufFilePath(protoFile))
 }
 
-// GetAPIProtobufFilePath returns the the API protobuf path connected with protobuf file path and protobuf file name
+// GetAPIProtobufFilePath returns the the API protobuf path connected with protobuf file path and protobuf file name,
+// a proto file name without directory is put into the directory named after it, e.g.: foo.proto -> foo/foo.proto,
+// otherwise the relative path is kept so that the imports still work, e.g.: order/order.proto -> order/order.proto
 func (layout *GRPCLayout) GetAPIProtobufFilePath(protoFile string) string {
+	protoFile = filepath.Clean(protoFile)
+	if filepath.Dir(protoFile) != "." {
+		return filepath.Join(layout.GetAPIProtobufBasePath(), protoFile)
+	}
+
 	filename := strings.TrimSuffix(protoFile, filepath.Ext(protoFile))
 	return filepath.Join(layout.GetAPIProtobufBasePath(), strcase.ToSnake(filename), protoFile)
 }
 
+// GetAPIProtobufBufFilePath returns the buf.yaml file path
+func (layout *GRPCLayout) GetAPIProtobufBufFilePath() string {
+	return filepath.Join(layout.GetAPIProtobufBasePath(), "buf.yaml")
+}
+
+// GetAPIProtobufBufGenFilePath returns the buf.gen.yaml file path
+func (layout *GRPCLayout) GetAPIProtobufBufGenFilePath() string {
+	return filepath.Join(layout.GetAPIProtobufBasePath(), "buf.gen.yaml")
+}
+
+// GetAPIProtobufDescriptorSetFilePath returns the descriptor set file path built by buf
+func (layout *GRPCLayout) GetAPIProtobufDescriptorSetFilePath() string {
+	return filepath.Join(layout.GetAPIProtobufBasePath(), "descriptor_set.bin")
+}
+
+// GetAPIDocsBasePath returns the API documents base path
+func (layout *GRPCLayout) GetAPIDocsBasePath() string {
+	return filepath.Join(layout.GetAPIBasePath(), "docs")
+}
+
+// GetAPIDocsFilePath returns the API document file path of service with the extension of format, e.g.: .md, .html
+func (layout *GRPCLayout) GetAPIDocsFilePath(service, ext string) string {
+	return filepath.Join(layout.GetAPIDocsBasePath(), strcase.ToSnake(service)+ext)
+}
+
 // GetGRPCServiceBasePath returns the gateway gRPC base path
 func (layout *GRPCLayout) GetGRPCServiceBasePath() string {
 	return filepath.Join(layout.GetServiceBasePath(), "grpc")
@@ -168,6 +251,62 @@ func (layout *GRPCLayout) GetGRPCRPCFilePath(service, RPC string) string {
 	return filepath.Join(layout.GetGRPCServiceBasePath(), strcase.ToSnake(service), strcase.ToSnake(RPC+".go"))
 }
 
+// GetGRPCServiceTestFilePath returns the test helper file path of service, it's shared by the RPC tests
+func (layout *GRPCLayout) GetGRPCServiceTestFilePath(service string) string {
+	return filepath.Join(layout.GetGRPCServiceBasePath(), strcase.ToSnake(service), "implementation_test.go")
+}
+
+// GetGRPCRPCTestFilePath returns the test file path of RPC, it's next to the RPC file
+func (layout *GRPCLayout) GetGRPCRPCTestFilePath(service, RPC string) string {
+	return filepath.Join(layout.GetGRPCServiceBasePath(), strcase.ToSnake(service), strcase.ToSnake(RPC)+"_test.go")
+}
+
+// GetClientBasePath returns the client SDK base path, it's public so that other projects could import it
+func (layout *GRPCLayout) GetClientBasePath() string {
+	return filepath.Join(layout.GetOutputPath(), "pkg", "client")
+}
+
+// GetClientPath returns the client SDK path of service
+func (layout *GRPCLayout) GetClientPath(service string) string {
+	return filepath.Join(layout.GetClientBasePath(), strcase.ToSnake(service))
+}
+
+// GetClientFilePath returns the client SDK file path of service
+func (layout *GRPCLayout) GetClientFilePath(service string) string {
+	return filepath.Join(layout.GetClientPath(service), "client.go")
+}
+
+// GetMockBasePath returns the mock server base path, it's public so that the consumers could import it
+func (layout *GRPCLayout) GetMockBasePath() string {
+	return filepath.Join(layout.GetOutputPath(), "pkg", "mock")
+}
+
+// GetMockPath returns the mock server path of service
+func (layout *GRPCLayout) GetMockPath(service string) string {
+	return filepath.Join(layout.GetMockBasePath(), strcase.ToSnake(service))
+}
+
+// GetMockFilePath returns the mock server file path of service
+func (layout *GRPCLayout) GetMockFilePath(service string) string {
+	return filepath.Join(layout.GetMockPath(service), "mock.go")
+}
+
+// GetMockMainFilePath returns the main file path of mock server, it's run by 'go run'
+func (layout *GRPCLayout) GetMockMainFilePath(service string) string {
+	return filepath.Join(layout.GetMockPath(service), "cmd", "main.go")
+}
+
+// GetMockFixtureFilePath returns the example fixture file path of mock server
+func (layout *GRPCLayout) GetMockFixtureFilePath(service string) string {
+	return filepath.Join(layout.GetMockPath(service), "fixtures.yaml")
+}
+
+// GetMockPackagePath returns the mock server package path of service
+func (layout *GRPCLayout) GetMockPackagePath(goModule, service string) string {
+	relativePath := strings.TrimPrefix(layout.GetMockPath(service), layout.GetOutputPath())
+	return filepath.Join(goModule, relativePath)
+}
+
 // GetConfigGoBasePath returns the config base path
 func (layout *GRPCLayout) GetConfigGoBasePath() string {
 	return filepath.Join(layout.GetInternalBasePath(), "config")
@@ -194,6 +333,11 @@ func (layout *GRPCLayout) GetPackageAlias(packageName string) string {
 	return strcase.ToLowerCamel(packageName)
 }
 
+// GetProtoPackageAlias returns the default alias of go package of proto, the package name is suffixed with Proto
+func (layout *GRPCLayout) GetProtoPackageAlias(packageName string) string {
+	return layout.GetPackageAlias(packageName) + "Proto"
+}
+
 // GetServicePackagePath returns the import package path according to go module path and service name
 func (layout *GRPCLayout) GetServicePackagePath(goModule, service string) string {
 	// generate
@@ -201,24 +345,30 @@ func (layout *GRPCLayout) GetServicePackagePath(goModule, service string) string
 	return filepath.Join(goModule, relativePath, strcase.ToSnake(service))
 }
 
-// GetProtoPackagePath returns the import protobuf package path according to go module path and proto file path
+// GetProtoPackagePath returns the import protobuf package path according to go module path and proto file path,
+// the package is the directory of proto file in api/protobuf_spec
 func (layout *GRPCLayout) GetProtoPackagePath(goModule, protoFile string) string {
+	relativePath := strings.TrimPrefix(layout.GetAPIProtobufBasePath(), layout.GetOutputPath())
+	if protoDir, err := filepath.Rel(layout.GetAPIProtobufBasePath(), filepath.Dir(protoFile)); err == nil &&
+		protoDir != ".." && !strings.HasPrefix(protoDir, ".."+string(filepath.Separator)) {
+		return filepath.Join(goModule, relativePath, protoDir)
+	}
+
+	// proto file name without directory
 	fileName := strings.TrimSuffix(filepath.Base(protoFile), filepath.Ext(protoFile))
 
 	// generate proto stub
 	// something like: healthCheck "git.code.oa.com/foo/api/protobuf-spec/health-check"
-	relativePath := strings.TrimSuffix(layout.GetAPIProtobufBasePath(), layout.GetOutputPath())
 	return filepath.Join(goModule, relativePath, strcase.ToSnake(fileName))
 }
 
-// GetServiceRegisterStub returns the service register line of code
-func (layout *GRPCLayout) GetServiceRegisterStub(protoPath, serviceName string) string {
-	fileName := strings.TrimSuffix(filepath.Base(protoPath), filepath.Ext(protoPath))
-
+// GetServiceRegisterStub returns the service register line of code, the alias is the one go package of proto
+// is imported as
+func (layout *GRPCLayout) GetServiceRegisterStub(protoAlias, serviceName string) string {
 	// generate register stub
 	// something like: healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation)
 	return fmt.Sprintf("%v.Register%vServer(server, %v.Implementation{})",
-		layout.GetPackageAlias(fileName)+"Proto", serviceName, strcase.ToLowerCamel(serviceName))
+		protoAlias, serviceName, strcase.ToLowerCamel(serviceName))
 }
 
 // GetServiceImportStub returns the service import line of code
@@ -227,9 +377,14 @@ func (layout *GRPCLayout) GetServiceImportStub(goModule, service string) string
 		layout.GetPackageAlias(service), layout.GetServicePackagePath(goModule, service))
 }
 
-// GetProtoImportStub returns the proto import line of code
-func (layout *GRPCLayout) GetProtoImportStub(goModule, protoPath string) string {
-	fileName := strings.TrimSuffix(filepath.Base(protoPath), filepath.Ext(protoPath))
-	return fmt.Sprintf("%v \"%v\"",
-		layout.GetPackageAlias(fileName)+"Proto", layout.GetProtoPackagePath(goModule, protoPath))
+// GetProtoImportStub returns the proto import line of code according to the alias & import path of proto
+func (layout *GRPCLayout) GetProtoImportStub(protoAlias, packagePath string) string {
+	return fmt.Sprintf("%v \"%v\"", protoAlias, packagePath)
+}
+
+// GetGatewayRegisterStub returns the http gateway handler register element of code
+func (layout *GRPCLayout) GetGatewayRegisterStub(protoAlias, serviceName string) string {
+	// generate gateway register stub
+	// something like: healthCheckProto.RegisterHealthCheckHandlerFromEndpoint,
+	return fmt.Sprintf("%v.Register%vHandlerFromEndpoint,", protoAlias, serviceName)
 }
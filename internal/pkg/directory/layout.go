@@ -5,7 +5,7 @@ import (
 	"path/filepath"
 
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
-	"github.com/DarkMetrix/gofra/pkg/utils"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
 	"golang.org/x/xerrors"
 )
 
@@ -86,12 +86,12 @@ func (layout *BasicServiceLayout) String() string {
 // Save creates all the generated directory structure
 func (layout *BasicServiceLayout) Save() error {
 	// create output directory in case it doesn't exist
-	if err := utils.CreatePaths(layout.Options.Override, layout.OutputPath); err != nil {
-		return xerrors.Errorf("utils.CreatePaths failed! output path:%v, error:%w", layout.OutputPath, err)
+	if err := fsutil.CreatePaths(layout.Options.Override, layout.OutputPath); err != nil {
+		return xerrors.Errorf("fsutil.CreatePaths failed! output path:%v, error:%w", layout.OutputPath, err)
 	}
 
 	// create directories
-	if err := utils.CreatePaths(
+	if err := fsutil.CreatePaths(
 		layout.Options.Override,
 		layout.APIBasePath,
 		layout.CommandBasePath,
@@ -100,7 +100,7 @@ func (layout *BasicServiceLayout) Save() error {
 		layout.ConfigBasePath,
 		layout.BuildBasePath,
 		layout.DeployBasePath); err != nil {
-		return xerrors.Errorf("utils.CreatePaths failed! layout:%v, error:%v", layout.String(), err)
+		return xerrors.Errorf("fsutil.CreatePaths failed! layout:%v, error:%v", layout.String(), err)
 	}
 	return nil
 }
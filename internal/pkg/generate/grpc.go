@@ -1,20 +1,23 @@
 package generate
 
 import (
-	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/manifest"
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
 	"github.com/DarkMetrix/gofra/internal/pkg/pb"
 	"github.com/DarkMetrix/gofra/internal/pkg/templates/general"
 	"github.com/DarkMetrix/gofra/internal/pkg/templates/grpc"
-	"github.com/DarkMetrix/gofra/pkg/utils"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/iancoleman/strcase"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/desc/protoparse"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
 )
 
@@ -23,6 +26,13 @@ type ProtobufServiceGenerator interface {
 	Init(layout directory.GRPCServiceLayout, opts ...option.Option) error
 	Add(protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) error
 	Update(protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) error
+	Remove(protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) error
+	Regenerate(layout directory.GRPCServiceLayout, opts ...option.Option) ([]*RegenerateResult, error)
+	CheckBreakingChanges(protoPath, newProtoPath string,
+		layout directory.GRPCServiceLayout, opts ...option.Option) ([]*pb.BreakingChange, error)
+	InitHTTPGateway(layout directory.GRPCServiceLayout, opts ...option.Option) error
+	GenerateDocs(layout directory.GRPCServiceLayout, opts ...option.Option) ([]string, error)
+	Mock(protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) ([]string, error)
 }
 
 // GRPCServiceGenerator definition
@@ -33,7 +43,9 @@ func NewGRPCServiceGenerator() *GRPCServiceGenerator {
 	return &GRPCServiceGenerator{}
 }
 
-// Init initializes all the files needed for a basic gRPC service with health check service
+// Init initializes all the files needed for a basic gRPC service with health check service,
+// the standard grpc.health.v1.Health service is registered by main.go for every service added,
+// the generator state is recorded by project manifest (.gofra.json) which is updated by Add, Update & Remove
 func (gen *GRPCServiceGenerator) Init(layout directory.GRPCServiceLayout, opts ...option.Option) error {
 	// initialize directory structure
 	if err := layout.Save(); err != nil {
@@ -47,7 +59,10 @@ func (gen *GRPCServiceGenerator) Init(layout directory.GRPCServiceLayout, opts .
 
 	// create main file
 	options := option.NewOptions(opts...)
-	opts = append(opts, option.WithConfigPackagePath(layout.GetConfigPackageName(options.GoModule)))
+	opts = append(opts,
+		option.WithConfigPackagePath(layout.GetConfigPackageName(options.GoModule)),
+		option.WithProject(path.Base(options.GoModule)),
+	)
 	if err := grpc.NewMainInfo(opts...).RenderFile(layout.GetMainFilePath()); err != nil {
 		return xerrors.Errorf("create main file failed! error:%w", err)
 	}
@@ -62,8 +77,33 @@ func (gen *GRPCServiceGenerator) Init(layout directory.GRPCServiceLayout, opts .
 		return xerrors.Errorf("create config YAML file failed! error:%w", err)
 	}
 
+	// create project manifest
+	projectManifest := &manifest.Manifest{
+		GoModule:             options.GoModule,
+		GoVersion:            options.GoVersion,
+		Project:              path.Base(options.GoModule),
+		ProtoTool:            options.ProtoTool,
+		ProtocPath:           options.ProtocPath,
+		ProtoFileIncludePath: options.ProtoFileIncludePath,
+		BufPath:              options.BufPath,
+		Protos:               []*manifest.Proto{},
+	}
+	if err := projectManifest.Save(options.OutputPath); err != nil {
+		return xerrors.Errorf("create project manifest failed! error:%w", err)
+	}
+
+	// create buf.yaml & buf.gen.yaml, api/protobuf_spec is the buf module
+	if options.ProtoTool == pb.ProtoToolBuf {
+		if err := grpc.NewBufYAMLInfo(opts...).RenderFile(layout.GetAPIProtobufBufFilePath()); err != nil {
+			return xerrors.Errorf("create buf.yaml file failed! error:%w", err)
+		}
+		if err := grpc.NewBufGenYAMLInfo(opts...).RenderFile(layout.GetAPIProtobufBufGenFilePath()); err != nil {
+			return xerrors.Errorf("create buf.gen.yaml file failed! error:%w", err)
+		}
+	}
+
 	// create health check proto file
-	if err := utils.CreatePaths(true, filepath.Join(layout.GetAPIProtobufBasePath(), "health_check")); err != nil {
+	if err := fsutil.CreatePaths(true, filepath.Join(layout.GetAPIProtobufBasePath(), "health_check")); err != nil {
 		return xerrors.Errorf("create health check directory failed! error:%w", err)
 	}
 	healthCheckProtoPath := filepath.Join(layout.GetAPIProtobufBasePath(), "health_check", "health_check.proto")
@@ -92,170 +132,438 @@ func (gen *GRPCServiceGenerator) Update(
 	return generateGRPCService(protoPath, layout, opts...)
 }
 
+// Remove removes a gRPC service according to proto file, it reverses what Add did
+func (gen *GRPCServiceGenerator) Remove(
+	protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	return removeGRPCService(protoPath, layout, opts...)
+}
+
 // generateGRPCService generates a gRPC service according to proto file
 func generateGRPCService(protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) error {
 	options := option.NewOptions(opts...)
 
-	// compile gRPC service
-	protoFileIncludePath := append(options.ProtoFileIncludePath, options.OutputPath)
-	if err := pb.CompileGRPC(options.ProtocPath, protoPath, protoFileIncludePath); err != nil {
-		return xerrors.Errorf("pb.CompileGRPC failed! error:%w", err)
+	// parse .proto file, the go package of the generated files is needed to compile
+	protoFileIncludePath := getProtoFileIncludePath(layout, options)
+	fileDescs, err := parseProtoFile(protoPath, protoFileIncludePath)
+	if err != nil {
+		return xerrors.Errorf("parseProtoFile failed! error:%w", err)
+	}
+	goPackage := getProtoGoPackage(protoPath, fileDescs[0], layout, options)
+
+	// the http gateway is generated for services with google.api.http annotations
+	gatewayServices := make([]string, 0)
+	if options.HTTPGateway {
+		for _, serviceDesc := range fileDescs[0].GetServices() {
+			if pb.HasHTTPRules(serviceDesc) {
+				gatewayServices = append(gatewayServices, serviceDesc.GetName())
+			}
+		}
 	}
 
-	// parse .proto file
-	parser := protoparse.Parser{
-		ImportPaths: protoFileIncludePath,
+	// parse handlers existed before update, the stream types are parsed from the code generated before
+	existingHandlers := make(map[string][]*handlerMethod)
+	// service name -> RPCs whose stubs are not generated, the handlers renamed to them are kept in the files named
+	// after the old RPCs until they're fixed, so the stubs would be generated next to them
+	skippedRPCs := make(map[string]map[string]bool)
+	if options.IgnoreExist {
+		for _, fileDesc := range fileDescs {
+			for _, serviceDesc := range fileDesc.GetServices() {
+				existingHandlers[serviceDesc.GetName()], err = parseServiceHandlers(
+					layout.GetGRPCServicePath(serviceDesc.GetName()), goPackage.Dir)
+				if err != nil {
+					return xerrors.Errorf("parseServiceHandlers failed! error:%w", err)
+				}
+
+				if !options.FixHandlers {
+					skippedRPCs[serviceDesc.GetName()] = getRenamedRPCs(goPackage, serviceDesc,
+						existingHandlers[serviceDesc.GetName()], layout, options)
+				}
+			}
+		}
 	}
-	fileDescs, err := parser.ParseFiles(protoPath)
+
+	// compile gRPC service, protoc & buf write files directly so it's skipped in dry-run mode
+	compiler, err := pb.NewCompiler(&pb.CompilerConfig{
+		ProtoTool:         options.ProtoTool,
+		ProtocPath:        options.ProtocPath,
+		BufPath:           options.BufPath,
+		WorkDir:           options.OutputPath,
+		GoModule:          goPackage.GoModule,
+		HTTPGateway:       len(gatewayServices) != 0,
+		BufModulePath:     layout.GetAPIProtobufBasePath(),
+		DescriptorSetPath: layout.GetAPIProtobufDescriptorSetFilePath(),
+		BreakingCheck:     options.BreakingCheck,
+	})
 	if err != nil {
-		return xerrors.Errorf("Unable to parse proto file! proto file:%v, error:%w", protoPath, err)
+		return xerrors.Errorf("pb.NewCompiler failed! error:%w", err)
+	}
+	if _, isNative := compiler.(*pb.NativeCompiler); fsutil.IsDryRun() && !isNative {
+		log.Infof("dry-run: skip compiling %v", protoPath)
+	} else if err := compiler.CompileGRPC(protoPath, protoFileIncludePath); err != nil {
+		logCompileError(err)
+		return xerrors.Errorf("compiler.CompileGRPC failed! error:%w", err)
 	}
 
 	for _, fileDesc := range fileDescs {
 		for _, serviceDesc := range fileDesc.GetServices() {
 			// generate service related files
-			if err := generateServiceFiles(protoPath, true, serviceDesc, layout, opts...); err != nil {
+			if err := generateServiceFiles(goPackage, true, serviceDesc, skippedRPCs[serviceDesc.GetName()], layout,
+				opts...); err != nil {
 				return xerrors.Errorf("generateServiceFiles failed! error:%w", err)
 			}
 
+			// generate client SDK
+			if err := generateServiceClient(goPackage, serviceDesc, layout, opts...); err != nil {
+				return xerrors.Errorf("generateServiceClient failed! error:%w", err)
+			}
+
+			// keep the mock server generated by 'gofra mock' in sync
+			mockExist, err := fsutil.CheckPathExists(layout.GetMockFilePath(serviceDesc.GetName()))
+			if err != nil {
+				return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+			}
+			if mockExist {
+				if err := generateServiceMock(goPackage, serviceDesc, layout, opts...); err != nil {
+					return xerrors.Errorf("generateServiceMock failed! error:%w", err)
+				}
+			}
+
+			// report removed, renamed & signature changed handlers
+			if options.IgnoreExist {
+				if err := updateServiceHandlers(goPackage, serviceDesc, existingHandlers[serviceDesc.GetName()], layout, opts...); err != nil {
+					return xerrors.Errorf("updateServiceHandlers failed! error:%w", err)
+				}
+			}
+
 			// add service import to main.go
 			if err := addServiceImportToMain(layout, options.GoModule, serviceDesc.GetName()); err != nil {
 				return xerrors.Errorf("add service import to main.go failed! error:%w", err)
 			}
 
 			// add service register to main.go
-			if err := addServiceRegisterToMain(layout, serviceDesc.GetName(), protoPath); err != nil {
+			if err := addServiceRegisterToMain(layout, serviceDesc.GetName(), goPackage); err != nil {
 				return xerrors.Errorf("add service import to main.go failed! error:%w", err)
 			}
 		}
 	}
 
-	// add service proto import to application
-	if err := addServiceProtoImportToMain(layout, options.GoModule, protoPath); err != nil {
-		return xerrors.Errorf("add service proto import to main.go failed! error:%w", err)
+	// add service proto import to application, protos without service(e.g.: imported ones) are not registered
+	serviceNames := make([]string, 0)
+	for _, fileDesc := range fileDescs {
+		for _, serviceDesc := range fileDesc.GetServices() {
+			serviceNames = append(serviceNames, serviceDesc.GetName())
+		}
+	}
+	if len(serviceNames) != 0 {
+		if err := addServiceProtoImportToMain(layout, goPackage); err != nil {
+			return xerrors.Errorf("add service proto import to main.go failed! error:%w", err)
+		}
+	}
+
+	// add gateway handler registers to gateway.go
+	if len(gatewayServices) != 0 {
+		if err := initHTTPGateway(layout, opts...); err != nil {
+			return xerrors.Errorf("initHTTPGateway failed! error:%w", err)
+		}
+	}
+	for _, serviceName := range gatewayServices {
+		if err := addServiceGatewayToGateway(layout, serviceName, goPackage); err != nil {
+			return xerrors.Errorf("add service gateway to gateway.go failed! error:%w", err)
+		}
+	}
+
+	// record proto & services to project manifest
+	if err := updateManifest(protoPath, serviceNames, opts...); err != nil {
+		return xerrors.Errorf("updateManifest failed! error:%w", err)
 	}
 	return nil
 }
 
-// generateServiceFiles generates gRPC service related files
-func generateServiceFiles(protoPath string, update bool, serviceDesc *desc.ServiceDescriptor,
-	layout directory.GRPCServiceLayout, opts ...option.Option) error {
-	opts = append(opts, option.WithServiceName(serviceDesc.GetName()))
+// removeGRPCService removes a gRPC service according to proto file
+func removeGRPCService(protoPath string, layout directory.GRPCServiceLayout, opts ...option.Option) error {
 	options := option.NewOptions(opts...)
-	serviceInfo := grpc.NewServiceInfo(opts...)
 
-	// create path
-	handlerPath := layout.GetGRPCServicePath(serviceInfo.ServiceName)
-	if err := utils.CreatePath(handlerPath, options.Override); err != nil {
-		return xerrors.Errorf("create service path failed! error:%w", err)
+	// parse .proto file before it's removed
+	protoFileIncludePath := getProtoFileIncludePath(layout, options)
+	fileDescs, err := parseProtoFile(protoPath, protoFileIncludePath)
+	if err != nil {
+		return xerrors.Errorf("parseProtoFile failed! error:%w", err)
 	}
+	goPackage := getProtoGoPackage(protoPath, fileDescs[0], layout, options)
 
-	// create implementation file
-	opts = append(opts,
-		option.WithPackageName(serviceDesc.GetName()),
-		option.WithImportedPackageName(layout.GetProtoPackagePath(options.GoModule, protoPath)),
-	)
+	for _, fileDesc := range fileDescs {
+		for _, serviceDesc := range fileDesc.GetServices() {
+			// remove service register from main.go
+			if err := removeServiceRegisterFromMain(layout, serviceDesc.GetName(), goPackage); err != nil {
+				return xerrors.Errorf("remove service register from main.go failed! error:%w", err)
+			}
 
-	if err := grpc.NewServiceInfo(opts...).RenderFile(layout.GetGRPCServiceFilePath(serviceDesc.GetName())); err != nil {
-		return xerrors.Errorf("create service implementation file failed! error:%w", err)
+			// remove service import from main.go
+			if err := removeImportFromMain(layout, layout.GetServiceImportStub(options.GoModule, serviceDesc.GetName())); err != nil {
+				return xerrors.Errorf("remove service import from main.go failed! error:%w", err)
+			}
+
+			// remove client SDK, it's generated from proto only
+			if err := fsutil.RemoveAll(layout.GetClientPath(serviceDesc.GetName())); err != nil {
+				return xerrors.Errorf("remove client path failed! error:%w", err)
+			}
+
+			// remove mock server, it's generated from proto only
+			if err := fsutil.RemoveAll(layout.GetMockPath(serviceDesc.GetName())); err != nil {
+				return xerrors.Errorf("remove mock path failed! error:%w", err)
+			}
+
+			// remove service related files
+			if options.KeepHandlers {
+				continue
+			}
+			if err := fsutil.RemoveAll(layout.GetGRPCServicePath(serviceDesc.GetName())); err != nil {
+				return xerrors.Errorf("remove service path failed! error:%w", err)
+			}
+		}
 	}
 
-	// create RPC file
-	for _, rpcDesc := range serviceDesc.GetMethods() {
-		if err := generateRPCFiles(update, serviceDesc, rpcDesc, layout, opts...); err != nil {
-			return xerrors.Errorf("generateRPCFiles failed! error:%w", err)
+	// remove service proto import from main.go
+	alias, err := getProtoAlias(layout.GetMainFilePath(), layout, goPackage)
+	if err != nil {
+		return xerrors.Errorf("getProtoAlias failed! error:%w", err)
+	}
+	if err := removeImportFromMain(layout, layout.GetProtoImportStub(alias, goPackage.ImportPath)); err != nil {
+		return xerrors.Errorf("remove service proto import from main.go failed! error:%w", err)
+	}
+
+	// remove gateway handler registers & proto import from gateway.go
+	serviceNames := make([]string, 0)
+	for _, fileDesc := range fileDescs {
+		for _, serviceDesc := range fileDesc.GetServices() {
+			serviceNames = append(serviceNames, serviceDesc.GetName())
+		}
+	}
+	if err := removeServiceGatewayFromGateway(layout, serviceNames, goPackage); err != nil {
+		return xerrors.Errorf("remove service gateway from gateway.go failed! error:%w", err)
+	}
+
+	// remove proto & the generated .pb.go files
+	if err := removeProtoFiles(protoPath, goPackage.Dir, layout); err != nil {
+		return xerrors.Errorf("removeProtoFiles failed! error:%w", err)
+	}
+
+	// remove proto from project manifest
+	if err := updateManifest(protoPath, nil, opts...); err != nil {
+		return xerrors.Errorf("updateManifest failed! error:%w", err)
+	}
+	return nil
+}
+
+// removeProtoFiles removes proto & the generated .pb.go files in go package directory & the OpenAPI spec,
+// the directories are removed if nothing else is left
+func removeProtoFiles(protoPath, goPackageDir string, layout directory.GRPCServiceLayout) error {
+	prefix := filepath.Join(goPackageDir, strings.TrimSuffix(filepath.Base(protoPath), filepath.Ext(protoPath)))
+	filePaths := []string{protoPath, prefix + ".pb.go", prefix + "_grpc.pb.go", prefix + ".pb.gw.go",
+		strings.TrimSuffix(protoPath, filepath.Ext(protoPath)) + ".swagger.json"}
+	for _, filePath := range filePaths {
+		isExist, err := fsutil.CheckPathExists(filePath)
+		if err != nil {
+			return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
 		}
+		if !isExist {
+			continue
+		}
+		if err := fsutil.Remove(filePath); err != nil {
+			return xerrors.Errorf("fsutil.Remove failed! error:%w", err)
+		}
+	}
+
+	// the go package directory may be out of api/protobuf_spec, e.g.: api/gen/user, it's pruned up to the project
+	protobufBasePath := filepath.Clean(layout.GetAPIProtobufBasePath())
+	goPackageRootPath := protobufBasePath
+	if !isSubPath(protobufBasePath, goPackageDir) {
+		goPackageRootPath = filepath.Clean(layout.GetOutputPath())
+	}
+	if err := removeEmptyDir(filepath.Dir(protoPath), protobufBasePath); err != nil {
+		return xerrors.Errorf("removeEmptyDir failed! error:%w", err)
+	}
+	if err := removeEmptyDir(goPackageDir, goPackageRootPath); err != nil {
+		return xerrors.Errorf("removeEmptyDir failed! error:%w", err)
 	}
 	return nil
 }
 
-// generateRPCFiles generates gRPC RPC method related files
-func generateRPCFiles(update bool, serviceDesc *desc.ServiceDescriptor, rpcDesc *desc.MethodDescriptor,
-	layout directory.GRPCServiceLayout, opts ...option.Option) error {
-	// generate
-	opts = append(opts,
-		option.WithRPCName(rpcDesc.GetName()),
-		option.WithRequestName(rpcDesc.GetInputType().GetName()),
-		option.WithResponseName(rpcDesc.GetOutputType().GetName()),
-	)
+// isSubPath checks if path is in the directory of basePath, basePath itself is excluded
+func isSubPath(basePath, path string) bool {
+	relativePath, err := filepath.Rel(basePath, path)
+	return err == nil && relativePath != "." && relativePath != ".." &&
+		!strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
 
-	if err := grpc.NewRPCInfo(opts...).RenderFile(
-		layout.GetGRPCRPCFilePath(serviceDesc.GetName(), rpcDesc.GetName())); err != nil {
-		return xerrors.Errorf("create RPC implementation file failed! error:%w", err)
+// removeEmptyDir removes the directory & its parents in rootPath if nothing is left, rootPath itself is kept,
+// other protos may share the directory, e.g.: common/types.proto & common/money.proto
+func removeEmptyDir(dir, rootPath string) error {
+	for dir = filepath.Clean(dir); isSubPath(rootPath, dir); dir = filepath.Dir(dir) {
+		fileInfos, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return xerrors.Errorf("ioutil.ReadDir failed! error:%w", err)
+		}
+		for _, fileInfo := range fileInfos {
+			isExist, err := fsutil.CheckPathExists(filepath.Join(dir, fileInfo.Name()))
+			if err != nil {
+				return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+			}
+			if isExist {
+				return nil
+			}
+		}
+		if err := fsutil.RemoveAll(dir); err != nil {
+			return xerrors.Errorf("fsutil.RemoveAll failed! error:%w", err)
+		}
 	}
 	return nil
 }
 
-// addServiceImportToMain adds service import to main.go
-func addServiceImportToMain(layout directory.GRPCServiceLayout, goModule, serviceName string) error {
-	// read file content
-	mainFilePath := layout.GetMainFilePath()
-	mainContent, err := ioutil.ReadFile(mainFilePath)
+// updateManifest records proto & services to project manifest, the proto is removed if services is nil
+func updateManifest(protoPath string, services []string, opts ...option.Option) error {
+	options := option.NewOptions(opts...)
+
+	projectManifest, err := manifest.LoadOrNew(options.OutputPath)
 	if err != nil {
-		return xerrors.Errorf("ioutil.ReadFile failed! error:%w", err)
+		return xerrors.Errorf("manifest.LoadOrNew failed! error:%w", err)
 	}
 
-	// generate
-	protoImport := layout.GetServiceImportStub(goModule, serviceName)
-	protoImportStub := fmt.Sprintf("%v\r\n	/*@HANDLER_STUB*/", protoImport)
-	if strings.Contains(string(mainContent), protoImport) {
-		return nil
+	relativePath, err := filepath.Rel(options.OutputPath, protoPath)
+	if err != nil {
+		return xerrors.Errorf("filepath.Rel failed! error:%w", err)
+	}
+	if services == nil {
+		projectManifest.RemoveProto(relativePath)
+	} else {
+		projectManifest.AddProto(relativePath, services)
 	}
-	mainContent = []byte(strings.Replace(string(mainContent), "/*@HANDLER_STUB*/", protoImportStub, 1))
 
-	// write to file
-	if err := ioutil.WriteFile(mainFilePath, mainContent, os.ModePerm); err != nil {
-		return xerrors.Errorf("ioutil.WriteFile failed! error:%w", err)
+	// keep the module & protoc options used by the last successful generation
+	if projectManifest.GoModule == "" {
+		projectManifest.GoModule = options.GoModule
+		projectManifest.Project = path.Base(options.GoModule)
+	}
+	if options.ProtoTool != "" {
+		projectManifest.ProtoTool = options.ProtoTool
+	}
+	if options.ProtocPath != "" {
+		projectManifest.ProtocPath = options.ProtocPath
+	}
+	if options.BufPath != "" {
+		projectManifest.BufPath = options.BufPath
+	}
+	if options.HTTPGateway {
+		projectManifest.HTTPGateway = true
+	}
+	if options.ProtoFileIncludePath != nil {
+		projectManifest.ProtoFileIncludePath = options.ProtoFileIncludePath
+	}
+
+	if err := projectManifest.Save(options.OutputPath); err != nil {
+		return xerrors.Errorf("manifest.Save failed! error:%w", err)
 	}
 	return nil
 }
 
-// addServiceRegisterToMain adds service register to main.go
-func addServiceRegisterToMain(layout directory.GRPCServiceLayout, serviceName, protoPath string) error {
-	// read file content
-	mainFilePath := layout.GetMainFilePath()
-	mainContent, err := ioutil.ReadFile(mainFilePath)
+// logCompileError logs the diagnostics of compile error with source context line by line
+func logCompileError(err error) {
+	var compileErr *pb.CompileError
+	if !xerrors.As(err, &compileErr) {
+		return
+	}
+	for _, line := range strings.Split(compileErr.Render(), "\n") {
+		log.Error(line)
+	}
+}
+
+// getProtoFileIncludePath returns the include paths to compile & parse .proto files, api/protobuf_spec comes first
+// so that the protos are imported by the path relative to it, e.g.: import "common/types.proto";
+func getProtoFileIncludePath(layout directory.GRPCServiceLayout, options *option.Options) []string {
+	return append([]string{layout.GetAPIProtobufBasePath(), options.OutputPath}, options.ProtoFileIncludePath...)
+}
+
+// parseProtoFile parses .proto file to descriptors, the source code info is kept for the comments of API documents
+func parseProtoFile(protoPath string, protoFileIncludePath []string) ([]*desc.FileDescriptor, error) {
+	parser := protoparse.Parser{
+		ImportPaths:           protoFileIncludePath,
+		Accessor:              fsutil.Open,
+		IncludeSourceCodeInfo: true,
+	}
+	fileDescs, err := parser.ParseFiles(pb.GetProtoFileName(protoPath, protoFileIncludePath))
 	if err != nil {
-		return xerrors.Errorf("ioutil.ReadFile failed! error:%w", err)
+		return nil, xerrors.Errorf("Unable to parse proto file! proto file:%v, error:%w", protoPath, err)
 	}
+	return fileDescs, nil
+}
+
+// generateServiceFiles generates gRPC service related files, the go package of proto is imported as pb,
+// the RPC files of skipped RPCs are not generated
+func generateServiceFiles(goPackage *protoGoPackage, update bool, serviceDesc *desc.ServiceDescriptor,
+	skipped map[string]bool, layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	opts = append(opts, option.WithServiceName(serviceDesc.GetName()))
+	options := option.NewOptions(opts...)
+	serviceInfo := grpc.NewServiceInfo(opts...)
 
-	// generate register stub
-	// something like: healthcheck.RegisterHealthCheckService(server, healthcheck.Implenmentation)
-	protoImport := layout.GetServiceRegisterStub(protoPath, serviceName)
-	protoImportStub := fmt.Sprintf("%v\r\n	/*@REGISTER_STUB*/", protoImport)
-	if strings.Contains(string(mainContent), protoImport) {
-		return nil
+	// create path
+	handlerPath := layout.GetGRPCServicePath(serviceInfo.ServiceName)
+	if err := fsutil.CreatePath(handlerPath, options.Override); err != nil {
+		return xerrors.Errorf("create service path failed! error:%w", err)
 	}
-	mainContent = []byte(strings.Replace(string(mainContent), "/*@REGISTER_STUB*/", protoImportStub, 1))
 
-	// write to file
-	if err = ioutil.WriteFile(mainFilePath, mainContent, os.ModePerm); err != nil {
-		return xerrors.Errorf("ioutil.WriteFile failed! error:%w", err)
+	// create implementation file
+	opts = append(opts,
+		option.WithPackageName(serviceDesc.GetName()),
+		option.WithImportedPackageName(goPackage.ImportPath),
+	)
+
+	if err := grpc.NewServiceInfo(opts...).RenderFile(layout.GetGRPCServiceFilePath(serviceDesc.GetName())); err != nil {
+		return xerrors.Errorf("create service implementation file failed! error:%w", err)
+	}
+
+	// create test helper file shared by the RPC tests
+	if err := grpc.NewServiceInfo(opts...).RenderTestFile(layout.GetGRPCServiceTestFilePath(serviceDesc.GetName())); err != nil {
+		return xerrors.Errorf("create service test file failed! error:%w", err)
+	}
+
+	// create RPC file
+	for _, rpcDesc := range serviceDesc.GetMethods() {
+		if skipped[strcase.ToCamel(rpcDesc.GetName())] {
+			continue
+		}
+		if err := generateRPCFiles(goPackage, update, serviceDesc, rpcDesc, layout, opts...); err != nil {
+			return xerrors.Errorf("generateRPCFiles failed! error:%w", err)
+		}
 	}
 	return nil
 }
 
-// addServiceProtoImportToMain adds service proto import to main.go
-func addServiceProtoImportToMain(layout directory.GRPCServiceLayout, goModule, protoPath string) error {
-	// read file content
-	mainFilePath := layout.GetMainFilePath()
-	mainContent, err := ioutil.ReadFile(mainFilePath)
-	if err != nil {
-		return xerrors.Errorf("ioutil.ReadFile failed! error:%w", err)
-	}
+// generateRPCFiles generates gRPC RPC method related files, request & response defined in other protos are
+// qualified with the aliases of their go packages
+func generateRPCFiles(goPackage *protoGoPackage, update bool, serviceDesc *desc.ServiceDescriptor,
+	rpcDesc *desc.MethodDescriptor, layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	// generate
+	opts = append(opts,
+		option.WithRPCName(rpcDesc.GetName()),
+		option.WithRequestName(rpcDesc.GetInputType().GetName()),
+		option.WithResponseName(rpcDesc.GetOutputType().GetName()),
+		option.WithClientStreaming(rpcDesc.IsClientStreaming()),
+		option.WithServerStreaming(rpcDesc.IsServerStreaming()),
+	)
 
-	// generate proto stub
-	// something like: healthCheck "git.code.oa.com/foo/api/protobuf-spec/health-check"
-	protoImport := layout.GetProtoImportStub(goModule, protoPath)
-	protoImportStub := fmt.Sprintf("%v\r\n	/*@PROTO_STUB*/", protoImport)
-	if strings.Contains(string(mainContent), protoImport) {
-		return nil
+	rpcInfo := grpc.NewRPCInfo(opts...)
+	options := option.NewOptions(opts...)
+	imports := make(map[string]string)
+	rpcInfo.Request = getMessageGoType(rpcDesc.GetInputType(), goPackage, imports, layout, options)
+	rpcInfo.Response = getMessageGoType(rpcDesc.GetOutputType(), goPackage, imports, layout, options)
+	rpcInfo.Imports = getGoImports(imports)
+	if err := rpcInfo.RenderFile(layout.GetGRPCRPCFilePath(serviceDesc.GetName(), rpcDesc.GetName())); err != nil {
+		return xerrors.Errorf("create RPC implementation file failed! error:%w", err)
 	}
-	mainContent = []byte(strings.Replace(string(mainContent), "/*@PROTO_STUB*/", protoImportStub, 1))
 
-	// write to file
-	if err = ioutil.WriteFile(mainFilePath, mainContent, os.ModePerm); err != nil {
-		return xerrors.Errorf("ioutil.WriteFile failed! error:%w", err)
+	// the test is rendered the same way as the RPC file, so existing tests are kept on update
+	if err := rpcInfo.RenderTestFile(layout.GetGRPCRPCTestFilePath(serviceDesc.GetName(), rpcDesc.GetName())); err != nil {
+		return xerrors.Errorf("create RPC test file failed! error:%w", err)
 	}
 	return nil
 }
@@ -0,0 +1,59 @@
+package generate
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"golang.org/x/xerrors"
+)
+
+// CheckBreakingChanges compares the proto added before with its new revision which is going to be copied over it,
+// nothing is returned if the proto hasn't been added
+func (gen *GRPCServiceGenerator) CheckBreakingChanges(protoPath, newProtoPath string,
+	layout directory.GRPCServiceLayout, opts ...option.Option) ([]*pb.BreakingChange, error) {
+	options := option.NewOptions(opts...)
+
+	isExist, err := fsutil.CheckPathExists(protoPath)
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if !isExist {
+		return nil, nil
+	}
+
+	// both revisions are parsed as the proto in api/protobuf_spec, so that the imports are resolved in the same way
+	protoFileIncludePath := getProtoFileIncludePath(layout, options)
+	oldFileDesc, err := parseProtoFileRevision(protoPath, protoPath, protoFileIncludePath)
+	if err != nil {
+		return nil, xerrors.Errorf("parse old proto file failed! error:%w", err)
+	}
+	newFileDesc, err := parseProtoFileRevision(protoPath, newProtoPath, protoFileIncludePath)
+	if err != nil {
+		return nil, xerrors.Errorf("parse new proto file failed! error:%w", err)
+	}
+	return pb.CheckBreakingChanges(oldFileDesc, newFileDesc), nil
+}
+
+// parseProtoFileRevision parses proto file with the content of revisionPath
+func parseProtoFileRevision(protoPath, revisionPath string, protoFileIncludePath []string) (*desc.FileDescriptor, error) {
+	parser := protoparse.Parser{
+		ImportPaths: protoFileIncludePath,
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			if filepath.Clean(filename) == filepath.Clean(protoPath) {
+				return fsutil.Open(revisionPath)
+			}
+			return fsutil.Open(filename)
+		},
+	}
+	fileDescs, err := parser.ParseFiles(pb.GetProtoFileName(protoPath, protoFileIncludePath))
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to parse proto file! proto file:%v, error:%w", revisionPath, err)
+	}
+	return fileDescs[0], nil
+}
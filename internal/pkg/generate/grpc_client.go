@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/grpc"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/iancoleman/strcase"
+	"github.com/jhump/protoreflect/desc"
+	"golang.org/x/xerrors"
+)
+
+// generateServiceClient generates the client SDK of service into pkg/client/<service>, it's always overridden
+// since it only wraps the <Service>Client generated into the go package of proto
+func generateServiceClient(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor,
+	layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	options := option.NewOptions(opts...)
+
+	if err := fsutil.CreatePath(layout.GetClientPath(serviceDesc.GetName()), false); err != nil {
+		return xerrors.Errorf("create client path failed! error:%w", err)
+	}
+
+	opts = append(opts,
+		option.WithOverride(true),
+		option.WithServiceName(serviceDesc.GetName()),
+		option.WithPackageName(getClientPackageName(serviceDesc.GetName())),
+		option.WithImportedPackageName(goPackage.ImportPath),
+	)
+	clientInfo := grpc.NewClientInfo(opts...)
+	clientInfo.FullName = serviceDesc.GetFullyQualifiedName()
+	clientInfo.RPCs, clientInfo.Imports = getClientRPCs(goPackage, serviceDesc, layout, options)
+
+	if err := clientInfo.RenderFile(layout.GetClientFilePath(serviceDesc.GetName())); err != nil {
+		return xerrors.Errorf("create client file failed! error:%w", err)
+	}
+	return nil
+}
+
+// getClientRPCs returns the RPCs of service with qualified go types and the go packages imported by the types
+func getClientRPCs(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor,
+	layout directory.GRPCServiceLayout, options *option.Options) ([]*grpc.ClientRPC, []*grpc.GoImport) {
+	rpcs := make([]*grpc.ClientRPC, 0, len(serviceDesc.GetMethods()))
+	imports := make(map[string]string)
+	for _, methodDesc := range serviceDesc.GetMethods() {
+		rpcs = append(rpcs, &grpc.ClientRPC{
+			Name:            strcase.ToCamel(methodDesc.GetName()),
+			Request:         getMessageGoType(methodDesc.GetInputType(), goPackage, imports, layout, options),
+			Response:        getMessageGoType(methodDesc.GetOutputType(), goPackage, imports, layout, options),
+			ClientStreaming: methodDesc.IsClientStreaming(),
+			ServerStreaming: methodDesc.IsServerStreaming(),
+		})
+	}
+
+	return rpcs, getGoImports(imports)
+}
+
+// getClientPackageName returns the package name of client SDK, e.g.: UserService -> userservice
+func getClientPackageName(serviceName string) string {
+	return strings.ToLower(strcase.ToCamel(serviceName))
+}
@@ -0,0 +1,259 @@
+package generate
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/docs"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DocFormatMarkdown renders the API documents in markdown, e.g.: api/docs/user_service.md
+	DocFormatMarkdown = "markdown"
+	// DocFormatHTML renders the API documents in static html, e.g.: api/docs/user_service.html
+	DocFormatHTML = "html"
+	// DocFormatOpenAPI renders the API documents in OpenAPI 3 JSON, e.g.: api/docs/user_service.openapi.json
+	DocFormatOpenAPI = "openapi"
+)
+
+// docFormatExts maps the document formats to the file extensions
+var docFormatExts = map[string]string{
+	DocFormatMarkdown: ".md",
+	DocFormatHTML:     ".html",
+	DocFormatOpenAPI:  ".openapi.json",
+}
+
+// GenerateDocs renders the API documents of every service in api/protobuf_spec into api/docs,
+// one file per service & format, the existing documents are overridden, the written files are returned
+func (gen *GRPCServiceGenerator) GenerateDocs(layout directory.GRPCServiceLayout, opts ...option.Option) ([]string, error) {
+	options := option.NewOptions(opts...)
+	formats := options.DocFormats
+	if len(formats) == 0 {
+		formats = []string{DocFormatMarkdown, DocFormatHTML, DocFormatOpenAPI}
+	}
+	for _, format := range formats {
+		if _, ok := docFormatExts[format]; !ok {
+			return nil, xerrors.Errorf("Unsupported document format! format:%v, supported:%v, %v, %v",
+				format, DocFormatMarkdown, DocFormatHTML, DocFormatOpenAPI)
+		}
+	}
+
+	protoPaths, err := findServiceProtoFiles(layout)
+	if err != nil {
+		return nil, xerrors.Errorf("findServiceProtoFiles failed! error:%w", err)
+	}
+
+	services := make([]*docs.ServiceDoc, 0)
+	protoFileIncludePath := getProtoFileIncludePath(layout, options)
+	for _, protoPath := range protoPaths {
+		fileDescs, err := parseProtoFile(protoPath, protoFileIncludePath)
+		if err != nil {
+			return nil, xerrors.Errorf("parseProtoFile failed! error:%w", err)
+		}
+		for _, serviceDesc := range fileDescs[0].GetServices() {
+			services = append(services, newServiceDoc(serviceDesc))
+		}
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	if err := fsutil.CreatePath(layout.GetAPIDocsBasePath(), false); err != nil {
+		return nil, xerrors.Errorf("create docs path failed! error:%w", err)
+	}
+
+	opts = append(opts, option.WithOverride(true))
+	filePaths := make([]string, 0, len(services)*len(formats))
+	for _, service := range services {
+		docInfo := docs.NewServiceDocInfo(service, opts...)
+		for _, format := range formats {
+			filePath := layout.GetAPIDocsFilePath(service.Name, docFormatExts[format])
+			var err error
+			switch format {
+			case DocFormatMarkdown:
+				err = docInfo.RenderMarkdownFile(filePath)
+			case DocFormatHTML:
+				err = docInfo.RenderHTMLFile(filePath)
+			case DocFormatOpenAPI:
+				err = docInfo.RenderOpenAPIFile(filePath)
+			}
+			if err != nil {
+				return filePaths, xerrors.Errorf("render %v document failed! service:%v, error:%w",
+					format, service.FullName, err)
+			}
+			filePaths = append(filePaths, filePath)
+		}
+	}
+	return filePaths, nil
+}
+
+// newServiceDoc returns the API document of service with the messages & enums used by its RPCs
+func newServiceDoc(serviceDesc *desc.ServiceDescriptor) *docs.ServiceDoc {
+	service := &docs.ServiceDoc{
+		Name:      serviceDesc.GetName(),
+		FullName:  serviceDesc.GetFullyQualifiedName(),
+		ProtoFile: serviceDesc.GetFile().GetName(),
+		Comment:   getLeadingComment(serviceDesc),
+	}
+
+	collector := &docTypeCollector{
+		messages: make(map[string]*desc.MessageDescriptor),
+		enums:    make(map[string]*desc.EnumDescriptor),
+	}
+	for _, methodDesc := range serviceDesc.GetMethods() {
+		service.RPCs = append(service.RPCs, &docs.RPCDoc{
+			Name:            methodDesc.GetName(),
+			Anchor:          getDocAnchor("rpc " + methodDesc.GetName()),
+			Comment:         getLeadingComment(methodDesc),
+			RequestType:     methodDesc.GetInputType().GetFullyQualifiedName(),
+			ResponseType:    methodDesc.GetOutputType().GetFullyQualifiedName(),
+			RequestAnchor:   getTypeAnchor(methodDesc.GetInputType().GetFullyQualifiedName()),
+			ResponseAnchor:  getTypeAnchor(methodDesc.GetOutputType().GetFullyQualifiedName()),
+			StreamingMode:   pb.GetStreamingMode(methodDesc),
+			ClientStreaming: methodDesc.IsClientStreaming(),
+			ServerStreaming: methodDesc.IsServerStreaming(),
+		})
+		collector.collectMessage(methodDesc.GetInputType())
+		collector.collectMessage(methodDesc.GetOutputType())
+	}
+
+	for _, messageDesc := range collector.messages {
+		message := &docs.MessageDoc{
+			FullName: messageDesc.GetFullyQualifiedName(),
+			Anchor:   getDocAnchor(messageDesc.GetFullyQualifiedName()),
+			Comment:  getLeadingComment(messageDesc),
+		}
+		for _, fieldDesc := range messageDesc.GetFields() {
+			message.Fields = append(message.Fields, newFieldDoc(fieldDesc))
+		}
+		service.Messages = append(service.Messages, message)
+	}
+	sort.Slice(service.Messages, func(i, j int) bool {
+		return service.Messages[i].FullName < service.Messages[j].FullName
+	})
+
+	for _, enumDesc := range collector.enums {
+		enum := &docs.EnumDoc{
+			FullName: enumDesc.GetFullyQualifiedName(),
+			Anchor:   getDocAnchor(enumDesc.GetFullyQualifiedName()),
+			Comment:  getLeadingComment(enumDesc),
+		}
+		for _, valueDesc := range enumDesc.GetValues() {
+			enum.Values = append(enum.Values, &docs.EnumValueDoc{
+				Name:    valueDesc.GetName(),
+				Number:  valueDesc.GetNumber(),
+				Comment: strings.Join(strings.Fields(getLeadingComment(valueDesc)), " "),
+			})
+		}
+		service.Enums = append(service.Enums, enum)
+	}
+	sort.Slice(service.Enums, func(i, j int) bool {
+		return service.Enums[i].FullName < service.Enums[j].FullName
+	})
+	return service
+}
+
+// newFieldDoc returns the API document of field, the value type of map is described instead of the map entry
+func newFieldDoc(fieldDesc *desc.FieldDescriptor) *docs.FieldDoc {
+	field := &docs.FieldDoc{
+		Name:     fieldDesc.GetName(),
+		JSONName: fieldDesc.GetJSONName(),
+		Number:   fieldDesc.GetNumber(),
+		Type:     pb.GetFieldType(fieldDesc),
+		Comment:  strings.Join(strings.Fields(getLeadingComment(fieldDesc)), " "),
+		Repeated: fieldDesc.IsRepeated() && !fieldDesc.IsMap(),
+		Map:      fieldDesc.IsMap(),
+	}
+
+	valueDesc := fieldDesc
+	if fieldDesc.IsMap() {
+		valueDesc = fieldDesc.GetMapValueType()
+	}
+	field.ValueType = strings.TrimPrefix(pb.GetFieldType(valueDesc), "repeated ")
+	field.ValueIsMessage = valueDesc.GetMessageType() != nil
+	field.ValueIsEnum = valueDesc.GetEnumType() != nil
+	if field.ValueIsMessage || field.ValueIsEnum {
+		field.Anchor = getTypeAnchor(field.ValueType)
+	}
+	return field
+}
+
+// docTypeCollector collects the messages & enums used by RPCs transitively
+type docTypeCollector struct {
+	messages map[string]*desc.MessageDescriptor
+	enums    map[string]*desc.EnumDescriptor
+}
+
+// collectMessage collects the message and the messages & enums of its fields,
+// well-known types are documented by protobuf and map entries are described by the map fields
+func (collector *docTypeCollector) collectMessage(messageDesc *desc.MessageDescriptor) {
+	fullName := messageDesc.GetFullyQualifiedName()
+	if isWellKnownType(fullName) {
+		return
+	}
+	if _, ok := collector.messages[fullName]; ok {
+		return
+	}
+	if !messageDesc.IsMapEntry() {
+		collector.messages[fullName] = messageDesc
+	}
+
+	for _, fieldDesc := range messageDesc.GetFields() {
+		switch {
+		case fieldDesc.GetMessageType() != nil:
+			collector.collectMessage(fieldDesc.GetMessageType())
+		case fieldDesc.GetEnumType() != nil:
+			if enumName := fieldDesc.GetEnumType().GetFullyQualifiedName(); !isWellKnownType(enumName) {
+				collector.enums[enumName] = fieldDesc.GetEnumType()
+			}
+		}
+	}
+}
+
+// isWellKnownType checks if the type is defined by google/protobuf/*.proto
+func isWellKnownType(fullName string) bool {
+	return strings.HasPrefix(fullName, "google.protobuf.")
+}
+
+// getLeadingComment returns the trimmed leading comment of element
+func getLeadingComment(descriptor desc.Descriptor) string {
+	sourceInfo := descriptor.GetSourceInfo()
+	if sourceInfo == nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(sourceInfo.GetLeadingComments()), "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getTypeAnchor returns the anchor of message or enum in document, well-known types are not documented
+func getTypeAnchor(fullName string) string {
+	if isWellKnownType(fullName) {
+		return ""
+	}
+	return getDocAnchor(fullName)
+}
+
+// getDocAnchor returns the anchor of heading the same as GitHub does for markdown,
+// e.g.: user.AddUserRequest -> useradduserrequest
+func getDocAnchor(heading string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			return unicode.ToLower(r)
+		case r == ' ':
+			return '-'
+		default:
+			return -1
+		}
+	}, heading)
+}
@@ -0,0 +1,70 @@
+package generate
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+)
+
+// update rewrites the golden files of API documents with the rendered ones
+var update = flag.Bool("update", false, "update the golden files of API documents")
+
+// docsGoldenPath contains the proto & the API documents of its service
+const docsGoldenPath = "testdata/docs"
+
+// TestGenerateDocsGolden checks the documents of a service with comments, enums, nested messages & streaming RPCs
+func TestGenerateDocsGolden(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+	proto, err := ioutil.ReadFile(filepath.Join(docsGoldenPath, "user.proto"))
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+	}
+	protoPath := layout.GetAPIProtobufFilePath("user/user.proto")
+	if err := os.MkdirAll(filepath.Dir(protoPath), 0755); err != nil {
+		t.Fatalf("os.MkdirAll failed! error:%v", err)
+	}
+	if err := ioutil.WriteFile(protoPath, proto, 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+	}
+
+	filePaths, err := NewGRPCServiceGenerator().GenerateDocs(layout, option.WithOutputPath(root))
+	if err != nil {
+		t.Fatalf("GenerateDocs failed! error:%v", err)
+	}
+	if len(filePaths) != len(docFormatExts) {
+		t.Fatalf("GenerateDocs() = %v, want a document per format", filePaths)
+	}
+
+	for _, filePath := range filePaths {
+		name := filepath.Base(filePath)
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+		}
+
+		goldenFilePath := filepath.Join(docsGoldenPath, name+".golden")
+		if *update {
+			if err := ioutil.WriteFile(goldenFilePath, content, 0644); err != nil {
+				t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+			}
+		}
+		golden, err := ioutil.ReadFile(goldenFilePath)
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+		}
+		if string(content) != string(golden) {
+			t.Errorf("%v differs from golden file:\n%v\nwant\n%v", name, string(content), string(golden))
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package generate
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/grpc"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// gatewayFuncName is the function of gateway.go which starts the http gateway, it's called in main
+	gatewayFuncName = "startHTTPGateway"
+
+	// marker generated in gateway.go, gateway handler registers are inserted before it
+	gatewayRegisterMarker = "@GATEWAY_REGISTER_STUB"
+)
+
+// gatewayStartStub starts the http gateway in main after the gRPC server is started
+var gatewayStartStub = `
+
+	// run to serve http gateway
+	closeGatewayFunc, err := ` + gatewayFuncName + `(conf)
+	if err != nil {
+		log.Fatalf("` + gatewayFuncName + ` failed! error:%v", err)
+	}
+	defer closeGatewayFunc()`
+
+// InitHTTPGateway initializes the http gateway of project: google/api protos imported by the annotated protos,
+// cmd/gateway.go and the call starting it in main.go, the existing files are kept
+func (gen *GRPCServiceGenerator) InitHTTPGateway(layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	return initHTTPGateway(layout, opts...)
+}
+
+// initHTTPGateway initializes the http gateway of project
+func initHTTPGateway(layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	options := option.NewOptions(opts...)
+	opts = append(opts,
+		option.WithIgnoreExist(true),
+		option.WithConfigPackagePath(layout.GetConfigPackageName(options.GoModule)),
+	)
+
+	// create google/api protos
+	googleAPIPath := filepath.Join(layout.GetAPIProtobufBasePath(), "google", "api")
+	if err := fsutil.CreatePaths(false, googleAPIPath); err != nil {
+		return xerrors.Errorf("create google api directory failed! error:%w", err)
+	}
+	googleAPIProtoInfo := grpc.NewGoogleAPIProtoInfo(opts...)
+	if err := googleAPIProtoInfo.RenderAnnotationsFile(filepath.Join(googleAPIPath, "annotations.proto")); err != nil {
+		return xerrors.Errorf("create google api annotations proto file failed! error:%w", err)
+	}
+	if err := googleAPIProtoInfo.RenderHTTPFile(filepath.Join(googleAPIPath, "http.proto")); err != nil {
+		return xerrors.Errorf("create google api http proto file failed! error:%w", err)
+	}
+
+	// create gateway file
+	if err := grpc.NewGatewayInfo(opts...).RenderFile(layout.GetGatewayFilePath()); err != nil {
+		return xerrors.Errorf("create gateway file failed! error:%w", err)
+	}
+
+	// start http gateway in main.go
+	if err := addGatewayStartToMain(layout); err != nil {
+		return xerrors.Errorf("add gateway start to main.go failed! error:%w", err)
+	}
+
+	// buf generates code according to buf.gen.yaml
+	if options.ProtoTool == pb.ProtoToolBuf {
+		if err := enableBufGenGateway(layout, opts...); err != nil {
+			return xerrors.Errorf("enableBufGenGateway failed! error:%w", err)
+		}
+	}
+	return nil
+}
+
+// enableBufGenGateway adds grpc-gateway & openapiv2 plugins to buf.gen.yaml, it's kept if it's modified
+func enableBufGenGateway(layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	filePath := layout.GetAPIProtobufBufGenFilePath()
+	content, err := fsutil.ReadFile(filePath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+
+	gateway, err := grpc.NewBufGenYAMLInfo(append(opts, option.WithHTTPGateway(true))...).Render(filePath)
+	if err != nil {
+		return xerrors.Errorf("render buf.gen.yaml file failed! error:%w", err)
+	}
+	if bytes.Equal(content, gateway) {
+		return nil
+	}
+
+	original, err := grpc.NewBufGenYAMLInfo(append(opts, option.WithHTTPGateway(false))...).Render(filePath)
+	if err != nil {
+		return xerrors.Errorf("render buf.gen.yaml file failed! error:%w", err)
+	}
+	if !bytes.Equal(content, original) {
+		log.Warnf("grpc-gateway & openapiv2 plugins must be enabled in %v to generate the http gateway", filePath)
+		return nil
+	}
+	if err := fsutil.WriteFile(filePath, gateway, 0666); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// addGatewayStartToMain adds the call of startHTTPGateway after 'defer closeFunc()' of gRPC server in main
+func addGatewayStartToMain(layout directory.GRPCServiceLayout) error {
+	return editMainFile(layout, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+		funcDecl := findFunc(file, "main")
+		if funcDecl == nil {
+			return nil, xerrors.Errorf("function main not found! file:%v", layout.GetMainFilePath())
+		}
+		if findCall(funcDecl, gatewayFuncName) {
+			return nil, nil
+		}
+
+		for _, stmt := range funcDecl.Body.List {
+			deferStmt, ok := stmt.(*ast.DeferStmt)
+			if !ok {
+				continue
+			}
+			if ident, ok := deferStmt.Call.Fun.(*ast.Ident); ok && ident.Name == "closeFunc" {
+				offset := fileSet.Position(deferStmt.End()).Offset
+				return splice(content, offset, offset, gatewayStartStub), nil
+			}
+		}
+		return nil, xerrors.Errorf("'defer closeFunc()' not found in function main, add '%v(conf)' manually! file:%v",
+			gatewayFuncName, layout.GetMainFilePath())
+	})
+}
+
+// addServiceGatewayToGateway adds the proto import & the service handler register to gateway.go
+// something like: healthCheckProto.RegisterHealthCheckHandlerFromEndpoint,
+func addServiceGatewayToGateway(layout directory.GRPCServiceLayout, serviceName string,
+	goPackage *protoGoPackage) error {
+	gatewayFilePath := layout.GetGatewayFilePath()
+	alias, err := getProtoAlias(gatewayFilePath, layout, goPackage)
+	if err != nil {
+		return xerrors.Errorf("getProtoAlias failed! error:%w", err)
+	}
+	if err := addImportToFile(gatewayFilePath,
+		layout.GetProtoImportStub(alias, goPackage.ImportPath), protoImportMarker); err != nil {
+		return xerrors.Errorf("addImportToFile failed! error:%w", err)
+	}
+
+	register := layout.GetGatewayRegisterStub(alias, serviceName)
+	return editGoFile(gatewayFilePath, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+		if bytes.Contains(content, []byte(register)) {
+			return nil, nil
+		}
+
+		funcDecl := findFunc(file, gatewayFuncName)
+		if funcDecl == nil {
+			return nil, xerrors.Errorf("function %v not found! file:%v", gatewayFuncName, gatewayFilePath)
+		}
+		offset := findComment(fileSet, file, funcDecl, gatewayRegisterMarker)
+		if offset < 0 {
+			return nil, xerrors.Errorf("marker %v not found in function %v! file:%v",
+				gatewayRegisterMarker, gatewayFuncName, gatewayFilePath)
+		}
+		return splice(content, offset, offset, register+"\n"), nil
+	})
+}
+
+// removeServiceGatewayFromGateway removes the service handler register & the proto import from gateway.go
+func removeServiceGatewayFromGateway(layout directory.GRPCServiceLayout, serviceNames []string,
+	goPackage *protoGoPackage) error {
+	gatewayFilePath := layout.GetGatewayFilePath()
+	isExist, err := fsutil.CheckPathExists(gatewayFilePath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if !isExist {
+		return nil
+	}
+
+	alias, err := getProtoAlias(gatewayFilePath, layout, goPackage)
+	if err != nil {
+		return xerrors.Errorf("getProtoAlias failed! error:%w", err)
+	}
+	for _, serviceName := range serviceNames {
+		register := []byte(layout.GetGatewayRegisterStub(alias, serviceName))
+		err := editGoFile(gatewayFilePath, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+			index := bytes.Index(content, register)
+			if index < 0 {
+				return nil, nil
+			}
+			begin, end := lineSpan(content, index, index+len(register))
+			return splice(content, begin, end, ""), nil
+		})
+		if err != nil {
+			return xerrors.Errorf("editGoFile failed! error:%w", err)
+		}
+	}
+
+	if err := removeImportFromFile(gatewayFilePath,
+		layout.GetProtoImportStub(alias, goPackage.ImportPath)); err != nil {
+		return xerrors.Errorf("removeImportFromFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// findFunc returns the function declaration by name
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil &&
+			funcDecl.Name.Name == name && funcDecl.Body != nil {
+			return funcDecl
+		}
+	}
+	return nil
+}
+
+// findCall checks if the function calls the function named name
+func findCall(funcDecl *ast.FuncDecl, name string) bool {
+	found := false
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,78 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/grpc"
+)
+
+func TestEnableBufGenGateway(t *testing.T) {
+	tests := []struct {
+		name        string
+		goModule    string
+		modified    bool
+		wantGateway bool
+		wantModule  bool
+	}{
+		{"source relative", "", false, true, false},
+		{"go module", "github.com/foo/bar", false, true, true},
+		{"modified", "github.com/foo/bar", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+			opts := []option.Option{option.WithOutputPath(root), option.WithGoModule(tt.goModule)}
+			filePath := layout.GetAPIProtobufBufGenFilePath()
+			if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+				t.Fatalf("os.MkdirAll failed! error:%v", err)
+			}
+			if err := grpc.NewBufGenYAMLInfo(opts...).RenderFile(filePath); err != nil {
+				t.Fatalf("RenderFile failed! error:%v", err)
+			}
+			if tt.modified {
+				content, err := ioutil.ReadFile(filePath)
+				if err != nil {
+					t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+				}
+				content = append(content, []byte("  - plugin: validate\n    out: .\n")...)
+				if err := ioutil.WriteFile(filePath, content, 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+				}
+			}
+
+			// enabling twice keeps buf.gen.yaml unchanged
+			for i := 0; i < 2; i++ {
+				if err := enableBufGenGateway(layout, opts...); err != nil {
+					t.Fatalf("enableBufGenGateway() failed! error:%v", err)
+				}
+			}
+
+			content, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+			}
+			if got := strings.Contains(string(content), "- plugin: grpc-gateway"); got != tt.wantGateway {
+				t.Errorf("enableBufGenGateway() gateway = %v, want %v:\n%s", got, tt.wantGateway, content)
+			}
+			if got := strings.Contains(string(content), "opt: module="+tt.goModule); got != tt.wantModule {
+				t.Errorf("enableBufGenGateway() module = %v, want %v:\n%s", got, tt.wantModule, content)
+			}
+			if got := isBufGenByModule(layout); got != tt.wantModule {
+				t.Errorf("isBufGenByModule() = %v, want %v", got, tt.wantModule)
+			}
+		})
+	}
+}
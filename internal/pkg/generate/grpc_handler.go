@@ -0,0 +1,1054 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/iancoleman/strcase"
+	"github.com/jhump/protoreflect/desc"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// deprecatedFileName is the file orphan handlers are moved to, files beginning with '_' are ignored by the go tool
+const deprecatedFileName = "_deprecated.go"
+
+// handlerMethod represents a method of service Implementation found in the handler files
+type handlerMethod struct {
+	Name     string
+	FilePath string
+	// qualified types, e.g.: context.Context, *pb.Request
+	Params  []string
+	Results []string
+	// import path -> alias of the go packages referenced by the types, the imports of handler file for the
+	// existing methods
+	Imports map[string]string
+	// message types received & sent by the stream of streaming RPCs, e.g.: *pb.Request, they're not in the
+	// signature but the handler doesn't compile if they change
+	Recv string
+	Send string
+	// streaming mode, e.g.: unary, server streaming, it's told from the stream methods called by the existing
+	// client & bidirectional streaming handlers, and empty if none is called
+	Mode string
+}
+
+// Signature returns the method signature with the packages qualified by import paths, so that the types moved to
+// another package don't match, e.g.: (context.Context, *example.com/user.Request) (*example.com/user.Response, error)
+func (method *handlerMethod) Signature() string {
+	return fmt.Sprintf("(%v) (%v)", strings.Join(method.qualifiedTypes(method.Params), ", "),
+		strings.Join(method.qualifiedTypes(method.Results), ", "))
+}
+
+// qualifiedTypes returns the types with the package aliases replaced by import paths, e.g.: *pb.Request ->
+// *example.com/user.Request, the aliases not imported are kept
+func (method *handlerMethod) qualifiedTypes(types []string) []string {
+	importPaths := make(map[string]string)
+	for importPath, alias := range method.Imports {
+		importPaths[alias] = importPath
+	}
+	qualified := make([]string, 0, len(types))
+	for _, typ := range types {
+		qualified = append(qualified, qualifyType(typ, importPaths))
+	}
+	return qualified
+}
+
+// StreamTypes returns the message types of stream with package names stripped, e.g.: recv *Request, send *Response
+func (method *handlerMethod) StreamTypes() string {
+	types := make([]string, 0, 2)
+	if method.Recv != "" {
+		types = append(types, "recv "+stripPackages([]string{method.Recv})[0])
+	}
+	if method.Send != "" {
+		types = append(types, "send "+stripPackages([]string{method.Send})[0])
+	}
+	return strings.Join(types, ", ")
+}
+
+// matchStreamTypes checks if the stream of method sends & receives the expected types,
+// it's true if the types of method are unknown, e.g.: the generated code doesn't exist
+func (method *handlerMethod) matchStreamTypes(expected *handlerMethod) bool {
+	if method.Recv == "" && method.Send == "" {
+		return true
+	}
+	return method.StreamTypes() == expected.StreamTypes()
+}
+
+// streamingMode returns the streaming mode of method, it's told from the parameters if it's not set,
+// client & bidirectional streaming can't be told apart that way so the mode is empty for both
+func (method *handlerMethod) streamingMode() string {
+	if method.Mode != "" {
+		return method.Mode
+	}
+	params := method.qualifiedTypes(method.Params)
+	switch {
+	case len(params) == 2 && params[0] == "context.Context":
+		return "unary"
+	case len(params) == 2:
+		return "server streaming"
+	}
+	return ""
+}
+
+// matchStreamingMode checks if method is in the streaming mode of expected, the body generated for another
+// mode doesn't compile, e.g.: the response returned by unary handlers is sent by stream in streaming ones
+func (method *handlerMethod) matchStreamingMode(expected *handlerMethod) bool {
+	if mode := method.streamingMode(); mode != "" {
+		return mode == expected.Mode
+	}
+	// the body calls neither SendAndClose nor Send, so it compiles in both client & bidirectional streaming
+	return len(method.Params) != 1 || len(expected.Params) == 1
+}
+
+// renamedSignature returns the signature as if the method is renamed, the stream type is named after the RPC
+func (method *handlerMethod) renamedSignature(name string) string {
+	return strings.Replace(method.Signature(), "_"+method.Name+"Server", "_"+name+"Server", -1)
+}
+
+// handlerReport represents the differences between proto service and handler files
+type handlerReport struct {
+	ServiceName string
+	// methods without RPC definition
+	Orphans []*handlerMethod
+	// orphan method name -> newly added RPC name with the same request & response
+	Renamed map[string]string
+	// methods whose signature doesn't match the RPC definition
+	Mismatched []*handlerMethod
+	// methods whose streaming mode changed, the bodies can't be kept so they're replaced by new stubs
+	ModeChanged []*handlerMethod
+	// streaming methods whose signature matches but the types received or sent by stream changed
+	StreamChanged []*handlerMethod
+	// RPC name -> method expected by the RPC definition
+	Expected map[string]*handlerMethod
+}
+
+// IsEmpty returns true if nothing changed
+func (report *handlerReport) IsEmpty() bool {
+	return len(report.Orphans) == 0 && len(report.Mismatched) == 0 && len(report.ModeChanged) == 0 &&
+		len(report.StreamChanged) == 0
+}
+
+// String returns the readable report
+func (report *handlerReport) String() string {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "service %v handlers are out of date:\n", report.ServiceName)
+	for _, method := range report.Orphans {
+		if newName, ok := report.Renamed[method.Name]; ok {
+			fmt.Fprintf(buffer, "  renamed:  %v -> %v (%v)\n", method.Name, newName, method.FilePath)
+			continue
+		}
+		fmt.Fprintf(buffer, "  orphaned: %v (%v)\n", method.Name, method.FilePath)
+	}
+	for _, method := range report.Mismatched {
+		fmt.Fprintf(buffer, "  signature changed: %v (%v)\n    current:  %v\n    expected: %v\n",
+			method.Name, method.FilePath, method.Signature(), report.Expected[method.Name].Signature())
+	}
+	for _, method := range report.ModeChanged {
+		mode := method.streamingMode()
+		if mode == "" {
+			mode = "client or bidirectional streaming"
+		}
+		fmt.Fprintf(buffer, "  streaming mode changed: %v (%v)\n    current:  %v\n    expected: %v\n",
+			method.Name, method.FilePath, mode, report.Expected[method.Name].Mode)
+	}
+	for _, method := range report.StreamChanged {
+		fmt.Fprintf(buffer, "  stream types changed: %v (%v)\n    current:  %v\n    expected: %v\n",
+			method.Name, method.FilePath, method.StreamTypes(), report.Expected[method.Name].StreamTypes())
+	}
+	return buffer.String()
+}
+
+// parseServiceHandlers parses all methods of Implementation in the service handler directory, the types received &
+// sent by streams are parsed from the stream interfaces generated into goPackageDir, so it must be called before
+// the proto is compiled again
+func parseServiceHandlers(servicePath, goPackageDir string) ([]*handlerMethod, error) {
+	isExist, err := fsutil.CheckPathExists(servicePath)
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if !isExist {
+		return nil, nil
+	}
+
+	filePaths, err := filepath.Glob(filepath.Join(servicePath, "*.go"))
+	if err != nil {
+		return nil, xerrors.Errorf("filepath.Glob failed! error:%w", err)
+	}
+
+	methods := make([]*handlerMethod, 0)
+	for _, filePath := range filePaths {
+		fileName := filepath.Base(filePath)
+		if strings.HasPrefix(fileName, "_") || strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+
+		content, err := fsutil.ReadFile(filePath)
+		if err != nil {
+			return nil, xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, content, 0)
+		if err != nil {
+			return nil, xerrors.Errorf("parser.ParseFile failed! file:%v, error:%w", filePath, err)
+		}
+
+		imports := getFileImports(file)
+		for _, funcDecl := range getImplementationMethods(file) {
+			methods = append(methods, &handlerMethod{
+				Name:     funcDecl.Name.Name,
+				FilePath: filePath,
+				Params:   fieldTypes(funcDecl.Type.Params),
+				Results:  fieldTypes(funcDecl.Type.Results),
+				Imports:  imports,
+				Mode:     getStreamMethodsMode(funcDecl),
+			})
+		}
+	}
+
+	streams, err := parseStreamInterfaces(goPackageDir)
+	if err != nil {
+		return nil, xerrors.Errorf("parseStreamInterfaces failed! error:%w", err)
+	}
+	for _, method := range methods {
+		for _, param := range stripPackages(method.Params) {
+			if stream, ok := streams[param]; ok {
+				method.Recv, method.Send = stream.Recv, stream.Send
+			}
+		}
+	}
+	return methods, nil
+}
+
+// parseStreamInterfaces parses the server stream interfaces generated by protoc-gen-go-grpc,
+// e.g.: UserService_ListUsersServer -> Send(*User) error, nothing is parsed if goPackageDir is empty
+func parseStreamInterfaces(goPackageDir string) (map[string]*handlerMethod, error) {
+	streams := make(map[string]*handlerMethod)
+	if goPackageDir == "" {
+		return streams, nil
+	}
+
+	filePaths, err := filepath.Glob(filepath.Join(goPackageDir, "*.go"))
+	if err != nil {
+		return nil, xerrors.Errorf("filepath.Glob failed! error:%w", err)
+	}
+
+	for _, filePath := range filePaths {
+		content, err := fsutil.ReadFile(filePath)
+		if err != nil {
+			return nil, xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, content, 0)
+		if err != nil {
+			return nil, xerrors.Errorf("parser.ParseFile failed! file:%v, error:%w", filePath, err)
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			typeSpec, ok := node.(*ast.TypeSpec)
+			if !ok || !strings.HasSuffix(typeSpec.Name.Name, "Server") || !strings.Contains(typeSpec.Name.Name, "_") {
+				return true
+			}
+			interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				return false
+			}
+
+			stream := &handlerMethod{Name: typeSpec.Name.Name}
+			for _, field := range interfaceType.Methods.List {
+				funcType, ok := field.Type.(*ast.FuncType)
+				if !ok || len(field.Names) == 0 {
+					continue
+				}
+				switch field.Names[0].Name {
+				case "Recv":
+					if results := fieldTypes(funcType.Results); len(results) > 0 {
+						stream.Recv = results[0]
+					}
+				case "Send", "SendAndClose":
+					if params := fieldTypes(funcType.Params); len(params) > 0 {
+						stream.Send = params[0]
+					}
+				}
+			}
+			streams[stream.Name] = stream
+			return false
+		})
+	}
+	return streams, nil
+}
+
+// checkServiceHandlers compares the handlers existed before update with the RPCs of service
+func checkServiceHandlers(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor, existing []*handlerMethod,
+	layout directory.GRPCServiceLayout, options *option.Options) *handlerReport {
+	report := &handlerReport{
+		ServiceName: serviceDesc.GetName(),
+		Renamed:     make(map[string]string),
+		Expected:    make(map[string]*handlerMethod),
+	}
+
+	existingNames := make(map[string]bool)
+	for _, method := range existing {
+		existingNames[method.Name] = true
+	}
+
+	for _, rpcDesc := range serviceDesc.GetMethods() {
+		expected := expectedHandlerMethod(goPackage, serviceDesc, rpcDesc, layout, options)
+		report.Expected[expected.Name] = expected
+	}
+
+	for _, method := range existing {
+		expected, ok := report.Expected[method.Name]
+		if !ok {
+			report.Orphans = append(report.Orphans, method)
+			continue
+		}
+
+		if !method.matchStreamingMode(expected) {
+			report.ModeChanged = append(report.ModeChanged, method)
+		} else if expected.Signature() != method.Signature() {
+			report.Mismatched = append(report.Mismatched, method)
+		} else if !method.matchStreamTypes(expected) {
+			report.StreamChanged = append(report.StreamChanged, method)
+		}
+	}
+
+	// an orphan with the same signature as a newly added RPC is most likely renamed, the RPCs are checked
+	// in the proto order and each one is taken by one orphan at most
+	for _, method := range report.Orphans {
+		for _, rpcDesc := range serviceDesc.GetMethods() {
+			expected := report.Expected[strcase.ToCamel(rpcDesc.GetName())]
+			if existingNames[expected.Name] {
+				continue
+			}
+			if expected.Signature() == method.renamedSignature(expected.Name) && method.matchStreamingMode(expected) &&
+				method.matchStreamTypes(expected) {
+				report.Renamed[method.Name] = expected.Name
+				existingNames[expected.Name] = true
+				break
+			}
+		}
+	}
+	return report
+}
+
+// getRenamedRPCs returns the names of RPCs renamed from the existing handlers
+func getRenamedRPCs(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor, existing []*handlerMethod,
+	layout directory.GRPCServiceLayout, options *option.Options) map[string]bool {
+	renamed := make(map[string]bool)
+	for _, newName := range checkServiceHandlers(goPackage, serviceDesc, existing, layout, options).Renamed {
+		renamed[newName] = true
+	}
+	return renamed
+}
+
+// updateServiceHandlers reports the handlers out of date with the service definition,
+// and fixes them if FixHandlers is set
+func updateServiceHandlers(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor, existing []*handlerMethod,
+	layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	options := option.NewOptions(opts...)
+
+	report := checkServiceHandlers(goPackage, serviceDesc, existing, layout, options)
+	if report.IsEmpty() {
+		return nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(report.String()), "\n") {
+		log.Warn(line)
+	}
+	if !options.FixHandlers {
+		log.Warnf("run with '--fix-handlers' to move orphans & the handlers whose streaming modes changed to %v, "+
+			"rename the renamed ones and rewrite changed signatures", deprecatedFileName)
+		for _, newName := range report.Renamed {
+			log.Warnf("stub of %v is not generated until the handler renamed to it is fixed", newName)
+		}
+		return nil
+	}
+
+	if err := fixServiceHandlers(goPackage, serviceDesc, report, layout, opts...); err != nil {
+		return xerrors.Errorf("fixServiceHandlers failed! error:%w", err)
+	}
+	return nil
+}
+
+// fixServiceHandlers moves orphans to the deprecated file, renames the renamed ones, rewrites the mismatched
+// signatures and replaces the handlers whose streaming modes changed with new stubs
+func fixServiceHandlers(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor, report *handlerReport,
+	layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	servicePath := layout.GetGRPCServicePath(serviceDesc.GetName())
+
+	rpcs := make(map[string]*desc.MethodDescriptor)
+	for _, rpcDesc := range serviceDesc.GetMethods() {
+		rpcs[strcase.ToCamel(rpcDesc.GetName())] = rpcDesc
+	}
+
+	for _, method := range report.Orphans {
+		if newName, ok := report.Renamed[method.Name]; ok {
+			if err := renameHandler(serviceDesc, rpcs[newName], method, report.Expected[newName], layout); err != nil {
+				return xerrors.Errorf("renameHandler failed! method:%v, error:%w", method.Name, err)
+			}
+			log.Infof("handler %v renamed to %v", method.Name, newName)
+		} else {
+			if err := moveHandlerToDeprecated(method, filepath.Join(servicePath, deprecatedFileName)); err != nil {
+				return xerrors.Errorf("moveHandlerToDeprecated failed! method:%v, error:%w", method.Name, err)
+			}
+			log.Infof("orphan handler %v moved to %v", method.Name, filepath.Join(servicePath, deprecatedFileName))
+		}
+
+		// the test calls the removed RPC of client, so it's ignored the same way
+		testFilePath := layout.GetGRPCRPCTestFilePath(serviceDesc.GetName(), method.Name)
+		deprecatedTestFilePath, err := moveFileToDeprecated(testFilePath)
+		if err != nil {
+			return xerrors.Errorf("moveFileToDeprecated failed! method:%v, error:%w", method.Name, err)
+		}
+		if deprecatedTestFilePath != "" {
+			log.Infof("orphan test of %v moved to %v", method.Name, deprecatedTestFilePath)
+		}
+	}
+
+	for _, method := range report.Mismatched {
+		if err := rewriteHandler(serviceDesc, rpcs[method.Name], method, report.Expected[method.Name]); err != nil {
+			return xerrors.Errorf("rewriteHandler failed! method:%v, error:%w", method.Name, err)
+		}
+		log.Infof("handler %v signature rewritten to %v", method.Name, report.Expected[method.Name].Signature())
+		log.Warnf("test of %v is kept, update it to the new signature: %v", method.Name,
+			layout.GetGRPCRPCTestFilePath(serviceDesc.GetName(), method.Name))
+	}
+
+	// the body written for another streaming mode doesn't compile, so it's moved to the deprecated file with its test
+	// and the stubs are generated again
+	opts = append(opts,
+		option.WithServiceName(serviceDesc.GetName()),
+		option.WithPackageName(serviceDesc.GetName()),
+		option.WithImportedPackageName(goPackage.ImportPath),
+	)
+	for _, method := range report.ModeChanged {
+		if err := moveHandlerToDeprecated(method, filepath.Join(servicePath, deprecatedFileName)); err != nil {
+			return xerrors.Errorf("moveHandlerToDeprecated failed! method:%v, error:%w", method.Name, err)
+		}
+		if _, err := moveFileToDeprecated(layout.GetGRPCRPCTestFilePath(serviceDesc.GetName(), method.Name)); err != nil {
+			return xerrors.Errorf("moveFileToDeprecated failed! method:%v, error:%w", method.Name, err)
+		}
+
+		// the file is kept if there's other code in it, so the stub can't be generated
+		filePath := layout.GetGRPCRPCFilePath(serviceDesc.GetName(), rpcs[method.Name].GetName())
+		exist, err := fsutil.CheckPathExists(filePath)
+		if err != nil {
+			return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+		}
+		if err := generateRPCFiles(goPackage, true, serviceDesc, rpcs[method.Name], layout, opts...); err != nil {
+			return xerrors.Errorf("generateRPCFiles failed! method:%v, error:%w", method.Name, err)
+		}
+		log.Infof("handler %v moved to %v since it's %v now", method.Name,
+			filepath.Join(servicePath, deprecatedFileName), report.Expected[method.Name].Mode)
+		if exist {
+			log.Warnf("stub of %v is not generated since %v has other code, implement it: %v", method.Name,
+				filePath, report.Expected[method.Name].Signature())
+		}
+	}
+
+	// the body is out of date, e.g.: the response passed to SendAndClose, which can't be fixed automatically
+	for _, method := range report.StreamChanged {
+		log.Warnf("handler %v is kept, update it to the new stream types: %v", method.Name,
+			report.Expected[method.Name].StreamTypes())
+	}
+	return nil
+}
+
+// expectedHandlerMethod returns the handler method expected by the RPC definition, the go package of service proto
+// is qualified as pb like RPCTemplate does
+func expectedHandlerMethod(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor, rpcDesc *desc.MethodDescriptor,
+	layout directory.GRPCServiceLayout, options *option.Options) *handlerMethod {
+	imports := make(map[string]string)
+	request := "*" + getMessageGoType(rpcDesc.GetInputType(), goPackage, imports, layout, options)
+	response := "*" + getMessageGoType(rpcDesc.GetOutputType(), goPackage, imports, layout, options)
+	stream := fmt.Sprintf("pb.%v_%vServer", strcase.ToCamel(serviceDesc.GetName()), strcase.ToCamel(rpcDesc.GetName()))
+
+	method := &handlerMethod{Name: strcase.ToCamel(rpcDesc.GetName()), Mode: pb.GetStreamingMode(rpcDesc)}
+	switch {
+	case rpcDesc.IsClientStreaming():
+		method.Params = []string{stream}
+		method.Results = []string{"error"}
+		method.Recv, method.Send = request, response
+	case rpcDesc.IsServerStreaming():
+		method.Params = []string{request, stream}
+		method.Results = []string{"error"}
+		method.Send = response
+	default:
+		method.Params = []string{"context.Context", request}
+		method.Results = []string{response, "error"}
+	}
+
+	// only the packages still referenced are imported, e.g.: the response isn't in the signature of streaming RPCs
+	imports[goPackage.ImportPath] = "pb"
+	method.Imports = make(map[string]string)
+	for importPath, alias := range imports {
+		for _, typ := range append(append([]string{}, method.Params...), method.Results...) {
+			if strings.HasPrefix(strings.TrimPrefix(typ, "*"), alias+".") {
+				method.Imports[importPath] = alias
+			}
+		}
+	}
+	return method
+}
+
+// moveHandlerToDeprecated cuts the method out of its file and appends it to the deprecated file
+func moveHandlerToDeprecated(method *handlerMethod, deprecatedFilePath string) error {
+	packageName, code, err := cutHandler(method.FilePath, method.Name)
+	if err != nil {
+		return xerrors.Errorf("cutHandler failed! error:%w", err)
+	}
+
+	// append method to the deprecated file, it keeps the package clause for readability only
+	deprecated, err := fsutil.ReadFile(deprecatedFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+	if len(deprecated) == 0 {
+		deprecated = []byte(fmt.Sprintf("package %v\n\n"+
+			"// Handlers below are moved here by gofra because their RPCs were removed from proto or their\n"+
+			"// streaming modes changed, this file is ignored by the go tool, delete it once the code is no longer\n"+
+			"// needed.\n", packageName))
+	}
+	deprecated = append(deprecated, '\n')
+	deprecated = append(deprecated, code...)
+	deprecated = append(deprecated, '\n')
+	if err := fsutil.WriteFile(deprecatedFilePath, deprecated, os.ModePerm); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// cutHandler removes the method and its doc comment from the file, the whole file is removed if nothing else is left,
+// the package name & the code removed are returned
+func cutHandler(filePath, name string) (string, []byte, error) {
+	content, err := fsutil.ReadFile(filePath)
+	if err != nil {
+		return "", nil, xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, content, parser.ParseComments)
+	if err != nil {
+		return "", nil, xerrors.Errorf("parser.ParseFile failed! error:%w", err)
+	}
+
+	funcDecl := findImplementationMethod(file, name)
+	if funcDecl == nil {
+		return "", nil, xerrors.Errorf("method not found! file:%v, method:%v", filePath, name)
+	}
+	begin := funcDecl.Pos()
+	if funcDecl.Doc != nil {
+		begin = funcDecl.Doc.Pos()
+	}
+	beginOffset, endOffset := fileSet.Position(begin).Offset, fileSet.Position(funcDecl.End()).Offset
+	code := append([]byte{}, content[beginOffset:endOffset]...)
+
+	remain := splice(content, beginOffset, endOffset, "")
+	remainFile, err := parser.ParseFile(token.NewFileSet(), filePath, remain, parser.ParseComments)
+	if err != nil {
+		return "", nil, xerrors.Errorf("parser.ParseFile failed! error:%w", err)
+	}
+	if !hasNonImportDecls(remainFile) {
+		if err := fsutil.Remove(filePath); err != nil {
+			return "", nil, xerrors.Errorf("fsutil.Remove failed! error:%w", err)
+		}
+		return file.Name.Name, code, nil
+	}
+	if err := writeGoFile(filePath, remain); err != nil {
+		return "", nil, xerrors.Errorf("writeGoFile failed! error:%w", err)
+	}
+	return file.Name.Name, code, nil
+}
+
+// moveFileToDeprecated renames the file with a '_' prefix so that it's ignored by the go tool,
+// the new file path is returned, or empty if the file doesn't exist
+func moveFileToDeprecated(filePath string) (string, error) {
+	exist, err := fsutil.CheckPathExists(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if !exist {
+		return "", nil
+	}
+
+	content, err := fsutil.ReadFile(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+	deprecatedFilePath := filepath.Join(filepath.Dir(filePath), "_"+filepath.Base(filePath))
+	if err := fsutil.WriteFile(deprecatedFilePath, content, os.ModePerm); err != nil {
+		return "", xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	if err := fsutil.Remove(filePath); err != nil {
+		return "", xerrors.Errorf("fsutil.Remove failed! error:%w", err)
+	}
+	return deprecatedFilePath, nil
+}
+
+// renameHandler renames the method to the renamed RPC in place of the stub generated for it, the file named after
+// the method is renamed as well
+func renameHandler(serviceDesc *desc.ServiceDescriptor, rpcDesc *desc.MethodDescriptor, method, expected *handlerMethod,
+	layout directory.GRPCServiceLayout) error {
+	stubFilePath := layout.GetGRPCRPCFilePath(serviceDesc.GetName(), expected.Name)
+	stubs, err := parseServiceHandlers(filepath.Dir(stubFilePath), "")
+	if err != nil {
+		return xerrors.Errorf("parseServiceHandlers failed! error:%w", err)
+	}
+	for _, stub := range stubs {
+		if stub.Name != expected.Name {
+			continue
+		}
+		if _, _, err := cutHandler(stub.FilePath, stub.Name); err != nil {
+			return xerrors.Errorf("cutHandler failed! error:%w", err)
+		}
+	}
+	if err := rewriteHandler(serviceDesc, rpcDesc, method, expected); err != nil {
+		return xerrors.Errorf("rewriteHandler failed! error:%w", err)
+	}
+
+	if method.FilePath != layout.GetGRPCRPCFilePath(serviceDesc.GetName(), method.Name) {
+		return nil
+	}
+	exist, err := fsutil.CheckPathExists(stubFilePath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if exist {
+		return nil
+	}
+	content, err := fsutil.ReadFile(method.FilePath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+	if err := fsutil.WriteFile(stubFilePath, content, os.ModePerm); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return fsutil.Remove(method.FilePath)
+}
+
+// rewriteHandler replaces the name, parameters & results of method with the ones expected by the RPC definition,
+// the doc comment generated by RPCTemplate is regenerated as well
+func rewriteHandler(serviceDesc *desc.ServiceDescriptor, rpcDesc *desc.MethodDescriptor,
+	method, expected *handlerMethod) error {
+	content, err := fsutil.ReadFile(method.FilePath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, method.FilePath, content, parser.ParseComments)
+	if err != nil {
+		return xerrors.Errorf("parser.ParseFile failed! error:%w", err)
+	}
+
+	funcDecl := findImplementationMethod(file, method.Name)
+	if funcDecl == nil {
+		return xerrors.Errorf("method not found! file:%v", method.FilePath)
+	}
+
+	// keep the parameter names unless the parameters change, e.g.: the request type
+	names := fieldNames(funcDecl.Type.Params)
+	currentParams := strings.Replace(strings.Join(method.qualifiedTypes(method.Params), ", "),
+		"_"+method.Name+"Server", "_"+expected.Name+"Server", -1)
+	if currentParams != strings.Join(expected.qualifiedTypes(expected.Params), ", ") {
+		names = defaultParamNames(rpcDesc)
+	}
+
+	// the packages are qualified with the aliases the file already uses, the missing ones are imported with
+	// an alias not taken, e.g.: by the package the types moved from
+	fileImports := getFileImports(file)
+	taken := make(map[string]bool)
+	for _, alias := range fileImports {
+		taken[alias] = true
+	}
+	aliases := make(map[string]string)
+	missing := make(map[string]string)
+	for importPath, alias := range expected.Imports {
+		if importedAlias, ok := fileImports[importPath]; ok {
+			aliases[alias] = importedAlias
+			continue
+		}
+		newAlias := alias
+		for index := 1; taken[newAlias]; index++ {
+			newAlias = fmt.Sprintf("%v%v", alias, index)
+		}
+		taken[newAlias] = true
+		aliases[alias] = newAlias
+		missing[importPath] = newAlias
+	}
+
+	params := make([]string, 0, len(expected.Params))
+	for index, paramType := range expected.Params {
+		params = append(params, names[index]+" "+qualifyType(paramType, aliases))
+	}
+	results := make([]string, 0, len(expected.Results))
+	for _, resultType := range expected.Results {
+		results = append(results, qualifyType(resultType, aliases))
+	}
+	signature := "(" + strings.Join(params, ", ") + ")"
+	if len(results) == 1 {
+		signature += " " + results[0]
+	} else {
+		signature += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	// replace from the end to keep the offsets valid: signature, name & the doc comment line generated
+	end := funcDecl.Type.Params.End()
+	if funcDecl.Type.Results != nil {
+		end = funcDecl.Type.Results.End()
+	}
+	rewritten := splice(content, fileSet.Position(funcDecl.Type.Params.Pos()).Offset, fileSet.Position(end).Offset,
+		signature)
+	rewritten = splice(rewritten, fileSet.Position(funcDecl.Name.Pos()).Offset,
+		fileSet.Position(funcDecl.Name.End()).Offset, expected.Name)
+	if funcDecl.Doc != nil {
+		docLine := funcDecl.Doc.List[0]
+		if strings.HasPrefix(docLine.Text, fmt.Sprintf("// %v implements ", method.Name)) {
+			rewritten = splice(rewritten, fileSet.Position(docLine.Pos()).Offset, fileSet.Position(docLine.End()).Offset,
+				handlerDocLine(serviceDesc, rpcDesc))
+		}
+	}
+
+	if !rpcDesc.IsClientStreaming() && !rpcDesc.IsServerStreaming() {
+		missing["context"] = ""
+	}
+	for importPath, alias := range missing {
+		rewritten, err = addImport(method.FilePath, rewritten, alias, importPath)
+		if err != nil {
+			return xerrors.Errorf("addImport failed! error:%w", err)
+		}
+	}
+	return writeGoFile(method.FilePath, rewritten)
+}
+
+// handlerDocLine returns the first line of doc comment generated by RPCTemplate
+func handlerDocLine(serviceDesc *desc.ServiceDescriptor, rpcDesc *desc.MethodDescriptor) string {
+	docLine := fmt.Sprintf("// %v implements %v interface", strcase.ToCamel(rpcDesc.GetName()),
+		strcase.ToCamel(serviceDesc.GetName()))
+	switch {
+	case rpcDesc.IsClientStreaming() && rpcDesc.IsServerStreaming():
+		return docLine + " (bidirectional streaming)"
+	case rpcDesc.IsClientStreaming():
+		return docLine + " (client streaming)"
+	case rpcDesc.IsServerStreaming():
+		return docLine + " (server streaming)"
+	}
+	return docLine
+}
+
+// getStreamMethodsMode returns the streaming mode told from the stream methods called by the handler with a single
+// stream parameter, e.g.: SendAndClose is called by client streaming handlers only, it's empty if none is called
+func getStreamMethodsMode(funcDecl *ast.FuncDecl) string {
+	names := fieldNames(funcDecl.Type.Params)
+	if len(names) != 1 || funcDecl.Body == nil {
+		return ""
+	}
+
+	mode := ""
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == names[0] {
+			switch selector.Sel.Name {
+			case "SendAndClose":
+				mode = "client streaming"
+			case "Send":
+				mode = "bidirectional streaming"
+			}
+		}
+		return mode == ""
+	})
+	return mode
+}
+
+// getImplementationMethods returns all the methods whose receiver is Implementation or *Implementation
+func getImplementationMethods(file *ast.File) []*ast.FuncDecl {
+	funcDecls := make([]*ast.FuncDecl, 0)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 {
+			continue
+		}
+
+		recvType := funcDecl.Recv.List[0].Type
+		if star, ok := recvType.(*ast.StarExpr); ok {
+			recvType = star.X
+		}
+		if ident, ok := recvType.(*ast.Ident); ok && ident.Name == "Implementation" {
+			funcDecls = append(funcDecls, funcDecl)
+		}
+	}
+	return funcDecls
+}
+
+// findImplementationMethod returns the method of Implementation by name
+func findImplementationMethod(file *ast.File, name string) *ast.FuncDecl {
+	for _, funcDecl := range getImplementationMethods(file) {
+		if funcDecl.Name.Name == name {
+			return funcDecl
+		}
+	}
+	return nil
+}
+
+// getFileImports returns the import path -> the name it's referenced by of the imports in file,
+// blank & dot imports are skipped
+func getFileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, importSpec := range file.Imports {
+		importPath, _ := strconv.Unquote(importSpec.Path.Value)
+		alias := path.Base(importPath)
+		if importSpec.Name != nil {
+			alias = importSpec.Name.Name
+		}
+		if alias != "_" && alias != "." {
+			imports[importPath] = alias
+		}
+	}
+	return imports
+}
+
+// fieldTypes returns the types of fields, a field with n names is expanded n times
+func fieldTypes(fields *ast.FieldList) []string {
+	types := make([]string, 0)
+	if fields == nil {
+		return types
+	}
+	for _, field := range fields.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for index := 0; index < count; index++ {
+			types = append(types, typeName(field.Type))
+		}
+	}
+	return types
+}
+
+// fieldNames returns the names of fields, unnamed fields are named '_'
+func fieldNames(fields *ast.FieldList) []string {
+	names := make([]string, 0)
+	if fields == nil {
+		return names
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			names = append(names, "_")
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// typeName returns the qualified type name, e.g.: *pb.Request
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + typeName(t.X)
+	case *ast.SelectorExpr:
+		return typeName(t.X) + "." + t.Sel.Name
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// stripPackages returns the types without package names, e.g.: *pb.Request -> *Request
+func stripPackages(types []string) []string {
+	stripped := make([]string, 0, len(types))
+	for _, typ := range types {
+		pointer := ""
+		if strings.HasPrefix(typ, "*") {
+			pointer, typ = "*", strings.TrimPrefix(typ, "*")
+		}
+		if index := strings.LastIndex(typ, "."); index >= 0 {
+			typ = typ[index+1:]
+		}
+		stripped = append(stripped, pointer+typ)
+	}
+	return stripped
+}
+
+// qualifyType replaces the package alias of type with the one in aliases, e.g.: *pb.Request -> *proto.Request
+func qualifyType(name string, aliases map[string]string) string {
+	pointer := ""
+	if strings.HasPrefix(name, "*") {
+		pointer, name = "*", strings.TrimPrefix(name, "*")
+	}
+	index := strings.Index(name, ".")
+	if index < 0 {
+		return pointer + name
+	}
+	alias, ok := aliases[name[:index]]
+	if !ok {
+		alias = name[:index]
+	}
+	return pointer + alias + name[index:]
+}
+
+// defaultParamNames returns the parameter names used by RPCTemplate
+func defaultParamNames(rpcDesc *desc.MethodDescriptor) []string {
+	switch {
+	case rpcDesc.IsClientStreaming():
+		return []string{"stream"}
+	case rpcDesc.IsServerStreaming():
+		return []string{"req", "stream"}
+	}
+	return []string{"ctx", "req"}
+}
+
+// hasNonImportDecls returns true if there is any declaration other than imports
+func hasNonImportDecls(file *ast.File) bool {
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// addImport adds import path with the name to the Go source if it's not imported yet, the name is optional
+func addImport(filePath string, content []byte, name, importPath string) ([]byte, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, content, parser.ImportsOnly)
+	if err != nil {
+		return nil, xerrors.Errorf("parser.ParseFile failed! error:%w", err)
+	}
+
+	for _, importSpec := range file.Imports {
+		if path, _ := strconv.Unquote(importSpec.Path.Value); path == importPath {
+			return content, nil
+		}
+	}
+
+	spec := strconv.Quote(importPath)
+	if name != "" {
+		spec = name + " " + spec
+	}
+
+	// insert into the first import declaration, or create one after the package clause
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		if genDecl.Lparen.IsValid() && len(genDecl.Specs) > 0 {
+			// standard packages join the first group while others join the last one,
+			// a new group is started if the group is of the other kind
+			first, last := genDecl.Specs[0].(*ast.ImportSpec), genDecl.Specs[len(genDecl.Specs)-1].(*ast.ImportSpec)
+			if isStandardImport(importPath) {
+				offset := fileSet.Position(first.Pos()).Offset
+				if !isStandardImport(first.Path.Value) {
+					return splice(content, offset, offset, spec+"\n\n"), nil
+				}
+				return splice(content, offset, offset, spec+"\n"), nil
+			}
+			offset := fileSet.Position(last.End()).Offset
+			if isStandardImport(last.Path.Value) {
+				return splice(content, offset, offset, "\n\n"+spec), nil
+			}
+			return splice(content, offset, offset, "\n"+spec), nil
+		}
+		if genDecl.Lparen.IsValid() {
+			offset := fileSet.Position(genDecl.Lparen).Offset + 1
+			return splice(content, offset, offset, "\n"+spec+"\n"), nil
+		}
+		offset := fileSet.Position(genDecl.Pos()).Offset
+		return splice(content, offset, offset, "import "+spec+"\n"), nil
+	}
+	offset := fileSet.Position(file.Name.End()).Offset
+	return splice(content, offset, offset, "\n\nimport "+spec+"\n"), nil
+}
+
+// isStandardImport checks if the import path (quoted or not) is a standard package, e.g.: context, net/http
+func isStandardImport(importPath string) bool {
+	return !strings.Contains(strings.Split(strings.Trim(importPath, "\""), "/")[0], ".")
+}
+
+// removeUnusedImports removes the imports which are not referenced by the Go source,
+// only named imports and standard packages are checked because other package names are unknown without loading them
+func removeUnusedImports(filePath string, content []byte) ([]byte, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, xerrors.Errorf("parser.ParseFile failed! error:%w", err)
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	type span struct{ begin, end int }
+	spans := make([]span, 0)
+	for _, importSpec := range file.Imports {
+		importPath, _ := strconv.Unquote(importSpec.Path.Value)
+		name := path.Base(importPath)
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		} else if !isStandardImport(importPath) {
+			continue
+		}
+		if name == "_" || name == "." || used[name] {
+			continue
+		}
+		spans = append(spans, span{fileSet.Position(importSpec.Pos()).Offset, fileSet.Position(importSpec.End()).Offset})
+	}
+
+	// remove from the end to keep the offsets valid
+	sort.Slice(spans, func(i, j int) bool { return spans[i].begin > spans[j].begin })
+	for _, s := range spans {
+		content = splice(content, s.begin, s.end, "")
+	}
+	return content, nil
+}
+
+// splice replaces content[begin:end] with text
+func splice(content []byte, begin, end int, text string) []byte {
+	result := make([]byte, 0, len(content)+len(text))
+	result = append(result, content[:begin]...)
+	result = append(result, text...)
+	return append(result, content[end:]...)
+}
+
+// writeGoFile removes unused imports, formats the Go source and writes to file
+func writeGoFile(filePath string, content []byte) error {
+	content, err := removeUnusedImports(filePath, content)
+	if err != nil {
+		return xerrors.Errorf("removeUnusedImports failed! error:%w", err)
+	}
+
+	formatted, err := format.Source(content)
+	if err != nil {
+		return xerrors.Errorf("format.Source failed! file:%v, error:%w", filePath, err)
+	}
+
+	if err := fsutil.WriteFile(filePath, formatted, os.ModePerm); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,615 @@
+package generate
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+)
+
+// handlerProtos are the protos of service whose handlers are checked, the go packages are out of api/protobuf_spec
+var handlerProtos = map[string]string{
+	"order/order.proto": `syntax = "proto3";
+package order;
+option go_package = "example.com/project/api/gen/order";
+import "common/money.proto";
+message GetRequest { string id = 1; }
+message Order { string id = 1; }
+service OrderService {
+  rpc Fetch(GetRequest) returns (Order);
+  rpc Watch(GetRequest) returns (stream Order);
+  rpc Upload(stream Order) returns (Order);
+  rpc Quote(GetRequest) returns (common.Money);
+  rpc Cancel(GetRequest) returns (Order);
+}
+`,
+	"common/money.proto": `syntax = "proto3";
+package common;
+option go_package = "example.com/project/api/gen/common";
+message Money { int64 units = 1; }
+`,
+}
+
+// parseHandlerProto parses the order service & returns its go package, layout & options
+func parseHandlerProto(t *testing.T, root string) (*protoGoPackage, *desc.ServiceDescriptor,
+	directory.GRPCServiceLayout, *option.Options) {
+	parser := protoparse.Parser{Accessor: protoparse.FileContentsFromMap(handlerProtos)}
+	fileDescs, err := parser.ParseFiles("order/order.proto")
+	if err != nil {
+		t.Fatalf("protoparse.Parser.ParseFiles failed! error:%v", err)
+	}
+
+	opts := []option.Option{option.WithOutputPath(root), option.WithGoModule("example.com/project")}
+	layout := directory.NewGRPCLayout(opts...)
+	options := option.NewOptions(opts...)
+	goPackage := getProtoGoPackage(layout.GetAPIProtobufFilePath("order/order.proto"), fileDescs[0], layout, options)
+	return goPackage, fileDescs[0].GetServices()[0], layout, options
+}
+
+func TestStripPackages(t *testing.T) {
+	tests := []struct {
+		types []string
+		want  []string
+	}{
+		{types: []string{"context.Context", "*pb.Request"}, want: []string{"Context", "*Request"}},
+		{types: []string{"pb.Service_RPCServer", "error"}, want: []string{"Service_RPCServer", "error"}},
+		{types: []string{"*commonProto.Money"}, want: []string{"*Money"}},
+		{types: []string{}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := stripPackages(tt.types); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("stripPackages(%q) = %q, want %q", tt.types, got, tt.want)
+		}
+	}
+}
+
+func TestQualifyType(t *testing.T) {
+	aliases := map[string]string{"pb": "orderProto", "commonProto": "commonProto"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "*pb.Request", want: "*orderProto.Request"},
+		{name: "pb.Service_RPCServer", want: "orderProto.Service_RPCServer"},
+		{name: "*commonProto.Money", want: "*commonProto.Money"},
+		{name: "context.Context", want: "context.Context"},
+		{name: "error", want: "error"},
+	}
+
+	for _, tt := range tests {
+		if got := qualifyType(tt.name, aliases); got != tt.want {
+			t.Errorf("qualifyType(%v) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		importName string
+		importPath string
+		want       string
+	}{
+		{
+			name:       "standard package joins the first group",
+			content:    "package a\n\nimport (\n\t\"io\"\n\n\tpb \"example.com/pb\"\n)\n",
+			importPath: "context",
+			want:       "package a\n\nimport (\n\t\"context\"\n\t\"io\"\n\n\tpb \"example.com/pb\"\n)\n",
+		},
+		{
+			name:       "other package joins the last group",
+			content:    "package a\n\nimport (\n\t\"io\"\n\n\tpb \"example.com/pb\"\n)\n",
+			importName: "commonProto",
+			importPath: "example.com/common",
+			want: "package a\n\nimport (\n\t\"io\"\n\n\tcommonProto \"example.com/common\"\n" +
+				"\tpb \"example.com/pb\"\n)\n",
+		},
+		{
+			name:       "standard package starts the first group",
+			content:    "package a\n\nimport (\n\tpb \"example.com/pb\"\n)\n",
+			importPath: "context",
+			want:       "package a\n\nimport (\n\t\"context\"\n\n\tpb \"example.com/pb\"\n)\n",
+		},
+		{
+			name:       "other package starts the last group",
+			content:    "package a\n\nimport (\n\t\"io\"\n)\n",
+			importName: "pb",
+			importPath: "example.com/pb",
+			want:       "package a\n\nimport (\n\t\"io\"\n\n\tpb \"example.com/pb\"\n)\n",
+		},
+		{
+			name:       "already imported",
+			content:    "package a\n\nimport (\n\tpb \"example.com/pb\"\n)\n",
+			importName: "orderProto",
+			importPath: "example.com/pb",
+			want:       "package a\n\nimport (\n\tpb \"example.com/pb\"\n)\n",
+		},
+		{
+			name:       "empty import declaration",
+			content:    "package a\n\nimport ()\n",
+			importPath: "context",
+			want:       "package a\n\nimport (\n\t\"context\"\n)\n",
+		},
+		{
+			name:       "import without parentheses",
+			content:    "package a\n\nimport \"io\"\n",
+			importPath: "context",
+			want:       "package a\n\nimport \"context\"\nimport \"io\"\n",
+		},
+		{
+			name:       "no import declaration",
+			content:    "package a\n\nfunc f() {}\n",
+			importPath: "context",
+			want:       "package a\n\nimport \"context\"\n\nfunc f() {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := addImport("a.go", []byte(tt.content), tt.importName, tt.importPath)
+			if err != nil {
+				t.Fatalf("addImport() failed! error:%v", err)
+			}
+			formatted, err := format.Source(content)
+			if err != nil {
+				t.Fatalf("format.Source failed! content:\n%s\nerror:%v", content, err)
+			}
+			if string(formatted) != tt.want {
+				t.Errorf("addImport() =\n%s\nwant\n%s", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckServiceHandlers(t *testing.T) {
+	// the imports of handler files
+	imports := map[string]string{
+		"context":                            "context",
+		"example.com/project/api/gen/order":  "pb",
+		"example.com/project/api/gen/common": "commonProto",
+	}
+	fetch := &handlerMethod{Name: "Fetch", Params: []string{"context.Context", "*pb.GetRequest"},
+		Results: []string{"*pb.Order", "error"}, Imports: imports}
+	watch := &handlerMethod{Name: "Watch", Params: []string{"*pb.GetRequest", "pb.OrderService_WatchServer"},
+		Results: []string{"error"}, Imports: imports, Send: "*pb.Order"}
+	upload := &handlerMethod{Name: "Upload", Params: []string{"pb.OrderService_UploadServer"},
+		Results: []string{"error"}, Imports: imports, Recv: "*pb.Order", Send: "*pb.Order"}
+	quote := &handlerMethod{Name: "Quote", Params: []string{"context.Context", "*pb.GetRequest"},
+		Results: []string{"*commonProto.Money", "error"}, Imports: imports}
+	cancel := &handlerMethod{Name: "Cancel", Params: []string{"context.Context", "*pb.GetRequest"},
+		Results: []string{"*pb.Order", "error"}, Imports: imports}
+
+	// with returns a copy of method modified by the function
+	with := func(method *handlerMethod, modify func(method *handlerMethod)) *handlerMethod {
+		copied := *method
+		modify(&copied)
+		return &copied
+	}
+
+	tests := []struct {
+		name              string
+		existing          []*handlerMethod
+		wantOrphans       []string
+		wantRenamed       map[string]string
+		wantMismatched    []string
+		wantModeChanged   []string
+		wantStreamChanged []string
+	}{
+		{
+			name:     "up to date",
+			existing: []*handlerMethod{fetch, watch, upload, quote, cancel},
+		},
+		{
+			name: "package aliases ignored",
+			existing: []*handlerMethod{with(quote, func(m *handlerMethod) {
+				m.Results = []string{"*money.Money", "error"}
+				m.Imports = map[string]string{"context": "context", "example.com/project/api/gen/order": "pb",
+					"example.com/project/api/gen/common": "money"}
+			})},
+		},
+		{
+			name: "type moved to another package",
+			existing: []*handlerMethod{with(quote, func(m *handlerMethod) {
+				m.Imports = map[string]string{"context": "context", "example.com/project/api/gen/order": "pb",
+					"example.com/project/api/legacy": "commonProto"}
+			})},
+			wantMismatched: []string{"Quote"},
+		},
+		{
+			name:     "stream types unknown",
+			existing: []*handlerMethod{with(upload, func(m *handlerMethod) { m.Recv, m.Send = "", "" })},
+		},
+		{
+			name:        "orphan",
+			existing:    []*handlerMethod{fetch, {Name: "Delete", Params: fetch.Params, Results: []string{"error"}, Imports: imports}},
+			wantOrphans: []string{"Delete"},
+		},
+		{
+			name:        "renamed",
+			existing:    []*handlerMethod{with(fetch, func(m *handlerMethod) { m.Name = "Get" }), watch},
+			wantOrphans: []string{"Get"},
+			wantRenamed: map[string]string{"Get": "Fetch"},
+		},
+		{
+			name: "renamed streaming",
+			existing: []*handlerMethod{with(watch, func(m *handlerMethod) {
+				m.Name, m.Params = "Subscribe", []string{"*pb.GetRequest", "pb.OrderService_SubscribeServer"}
+			})},
+			wantOrphans: []string{"Subscribe"},
+			wantRenamed: map[string]string{"Subscribe": "Watch"},
+		},
+		{
+			name:        "renamed to the RPC not implemented only",
+			existing:    []*handlerMethod{fetch, with(cancel, func(m *handlerMethod) { m.Name = "Get" })},
+			wantOrphans: []string{"Get"},
+			wantRenamed: map[string]string{"Get": "Cancel"},
+		},
+		{
+			name: "signature changed",
+			existing: []*handlerMethod{
+				with(quote, func(m *handlerMethod) { m.Results = fetch.Results }),
+				with(cancel, func(m *handlerMethod) { m.Params = []string{"context.Context", "*pb.Order"} }),
+			},
+			wantMismatched: []string{"Quote", "Cancel"},
+		},
+		{
+			name: "streaming mode changed",
+			existing: []*handlerMethod{
+				with(watch, func(m *handlerMethod) { m.Params, m.Results, m.Send = fetch.Params, fetch.Results, "" }),
+				with(fetch, func(m *handlerMethod) {
+					m.Params, m.Results = []string{"pb.OrderService_FetchServer"}, []string{"error"}
+				}),
+				with(upload, func(m *handlerMethod) { m.Mode = "bidirectional streaming" }),
+			},
+			wantModeChanged: []string{"Watch", "Fetch", "Upload"},
+		},
+		{
+			name:     "streaming mode unknown",
+			existing: []*handlerMethod{with(upload, func(m *handlerMethod) { m.Mode = "" })},
+		},
+		{
+			name: "renamed to another streaming mode",
+			existing: []*handlerMethod{with(upload, func(m *handlerMethod) {
+				m.Name, m.Params, m.Mode = "Push", []string{"pb.OrderService_PushServer"}, "bidirectional streaming"
+			})},
+			wantOrphans: []string{"Push"},
+		},
+		{
+			name:              "stream types changed",
+			existing:          []*handlerMethod{with(upload, func(m *handlerMethod) { m.Recv = "*pb.GetRequest" })},
+			wantStreamChanged: []string{"Upload"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goPackage, serviceDesc, layout, options := parseHandlerProto(t, "/project")
+			report := checkServiceHandlers(goPackage, serviceDesc, tt.existing, layout, options)
+
+			names := func(methods []*handlerMethod) []string {
+				result := make([]string, 0, len(methods))
+				for _, method := range methods {
+					result = append(result, method.Name)
+				}
+				return result
+			}
+			if tt.wantRenamed == nil {
+				tt.wantRenamed = map[string]string{}
+			}
+			if got := names(report.Orphans); !equalNames(got, tt.wantOrphans) {
+				t.Errorf("orphans = %q, want %q", got, tt.wantOrphans)
+			}
+			if !reflect.DeepEqual(report.Renamed, tt.wantRenamed) {
+				t.Errorf("renamed = %v, want %v", report.Renamed, tt.wantRenamed)
+			}
+			if got := names(report.Mismatched); !equalNames(got, tt.wantMismatched) {
+				t.Errorf("mismatched = %q, want %q", got, tt.wantMismatched)
+			}
+			if got := names(report.ModeChanged); !equalNames(got, tt.wantModeChanged) {
+				t.Errorf("mode changed = %q, want %q", got, tt.wantModeChanged)
+			}
+			if got := names(report.StreamChanged); !equalNames(got, tt.wantStreamChanged) {
+				t.Errorf("stream changed = %q, want %q", got, tt.wantStreamChanged)
+			}
+			if report.IsEmpty() != (len(tt.wantOrphans)+len(tt.wantMismatched)+len(tt.wantModeChanged)+
+				len(tt.wantStreamChanged) == 0) {
+				t.Errorf("IsEmpty() = %v, report:\n%v", report.IsEmpty(), report)
+			}
+		})
+	}
+}
+
+func TestRewriteHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		rpc     string
+		content string
+		want    string
+	}{
+		{
+			name:   "response type changed, parameter names kept",
+			method: "Quote",
+			rpc:    "Quote",
+			content: `package order_service
+
+import (
+	"context"
+
+	pb "example.com/project/api/gen/order"
+)
+
+// Quote implements OrderService interface
+func (service Implementation) Quote(c context.Context, request *pb.GetRequest) (*pb.Order, error) {
+	return nil, nil
+}
+`,
+			want: `package order_service
+
+import (
+	"context"
+
+	commonProto "example.com/project/api/gen/common"
+	pb "example.com/project/api/gen/order"
+)
+
+// Quote implements OrderService interface
+func (service Implementation) Quote(c context.Context, request *pb.GetRequest) (*commonProto.Money, error) {
+	return nil, nil
+}
+`,
+		},
+		{
+			name:   "response type moved to another package",
+			method: "Quote",
+			rpc:    "Quote",
+			content: `package order_service
+
+import (
+	"context"
+
+	commonProto "example.com/project/api/legacy"
+	pb "example.com/project/api/gen/order"
+)
+
+// Quote implements OrderService interface
+func (service Implementation) Quote(ctx context.Context, req *pb.GetRequest) (*commonProto.Money, error) {
+	return nil, nil
+}
+`,
+			want: `package order_service
+
+import (
+	"context"
+
+	commonProto1 "example.com/project/api/gen/common"
+	pb "example.com/project/api/gen/order"
+)
+
+// Quote implements OrderService interface
+func (service Implementation) Quote(ctx context.Context, req *pb.GetRequest) (*commonProto1.Money, error) {
+	return nil, nil
+}
+`,
+		},
+		{
+			name:   "renamed with the alias of file",
+			method: "Get",
+			rpc:    "Fetch",
+			content: `package order_service
+
+import (
+	"context"
+
+	orderProto "example.com/project/api/gen/order"
+)
+
+// Get implements OrderService interface
+func (service *Implementation) Get(ctx context.Context, in *orderProto.GetRequest) (*orderProto.Order, error) {
+	return &orderProto.Order{Id: in.Id}, nil
+}
+`,
+			want: `package order_service
+
+import (
+	"context"
+
+	orderProto "example.com/project/api/gen/order"
+)
+
+// Fetch implements OrderService interface
+func (service *Implementation) Fetch(ctx context.Context, in *orderProto.GetRequest) (*orderProto.Order, error) {
+	return &orderProto.Order{Id: in.Id}, nil
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			goPackage, serviceDesc, layout, options := parseHandlerProto(t, root)
+			filePath := layout.GetGRPCRPCFilePath(serviceDesc.GetName(), tt.method)
+			if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+				t.Fatalf("os.MkdirAll failed! error:%v", err)
+			}
+			if err := ioutil.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+			}
+
+			methods, err := parseServiceHandlers(filepath.Dir(filePath), "")
+			if err != nil || len(methods) != 1 {
+				t.Fatalf("parseServiceHandlers() = %v, %v, want 1 method", methods, err)
+			}
+			rpcDesc := serviceDesc.FindMethodByName(tt.rpc)
+			expected := expectedHandlerMethod(goPackage, serviceDesc, rpcDesc, layout, options)
+			if err := rewriteHandler(serviceDesc, rpcDesc, methods[0], expected); err != nil {
+				t.Fatalf("rewriteHandler() failed! error:%v", err)
+			}
+
+			content, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("rewriteHandler() =\n%s\nwant\n%s", content, tt.want)
+			}
+		})
+	}
+}
+
+// streamingModes are the RPC definitions of each streaming mode, %v is replaced by the RPC name
+var streamingModes = map[string]string{
+	"Unary":        "rpc %v(Request) returns (Response);",
+	"ServerStream": "rpc %v(Request) returns (stream Response);",
+	"ClientStream": "rpc %v(stream Request) returns (Response);",
+	"BidiStream":   "rpc %v(stream Request) returns (stream Response);",
+}
+
+// modesProto returns the proto of service Modes with the RPCs, RPC name -> streaming mode
+func modesProto(goPackage string, rpcs map[string]string) string {
+	names := make([]string, 0, len(rpcs))
+	for name := range rpcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	content := fmt.Sprintf("syntax = \"proto3\";\npackage modes;\noption go_package = \"%v\";\n"+
+		"message Request { string id = 1; }\nmessage Response { string id = 1; }\nservice Modes {\n", goPackage)
+	for _, name := range names {
+		content += "  " + fmt.Sprintf(streamingModes[rpcs[name]], name) + "\n"
+	}
+	return content + "}\n"
+}
+
+// generateModesHandlers compiles the proto and updates the handlers the same way as 'gofra service update' does
+func generateModesHandlers(t *testing.T, content string, layout directory.GRPCServiceLayout, opts ...option.Option) {
+	options := option.NewOptions(opts...)
+	protoPath := layout.GetAPIProtobufFilePath("modes/modes.proto")
+	if err := os.MkdirAll(filepath.Dir(protoPath), 0755); err != nil {
+		t.Fatalf("os.MkdirAll failed! error:%v", err)
+	}
+	if err := ioutil.WriteFile(protoPath, []byte(content), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+	}
+
+	protoFileIncludePath := getProtoFileIncludePath(layout, options)
+	fileDescs, err := parseProtoFile(protoPath, protoFileIncludePath)
+	if err != nil {
+		t.Fatalf("parseProtoFile failed! error:%v", err)
+	}
+	goPackage := getProtoGoPackage(protoPath, fileDescs[0], layout, options)
+	serviceDesc := fileDescs[0].GetServices()[0]
+
+	existing, err := parseServiceHandlers(layout.GetGRPCServicePath(serviceDesc.GetName()), goPackage.Dir)
+	if err != nil {
+		t.Fatalf("parseServiceHandlers failed! error:%v", err)
+	}
+	skipped := make(map[string]bool)
+	if !options.FixHandlers {
+		skipped = getRenamedRPCs(goPackage, serviceDesc, existing, layout, options)
+	}
+
+	compiler := &pb.NativeCompiler{WorkDir: options.OutputPath, GoModule: goPackage.GoModule}
+	if err := compiler.CompileGRPC(protoPath, protoFileIncludePath); err != nil {
+		t.Fatalf("CompileGRPC failed! error:%v", err)
+	}
+	if err := generateServiceFiles(goPackage, true, serviceDesc, skipped, layout, opts...); err != nil {
+		t.Fatalf("generateServiceFiles failed! error:%v", err)
+	}
+	if err := updateServiceHandlers(goPackage, serviceDesc, existing, layout, opts...); err != nil {
+		t.Fatalf("updateServiceHandlers failed! error:%v", err)
+	}
+}
+
+// TestFixServiceHandlersBuild changes the streaming mode between every two modes & renames an RPC, the handlers
+// fixed must compile with the code generated from the new proto
+func TestFixServiceHandlersBuild(t *testing.T) {
+	root, cleanup := newBuildProject(t)
+	defer cleanup()
+
+	before, after := map[string]string{"SayHello": "Unary"}, map[string]string{"SayHi": "Unary"}
+	modeChanged := make([]string, 0)
+	for from := range streamingModes {
+		for to := range streamingModes {
+			if from != to {
+				before[from+"To"+to], after[from+"To"+to] = from, to
+				modeChanged = append(modeChanged, from+"To"+to)
+			}
+		}
+	}
+
+	opts := []option.Option{option.WithOutputPath(root), option.WithGoModule(buildModule), option.WithIgnoreExist(true)}
+	layout := directory.NewGRPCLayout(opts...)
+	servicePath := layout.GetGRPCServicePath("Modes")
+	goPackage := buildModule + "/api/gen/modes"
+	generateModesHandlers(t, modesProto(goPackage, before), layout, opts...)
+	goBuild(t, root)
+
+	// the renamed handler is reported only, no stub is generated next to it
+	generateModesHandlers(t, modesProto(goPackage, after), layout, opts...)
+	if _, err := os.Stat(layout.GetGRPCRPCFilePath("Modes", "SayHi")); !os.IsNotExist(err) {
+		t.Fatalf("stub of SayHi is generated before the renamed handler is fixed! error:%v", err)
+	}
+	if _, err := os.Stat(layout.GetGRPCRPCFilePath("Modes", "SayHello")); err != nil {
+		t.Fatalf("handler of SayHello is removed before it's fixed! error:%v", err)
+	}
+
+	generateModesHandlers(t, modesProto(goPackage, after), layout, append(opts, option.WithFixHandlers(true))...)
+	if _, err := os.Stat(layout.GetGRPCRPCFilePath("Modes", "SayHello")); !os.IsNotExist(err) {
+		t.Fatalf("handler of SayHello is kept after it's renamed! error:%v", err)
+	}
+	content, err := ioutil.ReadFile(layout.GetGRPCRPCFilePath("Modes", "SayHi"))
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+	}
+	if !strings.Contains(string(content), ") SayHi(") {
+		t.Errorf("handler of SayHi is not renamed:\n%s", content)
+	}
+
+	// the old bodies are moved to the deprecated file with their tests
+	deprecated, err := ioutil.ReadFile(filepath.Join(servicePath, deprecatedFileName))
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+	}
+	for _, name := range modeChanged {
+		if !strings.Contains(string(deprecated), ") "+name+"(") {
+			t.Errorf("handler of %v is not moved to %v", name, deprecatedFileName)
+		}
+		if _, err := os.Stat(filepath.Join(servicePath, "_"+filepath.Base(
+			layout.GetGRPCRPCTestFilePath("Modes", name)))); err != nil {
+			t.Errorf("test of %v is not moved! error:%v", name, err)
+		}
+	}
+	goBuild(t, root)
+}
+
+// equalNames compares the names regardless of order, nil equals to empty
+func equalNames(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	got, want = append([]string{}, got...), append([]string{}, want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	return reflect.DeepEqual(got, want)
+}
@@ -0,0 +1,426 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// mainServerFuncName is the function of main.go in which services are registered
+	mainServerFuncName = "startGRPCServer"
+
+	// markers generated in main.go as '// @PROTO_STUB' (or '/*@PROTO_STUB*/' by former versions),
+	// new lines of code are inserted before them if they still exist
+	protoImportMarker     = "@PROTO_STUB"
+	serviceImportMarker   = "@HANDLER_STUB"
+	serviceRegisterMarker = "@REGISTER_STUB"
+)
+
+// mainEditFunc edits the parsed main.go or other go file, it returns the new content or nil if nothing changed
+type mainEditFunc func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error)
+
+// addServiceImportToMain adds service import to main.go
+// something like: healthCheck "github.com/foo/bar/internal/service/grpc/health_check"
+func addServiceImportToMain(layout directory.GRPCServiceLayout, goModule, serviceName string) error {
+	return addImportToMain(layout, layout.GetServiceImportStub(goModule, serviceName), serviceImportMarker)
+}
+
+// addServiceProtoImportToMain adds service proto import to main.go
+// something like: healthCheckProto "github.com/foo/bar/api/protobuf_spec/health_check"
+func addServiceProtoImportToMain(layout directory.GRPCServiceLayout, goPackage *protoGoPackage) error {
+	alias, err := getProtoAlias(layout.GetMainFilePath(), layout, goPackage)
+	if err != nil {
+		return xerrors.Errorf("getProtoAlias failed! error:%w", err)
+	}
+	return addImportToMain(layout, layout.GetProtoImportStub(alias, goPackage.ImportPath), protoImportMarker)
+}
+
+// addServiceRegisterToMain adds service register to main.go
+// something like: healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+func addServiceRegisterToMain(layout directory.GRPCServiceLayout, serviceName string, goPackage *protoGoPackage) error {
+	alias, err := getProtoAlias(layout.GetMainFilePath(), layout, goPackage)
+	if err != nil {
+		return xerrors.Errorf("getProtoAlias failed! error:%w", err)
+	}
+	register := layout.GetServiceRegisterStub(alias, serviceName)
+	registerExpr, err := parser.ParseExpr(register)
+	if err != nil {
+		return xerrors.Errorf("parser.ParseExpr failed! register:%v, error:%w", register, err)
+	}
+
+	return editMainFile(layout, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+		funcDecl, err := findMainServerFunc(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if findStmt(fileSet, funcDecl, registerExpr) != nil {
+			return nil, nil
+		}
+
+		// insert before the marker if it still exists
+		if offset := findComment(fileSet, file, funcDecl, serviceRegisterMarker); offset >= 0 {
+			return splice(content, offset, offset, register+"\n"), nil
+		}
+
+		// insert after the last service register following 'server := grpc.NewServer(...)'
+		anchor := findRegisterAnchor(funcDecl)
+		if anchor == nil {
+			return nil, xerrors.Errorf("'server := grpc.NewServer(...)' not found in function %v! file:%v",
+				mainServerFuncName, layout.GetMainFilePath())
+		}
+		offset := fileSet.Position(anchor.End()).Offset
+		return splice(content, offset, offset, "\n"+register), nil
+	})
+}
+
+// removeServiceRegisterFromMain removes service register from main.go
+func removeServiceRegisterFromMain(layout directory.GRPCServiceLayout, serviceName string,
+	goPackage *protoGoPackage) error {
+	alias, err := getProtoAlias(layout.GetMainFilePath(), layout, goPackage)
+	if err != nil {
+		return xerrors.Errorf("getProtoAlias failed! error:%w", err)
+	}
+	register := layout.GetServiceRegisterStub(alias, serviceName)
+	registerExpr, err := parser.ParseExpr(register)
+	if err != nil {
+		return xerrors.Errorf("parser.ParseExpr failed! register:%v, error:%w", register, err)
+	}
+
+	return editMainFile(layout, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+		funcDecl, err := findMainServerFunc(file)
+		if err != nil {
+			return nil, err
+		}
+
+		stmt := findStmt(fileSet, funcDecl, registerExpr)
+		if stmt == nil {
+			return nil, nil
+		}
+		begin, end := lineSpan(content, fileSet.Position(stmt.Pos()).Offset, fileSet.Position(stmt.End()).Offset)
+		return splice(content, begin, end, ""), nil
+	})
+}
+
+// addImportToMain adds import to main.go, e.g.: alias "github.com/foo/bar"
+func addImportToMain(layout directory.GRPCServiceLayout, importStub, marker string) error {
+	return addImportToFile(layout.GetMainFilePath(), importStub, marker)
+}
+
+// addImportToFile adds import to go file before the marker, e.g.: alias "github.com/foo/bar",
+// it fails if the alias is taken by another import path
+func addImportToFile(filePath, importStub, marker string) error {
+	name, importPath, err := parseImportStub(importStub)
+	if err != nil {
+		return xerrors.Errorf("parseImportStub failed! error:%w", err)
+	}
+
+	return editGoFile(filePath, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+		if findImport(file, importPath) != nil {
+			return nil, nil
+		}
+		if name != "" {
+			for _, importSpec := range file.Imports {
+				if importSpec.Name != nil && importSpec.Name.Name == name {
+					return nil, xerrors.Errorf("alias %v is taken by import %v! import:%v",
+						name, importSpec.Path.Value, importStub)
+				}
+			}
+		}
+
+		importDecl := findImportDecl(file)
+		if importDecl == nil {
+			return nil, xerrors.Errorf("import declaration not found! file:%v", filePath)
+		}
+
+		// insert before the marker if it still exists, otherwise at the end of import declaration
+		if offset := findComment(fileSet, file, importDecl, marker); offset >= 0 {
+			return splice(content, offset, offset, importStub+"\n"), nil
+		}
+		offset := fileSet.Position(importDecl.Rparen).Offset
+		if len(bytes.TrimSpace(content[bytes.LastIndexByte(content[:offset], '\n')+1:offset])) != 0 {
+			importStub = "\n" + importStub
+		}
+		return splice(content, offset, offset, importStub+"\n"), nil
+	})
+}
+
+// removeImportFromMain removes import from main.go
+func removeImportFromMain(layout directory.GRPCServiceLayout, importStub string) error {
+	return removeImportFromFile(layout.GetMainFilePath(), importStub)
+}
+
+// removeImportFromFile removes import from go file
+func removeImportFromFile(filePath, importStub string) error {
+	_, importPath, err := parseImportStub(importStub)
+	if err != nil {
+		return xerrors.Errorf("parseImportStub failed! error:%w", err)
+	}
+
+	return editGoFile(filePath, func(fileSet *token.FileSet, file *ast.File, content []byte) ([]byte, error) {
+		importSpec := findImport(file, importPath)
+		if importSpec == nil {
+			return nil, nil
+		}
+		begin, end := lineSpan(content,
+			fileSet.Position(importSpec.Pos()).Offset, fileSet.Position(importSpec.End()).Offset)
+		return splice(content, begin, end, ""), nil
+	})
+}
+
+// editMainFile parses main.go, applies the edit and writes the formatted result back
+func editMainFile(layout directory.GRPCServiceLayout, edit mainEditFunc) error {
+	return editGoFile(layout.GetMainFilePath(), edit)
+}
+
+// editGoFile parses go file, applies the edit and writes the formatted result back
+func editGoFile(filePath string, edit mainEditFunc) error {
+	// read file content
+	content, err := fsutil.ReadFile(filePath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+
+	// parse & edit
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, content, parser.ParseComments)
+	if err != nil {
+		return xerrors.Errorf("parser.ParseFile failed! file:%v, error:%w", filePath, err)
+	}
+
+	content, err = edit(fileSet, file, content)
+	if err != nil {
+		return xerrors.Errorf("edit file failed! file:%v, error:%w", filePath, err)
+	}
+	if content == nil {
+		return nil
+	}
+
+	// write to file
+	content, err = format.Source(content)
+	if err != nil {
+		return xerrors.Errorf("format.Source failed! file:%v, error:%w", filePath, err)
+	}
+	if err := fsutil.WriteFile(filePath, content, os.ModePerm); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// parseImportStub returns the name (empty if not named) & the import path of import stub returned by layout,
+// e.g.: alias "github.com/foo/bar"
+func parseImportStub(importStub string) (string, string, error) {
+	fields := strings.Fields(importStub)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", "", xerrors.Errorf("invalid import! import:%v", importStub)
+	}
+
+	importPath, err := strconv.Unquote(fields[len(fields)-1])
+	if err != nil {
+		return "", "", xerrors.Errorf("strconv.Unquote failed! import:%v, error:%w", importStub, err)
+	}
+	if len(fields) == 1 {
+		return "", importPath, nil
+	}
+	return fields[0], importPath, nil
+}
+
+// getProtoAlias returns the alias the go package of proto is imported as in the go file, a new alias is chosen
+// if it's not imported yet: the default one, or prefixed with the parent directories of import path until it's
+// not taken by other imports, e.g.: go packages named v1 are imported as v1Proto, userV1Proto, orderV1Proto...
+func getProtoAlias(filePath string, layout directory.GRPCServiceLayout, goPackage *protoGoPackage) (string, error) {
+	alias := layout.GetProtoPackageAlias(goPackage.Name)
+	isExist, err := fsutil.CheckPathExists(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if !isExist {
+		return alias, nil
+	}
+
+	content, err := fsutil.ReadFile(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, content, parser.ImportsOnly)
+	if err != nil {
+		return "", xerrors.Errorf("parser.ParseFile failed! file:%v, error:%w", filePath, err)
+	}
+
+	taken := make(map[string]bool)
+	for _, importSpec := range file.Imports {
+		importPath, _ := strconv.Unquote(importSpec.Path.Value)
+		if importPath == goPackage.ImportPath {
+			if importSpec.Name == nil {
+				return goPackage.Name, nil
+			}
+			return importSpec.Name.Name, nil
+		}
+		if importSpec.Name != nil {
+			taken[importSpec.Name.Name] = true
+		} else {
+			taken[path.Base(importPath)] = true
+		}
+	}
+
+	packageName := goPackage.Name
+	dirs := strings.Split(path.Dir(goPackage.ImportPath), "/")
+	for index := len(dirs) - 1; taken[alias] && index >= 0; index-- {
+		packageName = strings.NewReplacer("-", "_", ".", "_").Replace(dirs[index]) + "_" + packageName
+		alias = layout.GetProtoPackageAlias(packageName)
+	}
+	for index := 2; taken[alias]; index++ {
+		alias = fmt.Sprintf("%v%v", layout.GetProtoPackageAlias(goPackage.Name), index)
+	}
+	return alias, nil
+}
+
+// findImport returns the import spec by path
+func findImport(file *ast.File, importPath string) *ast.ImportSpec {
+	for _, importSpec := range file.Imports {
+		if path, _ := strconv.Unquote(importSpec.Path.Value); path == importPath {
+			return importSpec
+		}
+	}
+	return nil
+}
+
+// findImportDecl returns the first import declaration with parentheses
+func findImportDecl(file *ast.File) *ast.GenDecl {
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT && genDecl.Lparen.IsValid() {
+			return genDecl
+		}
+	}
+	return nil
+}
+
+// findMainServerFunc returns the function in which services are registered
+func findMainServerFunc(file *ast.File) (*ast.FuncDecl, error) {
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil &&
+			funcDecl.Name.Name == mainServerFuncName && funcDecl.Body != nil {
+			return funcDecl, nil
+		}
+	}
+	return nil, xerrors.Errorf("function %v not found in main.go, services can't be registered automatically", mainServerFuncName)
+}
+
+// findComment returns the offset of comment inside node, -1 if not found
+func findComment(fileSet *token.FileSet, file *ast.File, node ast.Node, text string) int {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			commentText := strings.TrimPrefix(comment.Text, "//")
+			commentText = strings.TrimSuffix(strings.TrimPrefix(commentText, "/*"), "*/")
+			if strings.TrimSpace(commentText) == text && comment.Pos() > node.Pos() && comment.End() < node.End() {
+				return fileSet.Position(comment.Pos()).Offset
+			}
+		}
+	}
+	return -1
+}
+
+// findStmt returns the top level statement of function whose expression equals to expr
+func findStmt(fileSet *token.FileSet, funcDecl *ast.FuncDecl, expr ast.Expr) ast.Stmt {
+	target := nodeString(token.NewFileSet(), expr)
+	for _, stmt := range funcDecl.Body.List {
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		if ok && nodeString(fileSet, exprStmt.X) == target {
+			return stmt
+		}
+	}
+	return nil
+}
+
+// findRegisterAnchor returns the last service register after 'server := grpc.NewServer(...)',
+// or the assignment itself if no service is registered
+func findRegisterAnchor(funcDecl *ast.FuncDecl) ast.Stmt {
+	var anchor ast.Stmt
+	for _, stmt := range funcDecl.Body.List {
+		if anchor == nil {
+			if isNewServerAssign(stmt) {
+				anchor = stmt
+			}
+			continue
+		}
+		if !isServiceRegister(stmt) {
+			break
+		}
+		anchor = stmt
+	}
+	return anchor
+}
+
+// isNewServerAssign checks if stmt is 'server := grpc.NewServer(...)'
+func isNewServerAssign(stmt ast.Stmt) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	if ident, ok := assign.Lhs[0].(*ast.Ident); !ok || ident.Name != "server" {
+		return false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "NewServer"
+}
+
+// isServiceRegister checks if stmt is something like 'fooProto.RegisterFooServer(server, foo.Implementation{})'
+func isServiceRegister(stmt ast.Stmt) bool {
+	exprStmt, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := exprStmt.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasPrefix(selector.Sel.Name, "Register") || !strings.HasSuffix(selector.Sel.Name, "Server") {
+		return false
+	}
+	_, ok = call.Args[1].(*ast.CompositeLit)
+	return ok
+}
+
+// nodeString returns the source code of node
+func nodeString(fileSet *token.FileSet, node ast.Node) string {
+	buffer := &bytes.Buffer{}
+	if err := printer.Fprint(buffer, fileSet, node); err != nil {
+		return ""
+	}
+	return buffer.String()
+}
+
+// lineSpan expands [begin, end) to the whole lines if nothing else is on them
+func lineSpan(content []byte, begin, end int) (int, int) {
+	lineBegin := bytes.LastIndexByte(content[:begin], '\n') + 1
+	if len(bytes.TrimSpace(content[lineBegin:begin])) != 0 {
+		return begin, end
+	}
+
+	lineEnd := len(content)
+	if index := bytes.IndexByte(content[end:], '\n'); index >= 0 {
+		lineEnd = end + index + 1
+	}
+	if len(bytes.TrimSpace(content[end:lineEnd])) != 0 {
+		return begin, end
+	}
+	return lineBegin, lineEnd
+}
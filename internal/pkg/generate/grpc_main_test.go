@@ -0,0 +1,364 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+)
+
+// mainWithMarkers is main.go generated with the markers
+const mainWithMarkers = `package main
+
+import (
+	"google.golang.org/grpc"
+
+	healthCheck "example.com/project/internal/service/grpc/health_check"
+	// @HANDLER_STUB
+
+	healthCheckProto "example.com/project/api/protobuf_spec/health_check"
+	// @PROTO_STUB
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+
+	healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+	// @REGISTER_STUB
+	server.Serve(nil)
+}
+`
+
+// mainWithoutMarkers is main.go whose markers are removed by users
+const mainWithoutMarkers = `package main
+
+import (
+	"google.golang.org/grpc"
+
+	healthCheck "example.com/project/internal/service/grpc/health_check"
+	healthCheckProto "example.com/project/api/protobuf_spec/health_check"
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+	healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+
+	server.Serve(nil)
+}
+`
+
+func TestEditMain(t *testing.T) {
+	userPackage := &protoGoPackage{ImportPath: "example.com/project/api/gen/user", Name: "user"}
+	healthCheckPackage := &protoGoPackage{ImportPath: "example.com/project/api/protobuf_spec/health_check",
+		Name: "health_check"}
+	orderV1Package := &protoGoPackage{ImportPath: "example.com/project/api/gen/order/v1", Name: "v1"}
+	userV1Package := &protoGoPackage{ImportPath: "example.com/project/api/gen/user/v1", Name: "v1"}
+
+	// addService adds the imports & register of service like service add does
+	addService := func(layout directory.GRPCServiceLayout, serviceName string, goPackage *protoGoPackage) error {
+		if err := addServiceImportToMain(layout, "example.com/project", serviceName); err != nil {
+			return err
+		}
+		if err := addServiceProtoImportToMain(layout, goPackage); err != nil {
+			return err
+		}
+		return addServiceRegisterToMain(layout, serviceName, goPackage)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		edit    func(layout directory.GRPCServiceLayout) error
+		want    string
+	}{
+		{
+			name:    "add imports & register before markers",
+			content: mainWithMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				if err := addServiceImportToMain(layout, "example.com/project", "UserService"); err != nil {
+					return err
+				}
+				if err := addServiceProtoImportToMain(layout, userPackage); err != nil {
+					return err
+				}
+				return addServiceRegisterToMain(layout, "UserService", userPackage)
+			},
+			want: `package main
+
+import (
+	"google.golang.org/grpc"
+
+	healthCheck "example.com/project/internal/service/grpc/health_check"
+	userService "example.com/project/internal/service/grpc/user_service"
+	// @HANDLER_STUB
+
+	userProto "example.com/project/api/gen/user"
+	healthCheckProto "example.com/project/api/protobuf_spec/health_check"
+	// @PROTO_STUB
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+
+	healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+	userProto.RegisterUserServiceServer(server, userService.Implementation{})
+	// @REGISTER_STUB
+	server.Serve(nil)
+}
+`,
+		},
+		{
+			name:    "add imports & register without markers",
+			content: mainWithoutMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				if err := addServiceImportToMain(layout, "example.com/project", "UserService"); err != nil {
+					return err
+				}
+				if err := addServiceProtoImportToMain(layout, userPackage); err != nil {
+					return err
+				}
+				return addServiceRegisterToMain(layout, "UserService", userPackage)
+			},
+			// imports are sorted by gofmt
+			want: `package main
+
+import (
+	"google.golang.org/grpc"
+
+	userProto "example.com/project/api/gen/user"
+	healthCheckProto "example.com/project/api/protobuf_spec/health_check"
+	healthCheck "example.com/project/internal/service/grpc/health_check"
+	userService "example.com/project/internal/service/grpc/user_service"
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+	healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+	userProto.RegisterUserServiceServer(server, userService.Implementation{})
+
+	server.Serve(nil)
+}
+`,
+		},
+		{
+			name:    "add existing imports & register",
+			content: mainWithMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				if err := addServiceImportToMain(layout, "example.com/project", "HealthCheck"); err != nil {
+					return err
+				}
+				if err := addServiceProtoImportToMain(layout, healthCheckPackage); err != nil {
+					return err
+				}
+				return addServiceRegisterToMain(layout, "HealthCheck", healthCheckPackage)
+			},
+			want: mainWithMarkers,
+		},
+		{
+			name:    "add services whose go packages share the name",
+			content: mainWithMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				for _, service := range []struct {
+					name      string
+					goPackage *protoGoPackage
+				}{
+					{name: "OrderService", goPackage: orderV1Package},
+					{name: "UserService", goPackage: userV1Package},
+					{name: "UserAdminService", goPackage: userV1Package},
+				} {
+					if err := addService(layout, service.name, service.goPackage); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: `package main
+
+import (
+	"google.golang.org/grpc"
+
+	healthCheck "example.com/project/internal/service/grpc/health_check"
+	orderService "example.com/project/internal/service/grpc/order_service"
+	userAdminService "example.com/project/internal/service/grpc/user_admin_service"
+	userService "example.com/project/internal/service/grpc/user_service"
+	// @HANDLER_STUB
+
+	v1Proto "example.com/project/api/gen/order/v1"
+	userV1Proto "example.com/project/api/gen/user/v1"
+	healthCheckProto "example.com/project/api/protobuf_spec/health_check"
+	// @PROTO_STUB
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+
+	healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+	v1Proto.RegisterOrderServiceServer(server, orderService.Implementation{})
+	userV1Proto.RegisterUserServiceServer(server, userService.Implementation{})
+	userV1Proto.RegisterUserAdminServiceServer(server, userAdminService.Implementation{})
+	// @REGISTER_STUB
+	server.Serve(nil)
+}
+`,
+		},
+		{
+			name:    "remove service of go package imported with the prefixed alias",
+			content: mainWithMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				if err := addService(layout, "OrderService", orderV1Package); err != nil {
+					return err
+				}
+				if err := addService(layout, "UserService", userV1Package); err != nil {
+					return err
+				}
+				if err := removeServiceRegisterFromMain(layout, "UserService", userV1Package); err != nil {
+					return err
+				}
+				if err := removeImportFromMain(layout,
+					layout.GetServiceImportStub("example.com/project", "UserService")); err != nil {
+					return err
+				}
+				return removeImportFromMain(layout, layout.GetProtoImportStub("userV1Proto", userV1Package.ImportPath))
+			},
+			want: `package main
+
+import (
+	"google.golang.org/grpc"
+
+	healthCheck "example.com/project/internal/service/grpc/health_check"
+	orderService "example.com/project/internal/service/grpc/order_service"
+	// @HANDLER_STUB
+
+	v1Proto "example.com/project/api/gen/order/v1"
+	healthCheckProto "example.com/project/api/protobuf_spec/health_check"
+	// @PROTO_STUB
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+
+	healthCheckProto.RegisterHealthCheckServer(server, healthCheck.Implementation{})
+	v1Proto.RegisterOrderServiceServer(server, orderService.Implementation{})
+	// @REGISTER_STUB
+	server.Serve(nil)
+}
+`,
+		},
+		{
+			name:    "remove imports & register",
+			content: mainWithMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				if err := removeServiceRegisterFromMain(layout, "HealthCheck", healthCheckPackage); err != nil {
+					return err
+				}
+				if err := removeImportFromMain(layout,
+					layout.GetServiceImportStub("example.com/project", "HealthCheck")); err != nil {
+					return err
+				}
+				return removeImportFromMain(layout,
+					layout.GetProtoImportStub("healthCheckProto", healthCheckPackage.ImportPath))
+			},
+			want: `package main
+
+import (
+	"google.golang.org/grpc"
+	// @HANDLER_STUB
+	// @PROTO_STUB
+)
+
+func startGRPCServer() {
+	server := grpc.NewServer()
+
+	// @REGISTER_STUB
+	server.Serve(nil)
+}
+`,
+		},
+		{
+			name:    "remove missing imports & register",
+			content: mainWithoutMarkers,
+			edit: func(layout directory.GRPCServiceLayout) error {
+				if err := removeServiceRegisterFromMain(layout, "UserService", userPackage); err != nil {
+					return err
+				}
+				return removeImportFromMain(layout, layout.GetProtoImportStub("userProto", userPackage.ImportPath))
+			},
+			want: mainWithoutMarkers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+			if err := os.MkdirAll(filepath.Dir(layout.GetMainFilePath()), 0755); err != nil {
+				t.Fatalf("os.MkdirAll failed! error:%v", err)
+			}
+			if err := ioutil.WriteFile(layout.GetMainFilePath(), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+			}
+
+			if err := tt.edit(layout); err != nil {
+				t.Fatalf("edit main.go failed! error:%v", err)
+			}
+
+			content, err := ioutil.ReadFile(layout.GetMainFilePath())
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("main.go =\n%s\nwant\n%s", content, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddImportToFileAliasTaken(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	filePath := filepath.Join(root, "main.go")
+	if err := ioutil.WriteFile(filePath, []byte(mainWithMarkers), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+	}
+	err = addImportToFile(filePath, `healthCheckProto "example.com/project/api/gen/health_check"`, protoImportMarker)
+	if err == nil {
+		t.Errorf("addImportToFile() with the alias taken succeeded, want error")
+	}
+}
+
+func TestAddServiceRegisterToMainWithoutServer(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+	if err := os.MkdirAll(filepath.Dir(layout.GetMainFilePath()), 0755); err != nil {
+		t.Fatalf("os.MkdirAll failed! error:%v", err)
+	}
+	for _, content := range []string{
+		"package main\n\nfunc main() {}\n",
+		"package main\n\nfunc startGRPCServer() {}\n",
+	} {
+		if err := ioutil.WriteFile(layout.GetMainFilePath(), []byte(content), 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+		}
+		err := addServiceRegisterToMain(layout, "UserService", &protoGoPackage{Name: "user"})
+		if err == nil {
+			t.Errorf("addServiceRegisterToMain() of %q succeeded, want error", content)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package generate
+
+import (
+	"path/filepath"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/grpc"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc"
+	"golang.org/x/xerrors"
+)
+
+// Mock generates the mock servers of the services defined in proto which is added to project,
+// the names of services mocked are returned
+func (gen *GRPCServiceGenerator) Mock(protoPath string, layout directory.GRPCServiceLayout,
+	opts ...option.Option) ([]string, error) {
+	options := option.NewOptions(opts...)
+
+	fileDescs, err := parseProtoFile(protoPath, getProtoFileIncludePath(layout, options))
+	if err != nil {
+		return nil, xerrors.Errorf("parseProtoFile failed! error:%w", err)
+	}
+	goPackage := getProtoGoPackage(protoPath, fileDescs[0], layout, options)
+
+	serviceNames := make([]string, 0)
+	for _, serviceDesc := range fileDescs[0].GetServices() {
+		if err := generateServiceMock(goPackage, serviceDesc, layout, opts...); err != nil {
+			return serviceNames, xerrors.Errorf("generateServiceMock failed! service:%v, error:%w",
+				serviceDesc.GetName(), err)
+		}
+		serviceNames = append(serviceNames, serviceDesc.GetName())
+	}
+	return serviceNames, nil
+}
+
+// generateServiceMock generates the mock server of service into pkg/mock/<service>, mock.go & cmd/main.go are
+// always overridden while the fixtures edited by user are kept
+func generateServiceMock(goPackage *protoGoPackage, serviceDesc *desc.ServiceDescriptor,
+	layout directory.GRPCServiceLayout, opts ...option.Option) error {
+	options := option.NewOptions(opts...)
+
+	serviceName := serviceDesc.GetName()
+	if err := fsutil.CreatePaths(false,
+		layout.GetMockPath(serviceName), filepath.Dir(layout.GetMockMainFilePath(serviceName))); err != nil {
+		return xerrors.Errorf("create mock path failed! error:%w", err)
+	}
+
+	fixturePath, err := filepath.Rel(layout.GetOutputPath(), layout.GetMockFixtureFilePath(serviceName))
+	if err != nil {
+		return xerrors.Errorf("filepath.Rel failed! error:%w", err)
+	}
+
+	opts = append(opts,
+		option.WithOverride(true),
+		option.WithIgnoreExist(false),
+		option.WithServiceName(serviceName),
+		option.WithPackageName(getClientPackageName(serviceName)+"mock"),
+		option.WithImportedPackageName(goPackage.ImportPath),
+	)
+	mockInfo := grpc.NewMockInfo(opts...)
+	mockInfo.FullName = serviceDesc.GetFullyQualifiedName()
+	mockInfo.MockPackagePath = filepath.ToSlash(layout.GetMockPackagePath(options.GoModule, serviceName))
+	mockInfo.FixturePath = filepath.ToSlash(fixturePath)
+	mockInfo.RPCs, mockInfo.Imports = getClientRPCs(goPackage, serviceDesc, layout, options)
+
+	if err := mockInfo.RenderFile(layout.GetMockFilePath(serviceName)); err != nil {
+		return xerrors.Errorf("create mock file failed! error:%w", err)
+	}
+	if err := mockInfo.RenderMainFile(layout.GetMockMainFilePath(serviceName)); err != nil {
+		return xerrors.Errorf("create mock main file failed! error:%w", err)
+	}
+
+	// the example fixtures are rendered once
+	mockInfo.Opts.Override = false
+	mockInfo.Opts.IgnoreExist = true
+	if err := mockInfo.RenderFixtureFile(layout.GetMockFixtureFilePath(serviceName)); err != nil {
+		return xerrors.Errorf("create mock fixture file failed! error:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package generate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	gofraMock "github.com/DarkMetrix/gofra/pkg/grpc-utils/mock"
+)
+
+// splitGenprotoVersion is a version of google.golang.org/genproto without googleapis/rpc
+const splitGenprotoVersion = "v0.0.0-20250603155806-513f23925822"
+
+// TestMockBuild renders the mock server of a service with RPCs of every streaming mode & well-known types,
+// the mock must compile with the code generated from the proto & the example fixtures must be loaded
+func TestMockBuild(t *testing.T) {
+	root, cleanup := newBuildProject(t)
+	defer cleanup()
+
+	// the mock imports pkg/grpc-utils/mock of this repository, the genproto it requires is upgraded to one split from
+	// googleapis/rpc, otherwise the packages of googleapis/rpc imported by grpc are ambiguous
+	repoPath, err := filepath.Abs(filepath.Join("..", "..", ".."))
+	if err != nil {
+		t.Fatalf("filepath.Abs failed! error:%v", err)
+	}
+	goModPath := filepath.Join(root, "go.mod")
+	goMod, err := ioutil.ReadFile(goModPath)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+	}
+	goMod = append(goMod, fmt.Sprintf("\nrequire google.golang.org/genproto %v\n", splitGenprotoVersion)...)
+	goMod = append(goMod, fmt.Sprintf("\nreplace github.com/DarkMetrix/gofra => %v\n", repoPath)...)
+	if err := ioutil.WriteFile(goModPath, goMod, 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+	}
+
+	opts := []option.Option{option.WithOutputPath(root), option.WithGoModule(buildModule)}
+	layout := directory.NewGRPCLayout(opts...)
+	protoPath := layout.GetAPIProtobufFilePath("modes/modes.proto")
+	content := modesProto(buildModule+"/api/gen/modes", map[string]string{
+		"Unary": "Unary", "ServerStream": "ServerStream", "ClientStream": "ClientStream", "BidiStream": "BidiStream"})
+	content = strings.Replace(content, "package modes;\n", "package modes;\n"+
+		"import \"google/protobuf/empty.proto\";\nimport \"google/protobuf/timestamp.proto\";\n", 1)
+	content = strings.TrimSuffix(content, "}\n") +
+		"  rpc Ping(google.protobuf.Empty) returns (google.protobuf.Timestamp);\n}\n"
+	if err := os.MkdirAll(filepath.Dir(protoPath), 0755); err != nil {
+		t.Fatalf("os.MkdirAll failed! error:%v", err)
+	}
+	if err := ioutil.WriteFile(protoPath, []byte(content), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+	}
+
+	compiler := &pb.NativeCompiler{WorkDir: root, GoModule: buildModule}
+	if err := compiler.CompileGRPC(protoPath, getProtoFileIncludePath(layout, option.NewOptions(opts...))); err != nil {
+		t.Fatalf("CompileGRPC failed! error:%v", err)
+	}
+
+	serviceNames, err := NewGRPCServiceGenerator().Mock(protoPath, layout, opts...)
+	if err != nil {
+		t.Fatalf("Mock failed! error:%v", err)
+	}
+	if len(serviceNames) != 1 || serviceNames[0] != "Modes" {
+		t.Fatalf("Mock() = %v, want [Modes]", serviceNames)
+	}
+	if _, err := gofraMock.LoadFixtures(layout.GetMockFixtureFilePath("Modes")); err != nil {
+		t.Fatalf("LoadFixtures of the example fixtures failed! error:%v", err)
+	}
+	goBuild(t, root)
+}
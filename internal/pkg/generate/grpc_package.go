@@ -0,0 +1,151 @@
+package generate
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/grpc"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc"
+	"golang.org/x/xerrors"
+)
+
+// protoGoPackage represents the go package of the .pb.go files generated from a proto
+type protoGoPackage struct {
+	// import path, e.g.: github.com/foo/bar/api/protobuf_spec/user
+	ImportPath string
+	// package name, e.g.: user
+	Name string
+	// directory of the .pb.go files, e.g.: api/protobuf_spec/user
+	Dir string
+	// go module used to generate the .pb.go files into the directory of go_package, it's empty if the files
+	// are generated next to the proto
+	GoModule string
+}
+
+// getProtoGoPackage returns the go package of proto according to its go_package option, the .pb.go files are
+// generated into the directory of go_package if it's in go module, otherwise they're generated next to the proto
+func getProtoGoPackage(protoPath string, fileDesc *desc.FileDescriptor,
+	layout directory.GRPCServiceLayout, options *option.Options) *protoGoPackage {
+	importPath, name := parseGoPackage(fileDesc.GetFileOptions().GetGoPackage())
+
+	goPackage := &protoGoPackage{
+		ImportPath: layout.GetProtoPackagePath(options.GoModule, protoPath),
+		Name:       name,
+		Dir:        filepath.Dir(protoPath),
+	}
+
+	// buf generates code according to buf.gen.yaml, go_package is used only if it's generated by module=
+	if (options.ProtoTool != pb.ProtoToolBuf || isBufGenByModule(layout)) && options.GoModule != "" &&
+		(importPath == options.GoModule || strings.HasPrefix(importPath, options.GoModule+"/")) {
+		goPackage.ImportPath = importPath
+		goPackage.Dir = filepath.Join(options.OutputPath,
+			filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, options.GoModule), "/")))
+		goPackage.GoModule = options.GoModule
+	}
+
+	// the package name is derived from the import path like protoc-gen-go does if go_package has no name
+	if goPackage.Name == "" {
+		goPackage.Name = strings.NewReplacer("-", "_", ".", "_").Replace(path.Base(goPackage.ImportPath))
+	}
+	return goPackage
+}
+
+// isBufGenByModule checks if buf.gen.yaml generates the files by 'opt: module=' instead of next to the proto
+func isBufGenByModule(layout directory.GRPCServiceLayout) bool {
+	content, err := fsutil.ReadFile(layout.GetAPIProtobufBufGenFilePath())
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "opt: module=") {
+			return true
+		}
+	}
+	return false
+}
+
+// loadProtoGoPackage parses the proto and returns its go package
+func loadProtoGoPackage(protoPath string, layout directory.GRPCServiceLayout,
+	options *option.Options) (*protoGoPackage, error) {
+	fileDescs, err := parseProtoFile(protoPath, getProtoFileIncludePath(layout, options))
+	if err != nil {
+		return nil, xerrors.Errorf("parseProtoFile failed! error:%w", err)
+	}
+	return getProtoGoPackage(protoPath, fileDescs[0], layout, options), nil
+}
+
+// parseGoPackage parses go_package option into import path & package name,
+// e.g.: "github.com/foo/bar/user;userpb" -> github.com/foo/bar/user, userpb
+func parseGoPackage(goPackage string) (importPath, name string) {
+	if goPackage == "" {
+		return "", ""
+	}
+	if index := strings.LastIndex(goPackage, ";"); index >= 0 {
+		return goPackage[:index], goPackage[index+1:]
+	}
+	return goPackage, strings.NewReplacer("-", "_", ".", "_").Replace(path.Base(goPackage))
+}
+
+// getMessageGoType returns the qualified go type of message, messages of the service proto are in package pb,
+// the go packages of other protos are added to imports with their aliases, e.g.: emptypb.Empty
+func getMessageGoType(messageDesc *desc.MessageDescriptor, goPackage *protoGoPackage, imports map[string]string,
+	layout directory.GRPCServiceLayout, options *option.Options) string {
+	// nested messages are named after their parents, e.g.: Outer_Inner
+	name := messageDesc.GetName()
+	for parent := messageDesc.GetParent(); parent != nil; parent = parent.GetParent() {
+		parentMessage, ok := parent.(*desc.MessageDescriptor)
+		if !ok {
+			break
+		}
+		name = parentMessage.GetName() + "_" + name
+	}
+
+	fileDesc := messageDesc.GetFile()
+	var messagePackage *protoGoPackage
+	if strings.HasPrefix(fileDesc.GetName(), "google/protobuf/") {
+		// well-known types are provided by google.golang.org/protobuf
+		importPath, packageName := parseGoPackage(fileDesc.GetFileOptions().GetGoPackage())
+		messagePackage = &protoGoPackage{ImportPath: importPath, Name: packageName}
+	} else {
+		messagePackage = getProtoGoPackage(filepath.Join(layout.GetAPIProtobufBasePath(), fileDesc.GetName()),
+			fileDesc, layout, options)
+	}
+	if messagePackage.ImportPath == goPackage.ImportPath {
+		return "pb." + name
+	}
+
+	alias, ok := imports[messagePackage.ImportPath]
+	if !ok {
+		alias = messagePackage.Name
+		if !strings.HasPrefix(fileDesc.GetName(), "google/protobuf/") {
+			alias = layout.GetPackageAlias(messagePackage.Name) + "Proto"
+		}
+		for _, importedAlias := range imports {
+			if importedAlias == alias {
+				alias = fmt.Sprintf("%v%v", alias, len(imports))
+				break
+			}
+		}
+		imports[messagePackage.ImportPath] = alias
+	}
+	return alias + "." + name
+}
+
+// getGoImports returns the go packages imported by the qualified go types sorted by path
+func getGoImports(imports map[string]string) []*grpc.GoImport {
+	clientImports := make([]*grpc.GoImport, 0, len(imports))
+	for importPath, alias := range imports {
+		clientImports = append(clientImports, &grpc.GoImport{Alias: alias, Path: importPath})
+	}
+	sort.Slice(clientImports, func(i, j int) bool {
+		return clientImports[i].Path < clientImports[j].Path
+	})
+	return clientImports
+}
@@ -0,0 +1,172 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"golang.org/x/xerrors"
+)
+
+// ProtoFile represents a .proto file to be copied into api/protobuf_spec
+type ProtoFile struct {
+	// path to read the .proto file from, e.g.: protos/order/order.proto
+	SourcePath string
+	// name used by imports, e.g.: order/order.proto
+	Name string
+	// path in project, e.g.: api/protobuf_spec/order/order.proto
+	ProjectPath string
+	// true if the file is not specified but imported by the specified ones
+	Imported bool
+}
+
+// CollectProtoFiles expands the specified .proto files & directories into .proto files with their transitive imports,
+// the imports come before the files importing them.
+//
+// The name of a file in directory is the path relative to the directory, the name of a specified file is the path
+// relative to the include path containing it, or the file name which is put into api/protobuf_spec/<name>/ like before.
+func CollectProtoFiles(paths []string, protoFileIncludePath []string,
+	layout directory.GRPCServiceLayout) ([]*ProtoFile, error) {
+	collector := &protoFileCollector{
+		protoFileIncludePath: protoFileIncludePath,
+		layout:               layout,
+		collected:            make(map[string]*ProtoFile),
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, xerrors.Errorf("os.Stat failed! error:%w", err)
+		}
+
+		if !info.IsDir() {
+			rootPath, name := getProtoFileName(path, protoFileIncludePath)
+			if err := collector.collect(rootPath, name, !strings.Contains(name, "/")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		protoPaths, err := findProtoFiles(path)
+		if err != nil {
+			return nil, xerrors.Errorf("findProtoFiles failed! error:%w", err)
+		}
+		for _, protoPath := range protoPaths {
+			relativePath, err := filepath.Rel(path, protoPath)
+			if err != nil {
+				return nil, xerrors.Errorf("filepath.Rel failed! error:%w", err)
+			}
+			if err := collector.collect(path, filepath.ToSlash(relativePath), false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return collector.protoFiles, nil
+}
+
+// GetProtoProjectPath returns the path in api/protobuf_spec of a .proto file specified to CollectProtoFiles,
+// e.g.: the path of proto to remove is resolved the same way as it was added
+func GetProtoProjectPath(path string, protoFileIncludePath []string, layout directory.GRPCServiceLayout) string {
+	_, name := getProtoFileName(path, protoFileIncludePath)
+	if !strings.Contains(name, "/") {
+		return layout.GetAPIProtobufFilePath(name)
+	}
+	return filepath.Join(layout.GetAPIProtobufBasePath(), filepath.FromSlash(name))
+}
+
+// getProtoFileName returns the include path containing the .proto file and the file name relative to it,
+// or the directory & the base name of file if no include path contains it
+func getProtoFileName(path string, protoFileIncludePath []string) (rootPath, name string) {
+	for _, includePath := range protoFileIncludePath {
+		if relativePath, err := filepath.Rel(includePath, path); err == nil && !strings.HasPrefix(relativePath, "..") {
+			return includePath, filepath.ToSlash(relativePath)
+		}
+	}
+	return filepath.Dir(path), filepath.Base(path)
+}
+
+// protoFileCollector collects .proto files and their imports in dependency order
+type protoFileCollector struct {
+	protoFileIncludePath []string
+	layout               directory.GRPCServiceLayout
+
+	protoFiles []*ProtoFile
+	collected  map[string]*ProtoFile
+}
+
+// collect parses the .proto file named name in root path and collects it after its imports,
+// flatten puts the file into api/protobuf_spec/<name>/ instead of keeping the name
+func (collector *protoFileCollector) collect(rootPath, name string, flatten bool) error {
+	// protos already in api/protobuf_spec could be imported as well, e.g.: google/api/annotations.proto
+	importPaths := append([]string{rootPath}, collector.protoFileIncludePath...)
+	importPaths = append(importPaths, collector.layout.GetAPIProtobufBasePath())
+	parser := protoparse.Parser{
+		ImportPaths: importPaths,
+		Accessor:    fsutil.Open,
+	}
+	fileDescs, err := parser.ParseFiles(name)
+	if err != nil {
+		parseErr := pb.NewParseError(filepath.Join(rootPath, name), err, importPaths)
+		logCompileError(parseErr)
+		return xerrors.Errorf("Unable to parse proto file! proto file:%v, error:%w", filepath.Join(rootPath, name), parseErr)
+	}
+
+	for _, dependency := range fileDescs[0].GetDependencies() {
+		if err := collector.collectImport(dependency, importPaths); err != nil {
+			return err
+		}
+	}
+
+	projectPath := filepath.Join(collector.layout.GetAPIProtobufBasePath(), filepath.FromSlash(name))
+	if flatten {
+		projectPath = collector.layout.GetAPIProtobufFilePath(name)
+	}
+	if protoFile, ok := collector.collected[name]; ok {
+		// imported before, keep the imported path so that the import works
+		protoFile.Imported = false
+		return nil
+	}
+	collector.add(&ProtoFile{
+		SourcePath:  filepath.Join(rootPath, filepath.FromSlash(name)),
+		Name:        name,
+		ProjectPath: projectPath,
+	})
+	return nil
+}
+
+// collectImport collects the imported .proto file and its imports, well-known types are provided by protobuf
+func (collector *protoFileCollector) collectImport(fileDesc *desc.FileDescriptor, importPaths []string) error {
+	name := fileDesc.GetName()
+	if _, ok := collector.collected[name]; ok || strings.HasPrefix(name, "google/protobuf/") {
+		return nil
+	}
+
+	sourcePath := pb.FindProtoFile(name, importPaths)
+	if sourcePath == "" {
+		return xerrors.Errorf("Imported proto file not found! proto file:%v, include path:%v", name, importPaths)
+	}
+
+	for _, dependency := range fileDesc.GetDependencies() {
+		if err := collector.collectImport(dependency, importPaths); err != nil {
+			return err
+		}
+	}
+	collector.add(&ProtoFile{
+		SourcePath:  sourcePath,
+		Name:        name,
+		ProjectPath: filepath.Join(collector.layout.GetAPIProtobufBasePath(), filepath.FromSlash(name)),
+		Imported:    true,
+	})
+	return nil
+}
+
+// add appends the .proto file to the collected ones
+func (collector *protoFileCollector) add(protoFile *ProtoFile) {
+	collector.protoFiles = append(collector.protoFiles, protoFile)
+	collector.collected[protoFile.Name] = protoFile
+}
@@ -0,0 +1,66 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/pb"
+	"golang.org/x/xerrors"
+)
+
+func TestCollectProtoFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"protos/common/types.proto": "syntax = \"proto3\";\npackage common;\nmessage Empty {}\n",
+		"protos/user/user.proto": "syntax = \"proto3\";\npackage user;\nimport \"common/types.proto\";\n" +
+			"service UserService {\n  rpc Get(common.Empty) returns (common.Empty);\n}\n",
+		"broken/order.proto": "syntax = \"proto3\";\npackage order;\nmesage Order {\n}\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed! error:%v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+		}
+	}
+
+	layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+	protoFileIncludePath := []string{filepath.Join(root, "protos")}
+
+	// the import comes first
+	protoFiles, err := CollectProtoFiles([]string{filepath.Join(root, "protos/user/user.proto")},
+		protoFileIncludePath, layout)
+	if err != nil {
+		t.Fatalf("CollectProtoFiles failed! error:%v", err)
+	}
+	if len(protoFiles) != 2 || protoFiles[0].Name != "common/types.proto" || !protoFiles[0].Imported ||
+		protoFiles[1].Name != "user/user.proto" || protoFiles[1].Imported {
+		t.Fatalf("CollectProtoFiles() = %+v, want common/types.proto imported by user/user.proto", protoFiles)
+	}
+
+	// the parse error is a compile error with the source of diagnostic
+	_, err = CollectProtoFiles([]string{filepath.Join(root, "broken")}, protoFileIncludePath, layout)
+	var compileErr *pb.CompileError
+	if !xerrors.As(err, &compileErr) {
+		t.Fatalf("CollectProtoFiles() error:%v, want *pb.CompileError", err)
+	}
+	if len(compileErr.Diagnostics) != 1 {
+		t.Fatalf("CollectProtoFiles() diagnostics:%+v, want 1", compileErr.Diagnostics)
+	}
+	diagnostic := compileErr.Diagnostics[0]
+	if diagnostic.Line != 3 || diagnostic.Source != "mesage Order {" ||
+		diagnostic.SourcePath != filepath.Join(root, "broken", "order.proto") {
+		t.Errorf("CollectProtoFiles() diagnostic:%+v, want line 3 of broken/order.proto with source", diagnostic)
+	}
+}
@@ -0,0 +1,224 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"golang.org/x/xerrors"
+)
+
+// RegenerateResult represents the changes made by regenerating a proto
+type RegenerateResult struct {
+	ProtoPath string
+	// generated .pb.go files whose content changed or which are newly generated
+	ChangedPBFiles []string
+	// handler files newly generated, e.g.: RPC stubs of new RPCs
+	AddedHandlers []string
+	// lines of code newly added to main.go, e.g.: imports & service registers
+	AddedMainStubs []string
+	// error occurred while regenerating, the following protos are not regenerated
+	Err error
+}
+
+// Regenerate recompiles every proto under api/protobuf_spec and updates its services,
+// it stops at the first failure and returns the results of protos regenerated so far
+func (gen *GRPCServiceGenerator) Regenerate(
+	layout directory.GRPCServiceLayout, opts ...option.Option) ([]*RegenerateResult, error) {
+	protoPaths, err := findServiceProtoFiles(layout)
+	if err != nil {
+		return nil, xerrors.Errorf("findServiceProtoFiles failed! error:%w", err)
+	}
+
+	results := make([]*RegenerateResult, 0, len(protoPaths))
+	for _, protoPath := range protoPaths {
+		result, err := regenerateGRPCService(gen, protoPath, layout, opts...)
+		results = append(results, result)
+		if err != nil {
+			return results, xerrors.Errorf("regenerate failed! proto:%v, error:%w", protoPath, err)
+		}
+	}
+	return results, nil
+}
+
+// regenerateGRPCService updates a gRPC service and compares the generated files before & after
+func regenerateGRPCService(gen *GRPCServiceGenerator, protoPath string,
+	layout directory.GRPCServiceLayout, opts ...option.Option) (*RegenerateResult, error) {
+	result := &RegenerateResult{ProtoPath: protoPath}
+
+	// the .pb.go files are generated into the directory of go package
+	goPackage, err := loadProtoGoPackage(protoPath, layout, option.NewOptions(opts...))
+	if err != nil {
+		result.Err = err
+		return result, err
+	}
+
+	// snapshot before regenerating
+	pbHashes, err := hashGeneratedFiles(goPackage.Dir)
+	if err != nil {
+		return result, xerrors.Errorf("hashGeneratedFiles failed! error:%w", err)
+	}
+	handlers, err := findGoFiles(layout.GetGRPCServiceBasePath())
+	if err != nil {
+		return result, xerrors.Errorf("findGoFiles failed! error:%w", err)
+	}
+	mainStubs, err := getMainStubs(layout)
+	if err != nil {
+		return result, xerrors.Errorf("getMainStubs failed! error:%w", err)
+	}
+
+	// regenerate
+	if err := gen.Update(protoPath, layout, opts...); err != nil {
+		result.Err = err
+		return result, err
+	}
+
+	// compare with the snapshot
+	newPBHashes, err := hashGeneratedFiles(goPackage.Dir)
+	if err != nil {
+		return result, xerrors.Errorf("hashGeneratedFiles failed! error:%w", err)
+	}
+	for filePath, hash := range newPBHashes {
+		if pbHashes[filePath] != hash {
+			result.ChangedPBFiles = append(result.ChangedPBFiles, filePath)
+		}
+	}
+
+	newHandlers, err := findGoFiles(layout.GetGRPCServiceBasePath())
+	if err != nil {
+		return result, xerrors.Errorf("findGoFiles failed! error:%w", err)
+	}
+	for filePath := range newHandlers {
+		if !handlers[filePath] {
+			result.AddedHandlers = append(result.AddedHandlers, filePath)
+		}
+	}
+
+	newMainStubs, err := getMainStubs(layout)
+	if err != nil {
+		return result, xerrors.Errorf("getMainStubs failed! error:%w", err)
+	}
+	for stub := range newMainStubs {
+		if !mainStubs[stub] {
+			result.AddedMainStubs = append(result.AddedMainStubs, stub)
+		}
+	}
+
+	sort.Strings(result.ChangedPBFiles)
+	sort.Strings(result.AddedHandlers)
+	sort.Strings(result.AddedMainStubs)
+	return result, nil
+}
+
+// findProtoFiles returns all the .proto files under dir in sorted order
+func findProtoFiles(dir string) ([]string, error) {
+	filePaths, err := fsutil.ListFiles(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.ListFiles failed! error:%w", err)
+	}
+
+	protoPaths := make([]string, 0)
+	for _, filePath := range filePaths {
+		if filepath.Ext(filePath) == ".proto" {
+			protoPaths = append(protoPaths, filePath)
+		}
+	}
+	return protoPaths, nil
+}
+
+// findServiceProtoFiles returns the .proto files of project under api/protobuf_spec in sorted order,
+// google/api protos written by the http gateway are provided by google.golang.org/genproto and skipped
+func findServiceProtoFiles(layout directory.GRPCServiceLayout) ([]string, error) {
+	protoPaths, err := findProtoFiles(layout.GetAPIProtobufBasePath())
+	if err != nil {
+		return nil, xerrors.Errorf("findProtoFiles failed! error:%w", err)
+	}
+
+	googleAPIPath := filepath.Join(layout.GetAPIProtobufBasePath(), "google", "api") + string(filepath.Separator)
+	servicePaths := make([]string, 0, len(protoPaths))
+	for _, protoPath := range protoPaths {
+		if !strings.HasPrefix(protoPath, googleAPIPath) {
+			servicePaths = append(servicePaths, protoPath)
+		}
+	}
+	return servicePaths, nil
+}
+
+// findGoFiles returns all the .go files under dir
+func findGoFiles(dir string) (map[string]bool, error) {
+	filePaths, err := fsutil.ListFiles(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.ListFiles failed! error:%w", err)
+	}
+
+	goFilePaths := make(map[string]bool)
+	for _, filePath := range filePaths {
+		if filepath.Ext(filePath) == ".go" {
+			goFilePaths[filePath] = true
+		}
+	}
+	return goFilePaths, nil
+}
+
+// hashGeneratedFiles returns the sha256 of every .pb.go & .pb.gw.go file in dir
+func hashGeneratedFiles(dir string) (map[string][sha256.Size]byte, error) {
+	filePaths, err := fsutil.ListFiles(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.ListFiles failed! error:%w", err)
+	}
+
+	hashes := make(map[string][sha256.Size]byte)
+	for _, filePath := range filePaths {
+		if filepath.Dir(filePath) != filepath.Clean(dir) ||
+			(!strings.HasSuffix(filePath, ".pb.go") && !strings.HasSuffix(filePath, ".pb.gw.go")) {
+			continue
+		}
+		content, err := fsutil.ReadFile(filePath)
+		if err != nil {
+			return nil, xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+		}
+		hashes[filePath] = sha256.Sum256(content)
+	}
+	return hashes, nil
+}
+
+// getMainStubs returns the imports and the service registers of main.go
+func getMainStubs(layout directory.GRPCServiceLayout) (map[string]bool, error) {
+	mainFilePath := layout.GetMainFilePath()
+	mainContent, err := fsutil.ReadFile(mainFilePath)
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, mainFilePath, mainContent, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("parser.ParseFile failed! file:%v, error:%w", mainFilePath, err)
+	}
+
+	stubs := make(map[string]bool)
+	for _, importSpec := range file.Imports {
+		stub := importSpec.Path.Value
+		if importSpec.Name != nil {
+			stub = importSpec.Name.Name + " " + stub
+		}
+		stubs["import "+stub] = true
+	}
+
+	funcDecl, err := findMainServerFunc(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range funcDecl.Body.List {
+		if isServiceRegister(stmt) {
+			stubs[strings.TrimSpace(nodeString(fileSet, stmt))] = true
+		}
+	}
+	return stubs, nil
+}
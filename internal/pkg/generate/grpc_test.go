@@ -0,0 +1,195 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/directory"
+	"github.com/DarkMetrix/gofra/internal/pkg/manifest"
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates/general"
+)
+
+func TestRemoveProtoFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		protoPath    string
+		goPackageDir string
+		files        []string
+		wantRemoved  []string
+		wantKept     []string
+	}{
+		{
+			name:         "go package next to proto",
+			protoPath:    "api/protobuf_spec/user/user.proto",
+			goPackageDir: "api/protobuf_spec/user",
+			files:        []string{"api/protobuf_spec/user/user.pb.go", "api/protobuf_spec/user/user_grpc.pb.go"},
+			wantRemoved:  []string{"api/protobuf_spec/user"},
+			wantKept:     []string{"api/protobuf_spec"},
+		},
+		{
+			name:         "go package out of api/protobuf_spec",
+			protoPath:    "api/protobuf_spec/order/order.proto",
+			goPackageDir: "api/gen/order",
+			files:        []string{"api/gen/order/order.pb.go", "api/gen/order/order_grpc.pb.go"},
+			wantRemoved:  []string{"api/protobuf_spec/order", "api/gen"},
+			wantKept:     []string{"api/protobuf_spec", "api"},
+		},
+		{
+			name:         "nested go package",
+			protoPath:    "api/protobuf_spec/order/v1/order.proto",
+			goPackageDir: "api/gen/order/v1",
+			files:        []string{"api/gen/order/v1/order.pb.go"},
+			wantRemoved:  []string{"api/protobuf_spec/order", "api/gen"},
+			wantKept:     []string{"api/protobuf_spec"},
+		},
+		{
+			name:         "go package shared by other protos",
+			protoPath:    "api/protobuf_spec/order/order.proto",
+			goPackageDir: "api/gen/order",
+			files:        []string{"api/gen/order/order.pb.go", "api/gen/order/types.pb.go"},
+			wantRemoved:  []string{"api/protobuf_spec/order", "api/gen/order/order.pb.go"},
+			wantKept:     []string{"api/gen/order/types.pb.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			for _, filePath := range append([]string{tt.protoPath}, tt.files...) {
+				filePath = filepath.Join(root, filePath)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+					t.Fatalf("os.MkdirAll failed! error:%v", err)
+				}
+				if err := ioutil.WriteFile(filePath, nil, 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+				}
+			}
+
+			layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+			if err := removeProtoFiles(filepath.Join(root, tt.protoPath),
+				filepath.Join(root, tt.goPackageDir), layout); err != nil {
+				t.Fatalf("removeProtoFiles() failed! error:%v", err)
+			}
+
+			for _, path := range tt.wantRemoved {
+				if _, err := os.Stat(filepath.Join(root, path)); !os.IsNotExist(err) {
+					t.Errorf("removeProtoFiles() kept %v, want removed", path)
+				}
+			}
+			for _, path := range tt.wantKept {
+				if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+					t.Errorf("removeProtoFiles() removed %v, want kept", path)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateManifest(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	layout := directory.NewGRPCLayout(option.WithOutputPath(root))
+	userProtoPath := layout.GetAPIProtobufFilePath("user/user.proto")
+	orderProtoPath := layout.GetAPIProtobufFilePath("order/order.proto")
+	opts := []option.Option{option.WithOutputPath(root), option.WithGoModule("github.com/foo/bar"),
+		option.WithProtoTool("native"), option.WithProtoFileIncludePath([]string{"third_party"})}
+
+	if err := updateManifest(userProtoPath, []string{"UserService"}, opts...); err != nil {
+		t.Fatalf("updateManifest() failed! error:%v", err)
+	}
+	// the options of the last generation are kept
+	if err := updateManifest(orderProtoPath, []string{"OrderService"},
+		append(opts, option.WithProtoTool("buf"))...); err != nil {
+		t.Fatalf("updateManifest() failed! error:%v", err)
+	}
+	projectManifest, err := manifest.Load(root)
+	if err != nil {
+		t.Fatalf("manifest.Load failed! error:%v", err)
+	}
+	if len(projectManifest.Protos) != 2 || projectManifest.FindProto("api/protobuf_spec/user/user.proto") == nil {
+		t.Errorf("Protos = %+v, want user & order protos", projectManifest.Protos)
+	}
+	if projectManifest.GoModule != "github.com/foo/bar" || projectManifest.Project != "bar" ||
+		projectManifest.ProtoTool != "buf" || !reflect.DeepEqual(projectManifest.ProtoFileIncludePath, []string{"third_party"}) {
+		t.Errorf("manifest = %+v, want the options of the last generation", projectManifest)
+	}
+
+	// the removed proto is added back the same way
+	if err := updateManifest(userProtoPath, nil, opts...); err != nil {
+		t.Fatalf("updateManifest() failed! error:%v", err)
+	}
+	if projectManifest, err = manifest.Load(root); err != nil {
+		t.Fatalf("manifest.Load failed! error:%v", err)
+	}
+	if len(projectManifest.Protos) != 1 || projectManifest.Protos[0].Path != "api/protobuf_spec/order/order.proto" {
+		t.Errorf("Protos = %+v, want order proto only", projectManifest.Protos)
+	}
+	if err := updateManifest(userProtoPath, []string{"UserService"}, opts...); err != nil {
+		t.Fatalf("updateManifest() failed! error:%v", err)
+	}
+	if projectManifest, err = manifest.Load(root); err != nil {
+		t.Fatalf("manifest.Load failed! error:%v", err)
+	}
+	want := []*manifest.Proto{
+		{Path: "api/protobuf_spec/order/order.proto", Services: []string{"OrderService"}},
+		{Path: "api/protobuf_spec/user/user.proto", Services: []string{"UserService"}},
+	}
+	if !reflect.DeepEqual(projectManifest.Protos, want) {
+		t.Errorf("Protos = %+v, want %+v", projectManifest.Protos, want)
+	}
+}
+
+// buildModule is the go module of the projects created by newBuildProject
+const buildModule = "example.com/project"
+
+// newBuildProject creates a project with go.mod in a temporary directory to build the generated code,
+// the dependencies are resolved by goBuild like 'go mod tidy' does for the projects generated
+func newBuildProject(t *testing.T) (string, func()) {
+	if testing.Short() {
+		t.Skip("building the generated code is skipped in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skipf("go is not found in PATH! error:%v", err)
+	}
+
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	if err := general.NewGoModuleInfo(option.WithGoModule(buildModule), option.WithGoVersion("1.22")).
+		RenderFile(filepath.Join(root, "go.mod")); err != nil {
+		os.RemoveAll(root)
+		t.Fatalf("RenderFile failed! error:%v", err)
+	}
+	return root, func() { os.RemoveAll(root) }
+}
+
+// goBuild builds the go packages of the project, it's skipped if the dependencies can't be resolved,
+// e.g.: no network & they're not in the module cache
+func goBuild(t *testing.T, root string) {
+	tidy := exec.Command("go", "mod", "tidy")
+	tidy.Dir = root
+	if output, err := tidy.CombinedOutput(); err != nil {
+		t.Skipf("go mod tidy failed! output:\n%s\nerror:%v", output, err)
+	}
+
+	build := exec.Command("go", "build", "./...")
+	build.Dir = root
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed! output:\n%s\nerror:%v", output, err)
+	}
+}
@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/DarkMetrix/gofra/internal/pkg/version"
+	"golang.org/x/xerrors"
+)
+
+// FileName is the name of project manifest file in the root of project
+const FileName = ".gofra.json"
+
+// Manifest records the generator state of project, so that commands don't need to re-derive it every time
+type Manifest struct {
+	GeneratorVersion     string   `json:"generator_version"`
+	GoModule             string   `json:"go_module"`
+	GoVersion            string   `json:"go_version"`
+	Project              string   `json:"project"`
+	ProtoTool            string   `json:"proto_tool"`
+	ProtocPath           string   `json:"protoc_path"`
+	ProtoFileIncludePath []string `json:"proto_include_path"`
+	BufPath              string   `json:"buf_path,omitempty"`
+	HTTPGateway          bool     `json:"http_gateway,omitempty"`
+	Protos               []*Proto `json:"protos"`
+}
+
+// Proto represents a .proto file added to project and the services defined in it
+type Proto struct {
+	// path relative to the root of project, e.g.: api/protobuf_spec/health_check/health_check.proto
+	Path     string   `json:"path"`
+	Services []string `json:"services"`
+}
+
+// GetFilePath returns the manifest file path of project
+func GetFilePath(projectPath string) string {
+	return filepath.Join(projectPath, FileName)
+}
+
+// Exists checks if the project has a manifest
+func Exists(projectPath string) (bool, error) {
+	return fsutil.CheckPathExists(GetFilePath(projectPath))
+}
+
+// Load loads the manifest of project
+func Load(projectPath string) (*Manifest, error) {
+	data, err := fsutil.ReadFile(GetFilePath(projectPath))
+	if err != nil {
+		return nil, xerrors.Errorf("fsutil.ReadFile failed! error:%w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, xerrors.Errorf("json.Unmarshal failed! manifest:%v, error:%w", GetFilePath(projectPath), err)
+	}
+	return manifest, nil
+}
+
+// LoadOrNew loads the manifest of project, an empty manifest is returned if the project has none
+func LoadOrNew(projectPath string) (*Manifest, error) {
+	isExist, err := Exists(projectPath)
+	if err != nil {
+		return nil, xerrors.Errorf("Exists failed! error:%w", err)
+	}
+	if !isExist {
+		return &Manifest{}, nil
+	}
+	return Load(projectPath)
+}
+
+// Save writes the manifest to project with the current generator version
+func (manifest *Manifest) Save(projectPath string) error {
+	manifest.GeneratorVersion = version.Version
+	sort.Slice(manifest.Protos, func(i, j int) bool {
+		return manifest.Protos[i].Path < manifest.Protos[j].Path
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("json.MarshalIndent failed! error:%w", err)
+	}
+	if err := fsutil.WriteFile(GetFilePath(projectPath), append(data, '\n'), 0666); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// FindProto returns the proto by path relative to the root of project
+func (manifest *Manifest) FindProto(path string) *Proto {
+	for _, proto := range manifest.Protos {
+		if proto.Path == filepath.ToSlash(path) {
+			return proto
+		}
+	}
+	return nil
+}
+
+// AddProto adds the proto or updates its services if it's already added
+func (manifest *Manifest) AddProto(path string, services []string) {
+	if proto := manifest.FindProto(path); proto != nil {
+		proto.Services = services
+		return
+	}
+	manifest.Protos = append(manifest.Protos, &Proto{Path: filepath.ToSlash(path), Services: services})
+}
+
+// RemoveProto removes the proto
+func (manifest *Manifest) RemoveProto(path string) {
+	protos := make([]*Proto, 0, len(manifest.Protos))
+	for _, proto := range manifest.Protos {
+		if proto.Path != filepath.ToSlash(path) {
+			protos = append(protos, proto)
+		}
+	}
+	manifest.Protos = protos
+}
@@ -0,0 +1,141 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/version"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		noFile    bool
+		wantErr   bool
+		wantNewOK bool
+		want      *Manifest
+	}{
+		{
+			name:      "no manifest",
+			noFile:    true,
+			wantErr:   true,
+			wantNewOK: true,
+			want:      &Manifest{},
+		},
+		{
+			name:    "invalid json",
+			content: "{\"go_module\": ",
+			wantErr: true,
+		},
+		{
+			name: "manifest",
+			content: `{
+  "generator_version": "v0.0.1",
+  "go_module": "github.com/foo/bar",
+  "proto_tool": "buf",
+  "proto_include_path": ["third_party"],
+  "http_gateway": true,
+  "protos": [{"path": "api/protobuf_spec/user/user.proto", "services": ["UserService"]}]
+}`,
+			wantNewOK: true,
+			want: &Manifest{
+				GeneratorVersion:     "v0.0.1",
+				GoModule:             "github.com/foo/bar",
+				ProtoTool:            "buf",
+				ProtoFileIncludePath: []string{"third_party"},
+				HTTPGateway:          true,
+				Protos:               []*Proto{{Path: "api/protobuf_spec/user/user.proto", Services: []string{"UserService"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			if !tt.noFile {
+				if err := ioutil.WriteFile(GetFilePath(root), []byte(tt.content), 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+				}
+			}
+
+			got, err := Load(root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error:%v, want error:%v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+
+			got, err = LoadOrNew(root)
+			if (err == nil) != tt.wantNewOK {
+				t.Fatalf("LoadOrNew() error:%v, want ok:%v", err, tt.wantNewOK)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadOrNew() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddRemoveProto(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	manifest := &Manifest{GoModule: "github.com/foo/bar"}
+	manifest.AddProto("api/protobuf_spec/user/user.proto", []string{"UserService"})
+	manifest.AddProto("api/protobuf_spec/order/order.proto", []string{"OrderService"})
+	// adding again updates the services
+	manifest.AddProto("api/protobuf_spec/user/user.proto", []string{"UserService", "AdminService"})
+
+	if err := manifest.Save(root); err != nil {
+		t.Fatalf("Save() failed! error:%v", err)
+	}
+	loaded, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load() failed! error:%v", err)
+	}
+
+	// protos are saved in path order with the generator version
+	want := []*Proto{
+		{Path: "api/protobuf_spec/order/order.proto", Services: []string{"OrderService"}},
+		{Path: "api/protobuf_spec/user/user.proto", Services: []string{"UserService", "AdminService"}},
+	}
+	if !reflect.DeepEqual(loaded.Protos, want) {
+		t.Errorf("Protos = %+v, want %+v", loaded.Protos, want)
+	}
+	if loaded.GeneratorVersion != version.Version {
+		t.Errorf("GeneratorVersion = %v, want %v", loaded.GeneratorVersion, version.Version)
+	}
+	if proto := loaded.FindProto("api/protobuf_spec/user/user.proto"); proto == nil || proto != loaded.Protos[1] {
+		t.Errorf("FindProto() = %+v, want %+v", proto, loaded.Protos[1])
+	}
+
+	// removing a proto not added changes nothing, the removed one is added back the same way
+	loaded.RemoveProto("api/protobuf_spec/pay/pay.proto")
+	loaded.RemoveProto("api/protobuf_spec/user/user.proto")
+	if loaded.FindProto("api/protobuf_spec/user/user.proto") != nil || len(loaded.Protos) != 1 {
+		t.Fatalf("RemoveProto() left protos:%+v", loaded.Protos)
+	}
+	loaded.AddProto("api/protobuf_spec/user/user.proto", []string{"UserService", "AdminService"})
+	if err := loaded.Save(root); err != nil {
+		t.Fatalf("Save() failed! error:%v", err)
+	}
+	reloaded, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load() failed! error:%v", err)
+	}
+	if !reflect.DeepEqual(reloaded.Protos, want) {
+		t.Errorf("Protos = %+v, want %+v", reloaded.Protos, want)
+	}
+}
@@ -9,16 +9,25 @@ type Options struct {
 	Author  string
 
 	// override control
-	Override    bool
-	IgnoreExist bool
+	Override     bool
+	IgnoreExist  bool
+	KeepHandlers bool
+	FixHandlers  bool
 
 	// go information
 	GoModule  string
 	GoVersion string
 
 	// protobuf information
+	ProtoTool            string
 	ProtocPath           string
 	ProtoFileIncludePath []string
+	BufPath              string
+	BreakingCheck        bool
+	HTTPGateway          bool
+
+	// document information
+	DocFormats []string
 
 	// service information
 	Addr                string
@@ -27,6 +36,8 @@ type Options struct {
 	RPCName             string
 	RequestName         string
 	ResponseName        string
+	ClientStreaming     bool
+	ServerStreaming     bool
 	ImportedPackageName string
 	ServiceImplName     string
 
@@ -88,6 +99,20 @@ func WithIgnoreExist(ignoreExist bool) Option {
 	}
 }
 
+// WithKeepHandlers set the keep handlers flag
+func WithKeepHandlers(keepHandlers bool) Option {
+	return func(options *Options) {
+		options.KeepHandlers = keepHandlers
+	}
+}
+
+// WithFixHandlers set the fix handlers flag
+func WithFixHandlers(fixHandlers bool) Option {
+	return func(options *Options) {
+		options.FixHandlers = fixHandlers
+	}
+}
+
 // WithGoVersion set the go version
 func WithGoVersion(version string) Option {
 	return func(options *Options) {
@@ -95,6 +120,13 @@ func WithGoVersion(version string) Option {
 	}
 }
 
+// WithProtoTool set the tool used to compile .proto files, e.g.: protoc, native
+func WithProtoTool(protoTool string) Option {
+	return func(options *Options) {
+		options.ProtoTool = protoTool
+	}
+}
+
 // WithProtocPath set the protoc command path
 func WithProtocPath(path string) Option {
 	return func(options *Options) {
@@ -102,6 +134,34 @@ func WithProtocPath(path string) Option {
 	}
 }
 
+// WithBufPath set the buf command path
+func WithBufPath(path string) Option {
+	return func(options *Options) {
+		options.BufPath = path
+	}
+}
+
+// WithBreakingCheck set the breaking check flag
+func WithBreakingCheck(breakingCheck bool) Option {
+	return func(options *Options) {
+		options.BreakingCheck = breakingCheck
+	}
+}
+
+// WithHTTPGateway set the http gateway flag
+func WithHTTPGateway(httpGateway bool) Option {
+	return func(options *Options) {
+		options.HTTPGateway = httpGateway
+	}
+}
+
+// WithDocFormats set the formats of API documents, e.g.: markdown, html, openapi
+func WithDocFormats(formats []string) Option {
+	return func(options *Options) {
+		options.DocFormats = formats
+	}
+}
+
 // WithProtoFileIncludePath set the protoc command include .proto file paths
 func WithProtoFileIncludePath(paths []string) Option {
 	return func(options *Options) {
@@ -158,6 +218,20 @@ func WithResponseName(name string) Option {
 	}
 }
 
+// WithClientStreaming set the client streaming flag
+func WithClientStreaming(streaming bool) Option {
+	return func(options *Options) {
+		options.ClientStreaming = streaming
+	}
+}
+
+// WithServerStreaming set the server streaming flag
+func WithServerStreaming(streaming bool) Option {
+	return func(options *Options) {
+		options.ServerStreaming = streaming
+	}
+}
+
 // WithImportedPackageName set the imported package name
 func WithImportedPackageName(name string) Option {
 	return func(options *Options) {
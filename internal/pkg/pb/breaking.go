@@ -0,0 +1,158 @@
+package pb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+)
+
+// BreakingChange represents a change of proto which breaks the wire compatibility with the old revision
+type BreakingChange struct {
+	// fully qualified name of the changed element, e.g.: user.AddUserRequest.name
+	Element string
+	Message string
+}
+
+// String returns the breaking change in one line
+func (change *BreakingChange) String() string {
+	return fmt.Sprintf("%v: %v", change.Element, change.Message)
+}
+
+// CheckBreakingChanges compares the old & new revision of proto file and returns the wire breaking changes:
+// removed messages & fields & services & RPCs, changed field numbers & types, changed RPC types & streaming modes
+func CheckBreakingChanges(oldFile, newFile *desc.FileDescriptor) []*BreakingChange {
+	changes := make([]*BreakingChange, 0)
+
+	for _, oldMessage := range oldFile.GetMessageTypes() {
+		changes = append(changes, checkMessage(oldMessage, newFile.FindMessage(oldMessage.GetFullyQualifiedName()))...)
+	}
+
+	for _, oldService := range oldFile.GetServices() {
+		newService := newFile.FindService(oldService.GetFullyQualifiedName())
+		if newService == nil {
+			changes = append(changes, newBreakingChange(oldService, "service removed"))
+			continue
+		}
+
+		for _, oldMethod := range oldService.GetMethods() {
+			newMethod := newService.FindMethodByName(oldMethod.GetName())
+			if newMethod == nil {
+				changes = append(changes, newBreakingChange(oldMethod, "RPC removed"))
+				continue
+			}
+			changes = append(changes, checkMethod(oldMethod, newMethod)...)
+		}
+	}
+	return changes
+}
+
+// checkMessage compares the old & new revision of message including the nested messages
+func checkMessage(oldMessage, newMessage *desc.MessageDescriptor) []*BreakingChange {
+	if newMessage == nil {
+		return []*BreakingChange{newBreakingChange(oldMessage, "message removed")}
+	}
+
+	changes := make([]*BreakingChange, 0)
+	for _, oldField := range oldMessage.GetFields() {
+		newField := newMessage.FindFieldByNumber(oldField.GetNumber())
+		switch {
+		case newField != nil:
+			if oldType, newType := GetFieldType(oldField), GetFieldType(newField); oldType != newType {
+				changes = append(changes, newBreakingChange(oldField,
+					fmt.Sprintf("type of field %v changed from %v to %v", oldField.GetNumber(), oldType, newType)))
+			}
+		case newMessage.FindFieldByName(oldField.GetName()) != nil:
+			changes = append(changes, newBreakingChange(oldField, fmt.Sprintf("field number changed from %v to %v",
+				oldField.GetNumber(), newMessage.FindFieldByName(oldField.GetName()).GetNumber())))
+		case !isReservedNumber(newMessage, oldField.GetNumber()):
+			changes = append(changes, newBreakingChange(oldField,
+				fmt.Sprintf("field %v removed without being reserved", oldField.GetNumber())))
+		}
+	}
+
+	for _, oldNested := range oldMessage.GetNestedMessageTypes() {
+		if oldNested.IsMapEntry() {
+			continue
+		}
+		var newNested *desc.MessageDescriptor
+		for _, nested := range newMessage.GetNestedMessageTypes() {
+			if nested.GetName() == oldNested.GetName() {
+				newNested = nested
+			}
+		}
+		changes = append(changes, checkMessage(oldNested, newNested)...)
+	}
+	return changes
+}
+
+// checkMethod compares the old & new revision of RPC
+func checkMethod(oldMethod, newMethod *desc.MethodDescriptor) []*BreakingChange {
+	changes := make([]*BreakingChange, 0)
+
+	if oldType, newType := oldMethod.GetInputType().GetFullyQualifiedName(),
+		newMethod.GetInputType().GetFullyQualifiedName(); oldType != newType {
+		changes = append(changes, newBreakingChange(oldMethod,
+			fmt.Sprintf("request type changed from %v to %v", oldType, newType)))
+	}
+	if oldType, newType := oldMethod.GetOutputType().GetFullyQualifiedName(),
+		newMethod.GetOutputType().GetFullyQualifiedName(); oldType != newType {
+		changes = append(changes, newBreakingChange(oldMethod,
+			fmt.Sprintf("response type changed from %v to %v", oldType, newType)))
+	}
+	if oldMode, newMode := GetStreamingMode(oldMethod), GetStreamingMode(newMethod); oldMode != newMode {
+		changes = append(changes, newBreakingChange(oldMethod,
+			fmt.Sprintf("streaming mode changed from %v to %v", oldMode, newMode)))
+	}
+	return changes
+}
+
+// GetFieldType returns the wire related type of field, e.g.: repeated string, map<string, int32>, user.User
+func GetFieldType(field *desc.FieldDescriptor) string {
+	if field.IsMap() {
+		return fmt.Sprintf("map<%v, %v>", GetFieldType(field.GetMapKeyType()), GetFieldType(field.GetMapValueType()))
+	}
+
+	fieldType := strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+	switch {
+	case field.GetMessageType() != nil:
+		fieldType = field.GetMessageType().GetFullyQualifiedName()
+	case field.GetEnumType() != nil:
+		fieldType = field.GetEnumType().GetFullyQualifiedName()
+	}
+
+	if field.IsRepeated() {
+		return "repeated " + fieldType
+	}
+	return fieldType
+}
+
+// GetStreamingMode returns the streaming mode of RPC, e.g.: unary, server streaming
+func GetStreamingMode(method *desc.MethodDescriptor) string {
+	switch {
+	case method.IsClientStreaming() && method.IsServerStreaming():
+		return "bidirectional streaming"
+	case method.IsClientStreaming():
+		return "client streaming"
+	case method.IsServerStreaming():
+		return "server streaming"
+	default:
+		return "unary"
+	}
+}
+
+// isReservedNumber checks if the field number is reserved by message
+func isReservedNumber(message *desc.MessageDescriptor, number int32) bool {
+	for _, reservedRange := range message.AsDescriptorProto().GetReservedRange() {
+		// the end of reserved range is exclusive
+		if number >= reservedRange.GetStart() && number < reservedRange.GetEnd() {
+			return true
+		}
+	}
+	return false
+}
+
+// newBreakingChange returns a new BreakingChange pointer of descriptor
+func newBreakingChange(descriptor desc.Descriptor, message string) *BreakingChange {
+	return &BreakingChange{Element: descriptor.GetFullyQualifiedName(), Message: message}
+}
@@ -0,0 +1,201 @@
+package pb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+)
+
+// userProto is the old revision of proto compared with the new ones
+const userProto = `syntax = "proto3";
+package user;
+message User {
+  string id = 1;
+  string name = 2;
+  repeated string tags = 3;
+  map<string, int32> scores = 4;
+  message Address { string city = 1; }
+  Address address = 5;
+}
+message GetRequest { string id = 1; }
+service UserService {
+  rpc Get(GetRequest) returns (User);
+  rpc Watch(GetRequest) returns (stream User);
+}
+`
+
+func TestCheckBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		newFile string
+		want    []string
+	}{
+		{
+			name:    "no change",
+			newFile: userProto,
+			want:    []string{},
+		},
+		{
+			name: "compatible additions",
+			newFile: `syntax = "proto3";
+package user;
+message User {
+  string id = 1;
+  string name = 2;
+  repeated string tags = 3;
+  map<string, int32> scores = 4;
+  message Address { string city = 1; string street = 2; }
+  Address address = 5;
+  int64 age = 6;
+}
+message GetRequest { string id = 1; }
+message ListRequest {}
+service UserService {
+  rpc Get(GetRequest) returns (User);
+  rpc Watch(GetRequest) returns (stream User);
+  rpc List(ListRequest) returns (stream User);
+}
+`,
+			want: []string{},
+		},
+		{
+			name: "field removed, reserved & renumbered",
+			newFile: `syntax = "proto3";
+package user;
+message User {
+  reserved 3;
+  string id = 1;
+  string name = 12;
+  message Address { string city = 1; }
+  Address address = 5;
+}
+message GetRequest { string id = 1; }
+service UserService {
+  rpc Get(GetRequest) returns (User);
+  rpc Watch(GetRequest) returns (stream User);
+}
+`,
+			want: []string{
+				"user.User.name: field number changed from 2 to 12",
+				"user.User.scores: field 4 removed without being reserved",
+			},
+		},
+		{
+			name: "field types changed",
+			newFile: `syntax = "proto3";
+package user;
+message User {
+  string id = 1;
+  bytes name = 2;
+  string tags = 3;
+  map<string, int64> scores = 4;
+  message Address { int32 city = 1; }
+  Address address = 5;
+}
+message GetRequest { string id = 1; }
+service UserService {
+  rpc Get(GetRequest) returns (User);
+  rpc Watch(GetRequest) returns (stream User);
+}
+`,
+			want: []string{
+				"user.User.name: type of field 2 changed from string to bytes",
+				"user.User.tags: type of field 3 changed from repeated string to string",
+				"user.User.scores: type of field 4 changed from map<string, int32> to map<string, int64>",
+				"user.User.Address.city: type of field 1 changed from string to int32",
+			},
+		},
+		{
+			name: "messages & RPCs removed or changed",
+			newFile: `syntax = "proto3";
+package user;
+message User {
+  string id = 1;
+  string name = 2;
+  repeated string tags = 3;
+  map<string, int32> scores = 4;
+  reserved 5;
+}
+message GetRequest { string id = 1; }
+service UserService {
+  rpc Watch(stream GetRequest) returns (User);
+}
+`,
+			want: []string{
+				"user.User.Address: message removed",
+				"user.UserService.Get: RPC removed",
+				"user.UserService.Watch: streaming mode changed from server streaming to client streaming",
+			},
+		},
+		{
+			name: "service removed",
+			newFile: `syntax = "proto3";
+package user;
+message User {
+  string id = 1;
+  string name = 2;
+  repeated string tags = 3;
+  map<string, int32> scores = 4;
+  message Address { string city = 1; }
+  Address address = 5;
+}
+message GetRequest { string id = 1; }
+`,
+			want: []string{"user.UserService: service removed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := CheckBreakingChanges(parseProto(t, userProto), parseProto(t, tt.newFile))
+
+			got := make([]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckBreakingChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStreamingMode(t *testing.T) {
+	tests := []struct {
+		rpc  string
+		want string
+	}{
+		{rpc: "rpc Call(Request) returns (Response);", want: "unary"},
+		{rpc: "rpc Call(Request) returns (stream Response);", want: "server streaming"},
+		{rpc: "rpc Call(stream Request) returns (Response);", want: "client streaming"},
+		{rpc: "rpc Call(stream Request) returns (stream Response);", want: "bidirectional streaming"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			file := parseProto(t, `syntax = "proto3";
+package test;
+message Request {}
+message Response {}
+service Test { `+tt.rpc+` }
+`)
+			if got := GetStreamingMode(file.GetServices()[0].GetMethods()[0]); got != tt.want {
+				t.Errorf("GetStreamingMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// parseProto parses the content of proto file
+func parseProto(t *testing.T, content string) *desc.FileDescriptor {
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{"test.proto": content}),
+	}
+	fileDescs, err := parser.ParseFiles("test.proto")
+	if err != nil {
+		t.Fatalf("protoparse.Parser.ParseFiles failed! error:%v", err)
+	}
+	return fileDescs[0]
+}
@@ -0,0 +1,72 @@
+package pb
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"golang.org/x/xerrors"
+)
+
+// BufCompiler compiles protobuf file by executing buf in the buf module,
+// the code generation is configured by buf.gen.yaml and the lint & breaking rules by buf.yaml
+type BufCompiler struct {
+	BufPath    string
+	ModulePath string
+
+	// descriptor set built after every compilation, it's committed to repo as the baseline of breaking check
+	DescriptorSetPath string
+	BreakingCheck     bool
+}
+
+// CompileGRPC compiles protobuf file to gRPC service protobuf definition, include paths are not used
+// since the dependencies are declared in buf.yaml
+func (compiler *BufCompiler) CompileGRPC(protoFilePath string, protoFileIncludePath []string) error {
+	protoFileName, ok := getRelativePath(compiler.ModulePath, protoFilePath)
+	if !ok {
+		return xerrors.Errorf("Proto file is not in buf module! proto file:%v, buf module:%v",
+			protoFilePath, compiler.ModulePath)
+	}
+	descriptorSetPath := rebasePath(compiler.ModulePath, compiler.DescriptorSetPath)
+
+	// check breaking changes against the descriptor set built last time
+	if compiler.BreakingCheck {
+		isExist, err := fsutil.CheckPathExists(compiler.DescriptorSetPath)
+		if err != nil {
+			return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+		}
+		if isExist {
+			if err := compiler.run("breaking", "--against", descriptorSetPath); err != nil {
+				return xerrors.Errorf("Breaking changes found! error:%w", err)
+			}
+		}
+	}
+
+	// generate code and build the descriptor set as the baseline of next breaking check
+	if err := compiler.run("generate", "--template", "buf.gen.yaml", "--path", protoFileName); err != nil {
+		return err
+	}
+	if err := compiler.run("build", "-o", descriptorSetPath); err != nil {
+		return err
+	}
+	return nil
+}
+
+// run executes buf in buf module
+func (compiler *BufCompiler) run(args ...string) error {
+	output := &bytes.Buffer{}
+	shellCmd := exec.Command(compiler.BufPath, args...)
+	shellCmd.Dir = compiler.ModulePath
+	shellCmd.Stdout = output
+	shellCmd.Stderr = output
+	if err := shellCmd.Run(); err != nil {
+		return &CompileError{
+			Command:     strings.Join(append([]string{compiler.BufPath}, args...), " "),
+			Output:      output.String(),
+			Diagnostics: parseDiagnostics(output.String(), compiler.ModulePath, nil),
+			Err:         err,
+		}
+	}
+	return nil
+}
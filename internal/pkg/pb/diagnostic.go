@@ -0,0 +1,178 @@
+package pb
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"golang.org/x/xerrors"
+)
+
+// diagnosticPattern matches protoc's & buf's diagnostic, e.g.: api/protobuf_spec/user/user.proto:23:1: Expected "}".
+var diagnosticPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): ?(.*)$`)
+
+// Diagnostic represents an error or warning reported by protoc, File & Line & Column are empty if it has no position
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+
+	// the source line of Line and where the proto file is, they're empty if the proto file can't be found
+	Source     string
+	SourcePath string
+}
+
+// String returns the diagnostic in protoc's format
+func (diagnostic *Diagnostic) String() string {
+	if diagnostic.Line == 0 {
+		return diagnostic.Message
+	}
+	return fmt.Sprintf("%v:%v:%v: %v", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+}
+
+// Render returns the diagnostic followed by the source line and a caret pointing to the column, e.g.:
+//
+//	api/protobuf_spec/user/user.proto:23:1: Expected top-level statement (e.g. "message").
+//	   23 | mesage User {
+//	      | ^
+func (diagnostic *Diagnostic) Render() string {
+	if diagnostic.Source == "" {
+		return diagnostic.String()
+	}
+
+	lineNumber := strconv.Itoa(diagnostic.Line)
+	padding := strings.Repeat(" ", len(lineNumber))
+
+	// keep tabs so that the caret is aligned with the source
+	caret := make([]rune, 0, diagnostic.Column)
+	for index, char := range []rune(diagnostic.Source) {
+		if index >= diagnostic.Column-1 {
+			break
+		}
+		if char == '\t' {
+			caret = append(caret, '\t')
+		} else {
+			caret = append(caret, ' ')
+		}
+	}
+	caret = append(caret, '^')
+
+	return fmt.Sprintf("%v\n  %v | %v\n  %v | %v",
+		diagnostic.String(), lineNumber, diagnostic.Source, padding, string(caret))
+}
+
+// CompileError represents the failure of compiling proto file, diagnostics are parsed from the output of protoc
+type CompileError struct {
+	Command     string
+	Output      string
+	Diagnostics []*Diagnostic
+	Err         error
+}
+
+// Error returns the command & error with every diagnostic in a line
+func (compileErr *CompileError) Error() string {
+	lines := []string{fmt.Sprintf("%v failed! error:%v", compileErr.Command, compileErr.Err)}
+	for _, diagnostic := range compileErr.Diagnostics {
+		lines = append(lines, diagnostic.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the underlying error
+func (compileErr *CompileError) Unwrap() error {
+	return compileErr.Err
+}
+
+// Render returns all the diagnostics with source context
+func (compileErr *CompileError) Render() string {
+	lines := make([]string, 0, len(compileErr.Diagnostics))
+	for _, diagnostic := range compileErr.Diagnostics {
+		lines = append(lines, diagnostic.Render())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseDiagnostics parses the output of protoc run in work dir into diagnostics, proto files are searched in include
+// paths and work dir to get the source
+func parseDiagnostics(output, workDir string, protoFileIncludePath []string) []*Diagnostic {
+	diagnostics := make([]*Diagnostic, 0)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		diagnostic := &Diagnostic{Message: line}
+		if matches := diagnosticPattern.FindStringSubmatch(line); matches != nil {
+			diagnostic.File = matches[1]
+			diagnostic.Line, _ = strconv.Atoi(matches[2])
+			diagnostic.Column, _ = strconv.Atoi(matches[3])
+			diagnostic.Message = matches[4]
+			loadDiagnosticSource(diagnostic, workDir, protoFileIncludePath)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// NewParseError returns the compile error of parsing .proto file by protoparse, the diagnostic has the source context
+func NewParseError(protoFilePath string, err error, protoFileIncludePath []string) *CompileError {
+	return &CompileError{
+		Command:     fmt.Sprintf("parse %v", protoFilePath),
+		Output:      err.Error(),
+		Diagnostics: []*Diagnostic{newParseDiagnostic(err, protoFileIncludePath)},
+		Err:         err,
+	}
+}
+
+// newParseDiagnostic converts the error of protoparse into diagnostic
+func newParseDiagnostic(err error, protoFileIncludePath []string) *Diagnostic {
+	var posErr protoparse.ErrorWithPos
+	if !xerrors.As(err, &posErr) {
+		return &Diagnostic{Message: err.Error()}
+	}
+
+	pos := posErr.GetPosition()
+	diagnostic := &Diagnostic{
+		File:    pos.Filename,
+		Line:    pos.Line,
+		Column:  pos.Col,
+		Message: posErr.Unwrap().Error(),
+	}
+	loadDiagnosticSource(diagnostic, "", protoFileIncludePath)
+	return diagnostic
+}
+
+// loadDiagnosticSource finds the proto file of diagnostic in include paths and loads the source line, the file is
+// relative to work dir if it's not found in include paths, e.g.: protoc reports the file passed to it that way
+func loadDiagnosticSource(diagnostic *Diagnostic, workDir string, protoFileIncludePath []string) {
+	candidates := make([]string, 0, len(protoFileIncludePath)+1)
+	for _, includePath := range protoFileIncludePath {
+		candidates = append(candidates, filepath.Join(includePath, diagnostic.File))
+	}
+	if filepath.IsAbs(diagnostic.File) {
+		candidates = append(candidates, diagnostic.File)
+	} else {
+		candidates = append(candidates, filepath.Join(workDir, diagnostic.File))
+	}
+
+	for _, candidate := range candidates {
+		content, err := fsutil.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+
+		lines := strings.Split(string(content), "\n")
+		if diagnostic.Line < 1 || diagnostic.Line > len(lines) {
+			return
+		}
+		diagnostic.SourcePath = candidate
+		diagnostic.Source = strings.TrimRight(lines[diagnostic.Line-1], "\r")
+		return
+	}
+}
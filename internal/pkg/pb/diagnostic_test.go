@@ -0,0 +1,133 @@
+package pb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// root is the work dir of protoc & buf, it is not the cwd so files are found only by joining it
+	includePath := filepath.Join(root, "third_party")
+	files := map[string]string{
+		filepath.Join(root, "user", "user.proto"):            "syntax = \"proto3\";\n\nmesage User {\n}\n",
+		filepath.Join(root, "crlf.proto"):                    "syntax = \"proto3\";\r\nmesage User {\r\n}\r\n",
+		filepath.Join(includePath, "common", "common.proto"): "syntax = \"proto3\";\nmessage Common {\n\tstrin name = 1;\n}\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed! error:%v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   []*Diagnostic
+	}{
+		{
+			name:   "protoc",
+			output: "user/user.proto:3:1: Expected top-level statement (e.g. \"message\").\n",
+			want: []*Diagnostic{{File: "user/user.proto", Line: 3, Column: 1,
+				Message: "Expected top-level statement (e.g. \"message\").", Source: "mesage User {",
+				SourcePath: filepath.Join(root, "user", "user.proto")}},
+		},
+		{
+			name:   "buf",
+			output: "user/user.proto:3:1:syntax error: unexpected identifier\n",
+			want: []*Diagnostic{{File: "user/user.proto", Line: 3, Column: 1,
+				Message: "syntax error: unexpected identifier", Source: "mesage User {",
+				SourcePath: filepath.Join(root, "user", "user.proto")}},
+		},
+		{
+			name:   "include path",
+			output: "common/common.proto:3:2: \"strin\" is not defined.\n",
+			want: []*Diagnostic{{File: "common/common.proto", Line: 3, Column: 2,
+				Message: "\"strin\" is not defined.", Source: "\tstrin name = 1;",
+				SourcePath: filepath.Join(includePath, "common", "common.proto")}},
+		},
+		{
+			name:   "non-matching lines",
+			output: "--go_out: protoc-gen-go: Plugin failed with status code 1.\n\nuser.proto: File not found.\n",
+			want: []*Diagnostic{{Message: "--go_out: protoc-gen-go: Plugin failed with status code 1."},
+				{Message: "user.proto: File not found."}},
+		},
+		{
+			name:   "crlf",
+			output: "crlf.proto:2:1: Expected top-level statement (e.g. \"message\").\r\n",
+			want: []*Diagnostic{{File: "crlf.proto", Line: 2, Column: 1,
+				Message: "Expected top-level statement (e.g. \"message\").", Source: "mesage User {",
+				SourcePath: filepath.Join(root, "crlf.proto")}},
+		},
+		{
+			name:   "out-of-range line",
+			output: "user/user.proto:30:1: Reached end of input in message definition (missing '}').\n",
+			want: []*Diagnostic{{File: "user/user.proto", Line: 30, Column: 1,
+				Message: "Reached end of input in message definition (missing '}')."}},
+		},
+		{
+			name:   "file not found",
+			output: "pay/pay.proto:1:1: Expected \"syntax\".\n",
+			want:   []*Diagnostic{{File: "pay/pay.proto", Line: 1, Column: 1, Message: "Expected \"syntax\"."}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDiagnostics(tt.output, root, []string{includePath})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDiagnostics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticRender(t *testing.T) {
+	tests := []struct {
+		name       string
+		diagnostic *Diagnostic
+		want       string
+	}{
+		{
+			name:       "no position",
+			diagnostic: &Diagnostic{Message: "user.proto: File not found."},
+			want:       "user.proto: File not found.",
+		},
+		{
+			name:       "no source",
+			diagnostic: &Diagnostic{File: "user.proto", Line: 3, Column: 1, Message: "Expected \"}\"."},
+			want:       "user.proto:3:1: Expected \"}\".",
+		},
+		{
+			name: "source",
+			diagnostic: &Diagnostic{File: "user.proto", Line: 23, Column: 8, Message: "Expected \"{\".",
+				Source: "mesage User {"},
+			want: "user.proto:23:8: Expected \"{\".\n  23 | mesage User {\n     |        ^",
+		},
+		{
+			name: "tabs",
+			diagnostic: &Diagnostic{File: "user.proto", Line: 3, Column: 3, Message: "\"strin\" is not defined.",
+				Source: "\t\tstrin name = 1;"},
+			want: "user.proto:3:3: \"strin\" is not defined.\n  3 | \t\tstrin name = 1;\n    | \t\t^",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diagnostic.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package pb
+
+import (
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// httpRuleFieldNumber is the field number of google.api.http extension of google.protobuf.MethodOptions
+const httpRuleFieldNumber = 72295728
+
+// HasHTTPRules checks if any RPC of service has google.api.http annotation
+func HasHTTPRules(service *desc.ServiceDescriptor) bool {
+	for _, method := range service.GetMethods() {
+		if HasHTTPRule(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasHTTPRule checks if RPC has google.api.http annotation, the extension is either known by the parser
+// or kept as unknown field if google/api/annotations.proto is not linked in
+func HasHTTPRule(method *desc.MethodDescriptor) bool {
+	options := method.GetMethodOptions()
+	if options == nil {
+		return false
+	}
+
+	found := false
+	options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		found = field.Number() == httpRuleFieldNumber
+		return !found
+	})
+	if found {
+		return true
+	}
+
+	unknown := options.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		number, _, length := protowire.ConsumeField(unknown)
+		if length < 0 {
+			return false
+		}
+		if number == httpRuleFieldNumber {
+			return true
+		}
+		unknown = unknown[length:]
+	}
+	return false
+}
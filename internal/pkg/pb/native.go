@@ -0,0 +1,177 @@
+package pb
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// plugins executed by native compiler to generate http gateway
+const (
+	gatewayPluginName = "protoc-gen-grpc-gateway"
+	openAPIPluginName = "protoc-gen-openapiv2"
+)
+
+// nativeParameter is the same as protoc's '--go_opt=paths=source_relative' & '--go-grpc_opt=paths=source_relative'
+const nativeParameter = "paths=source_relative"
+
+// NativeCompiler compiles protobuf file in process, it parses .proto file by protoparse and
+// runs protoc-gen-go & protoc-gen-go-grpc as libraries, so protoc and plugins are not needed unless HTTPGateway is set
+type NativeCompiler struct {
+	// the generated files are written into the directory of go_package relative to work dir if go module is not empty
+	WorkDir  string
+	GoModule string
+	// protoc-gen-grpc-gateway & protoc-gen-openapiv2 are not built in, they're executed like protoc does
+	HTTPGateway bool
+}
+
+// CompileGRPC compiles protobuf file to gRPC service protobuf definition
+func (compiler *NativeCompiler) CompileGRPC(protoFilePath string, protoFileIncludePath []string) error {
+	// parse .proto file and its imports
+	parser := protoparse.Parser{
+		ImportPaths:           protoFileIncludePath,
+		Accessor:              fsutil.Open,
+		IncludeSourceCodeInfo: true,
+	}
+	fileDescs, err := parser.ParseFiles(GetProtoFileName(protoFilePath, protoFileIncludePath))
+	if err != nil {
+		return NewParseError(protoFilePath, err, protoFileIncludePath)
+	}
+
+	// build the same request as protoc sends to plugins
+	parameter, outputPath := nativeParameter, GetProtoRootPath(protoFilePath, protoFileIncludePath)
+	if compiler.GoModule != "" {
+		parameter, outputPath = "module="+compiler.GoModule, compiler.WorkDir
+	}
+	request := &pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String(parameter),
+	}
+	visited := make(map[string]bool)
+	for _, fileDesc := range fileDescs {
+		request.FileToGenerate = append(request.FileToGenerate, fileDesc.GetName())
+		request.ProtoFile = appendFileDescriptorProtos(request.ProtoFile, fileDesc, visited)
+	}
+
+	// run protoc-gen-go & protoc-gen-go-grpc, the generated files are written next to the proto file by default
+	for _, generate := range []func(*protogen.Plugin) error{generateGo, generateGoGRPC} {
+		if err := runPlugin(request, outputPath, generate); err != nil {
+			return err
+		}
+	}
+	if !compiler.HTTPGateway {
+		return nil
+	}
+
+	// run protoc-gen-grpc-gateway with the same parameter, the OpenAPI spec is always written next to the proto file
+	if err := runExternalPlugin(request, outputPath, gatewayPluginName); err != nil {
+		return err
+	}
+	request.Parameter = nil
+	return runExternalPlugin(request, GetProtoRootPath(protoFilePath, protoFileIncludePath), openAPIPluginName)
+}
+
+// appendFileDescriptorProtos appends the file descriptor protos of fileDesc after the ones of its dependencies
+func appendFileDescriptorProtos(fileProtos []*descriptorpb.FileDescriptorProto,
+	fileDesc *desc.FileDescriptor, visited map[string]bool) []*descriptorpb.FileDescriptorProto {
+	if visited[fileDesc.GetName()] {
+		return fileProtos
+	}
+	visited[fileDesc.GetName()] = true
+
+	for _, dependency := range fileDesc.GetDependencies() {
+		fileProtos = appendFileDescriptorProtos(fileProtos, dependency, visited)
+	}
+	return append(fileProtos, fileDesc.AsFileDescriptorProto())
+}
+
+// generateGo does what protoc-gen-go does
+func generateGo(plugin *protogen.Plugin) error {
+	for _, file := range plugin.Files {
+		if file.Generate {
+			internal_gengo.GenerateFile(plugin, file)
+		}
+	}
+	plugin.SupportedFeatures = internal_gengo.SupportedFeatures
+	return nil
+}
+
+// generateGoGRPC does what protoc-gen-go-grpc does
+func generateGoGRPC(plugin *protogen.Plugin) error {
+	plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	for _, file := range plugin.Files {
+		if file.Generate {
+			generateGRPCFile(plugin, file)
+		}
+	}
+	return nil
+}
+
+// runExternalPlugin executes the plugin binary found in PATH with request and writes the generated files to
+// output path like protoc does
+func runExternalPlugin(request *pluginpb.CodeGeneratorRequest, outputPath, pluginName string) error {
+	input, err := proto.Marshal(request)
+	if err != nil {
+		return xerrors.Errorf("proto.Marshal failed! error:%w", err)
+	}
+
+	output, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	shellCmd := exec.Command(pluginName)
+	shellCmd.Stdin = bytes.NewReader(input)
+	shellCmd.Stdout = output
+	shellCmd.Stderr = stderr
+	if err := shellCmd.Run(); err != nil {
+		return xerrors.Errorf("Execute plugin failed! plugin:%v, output:%v, error:%w",
+			pluginName, strings.TrimSpace(stderr.String()), err)
+	}
+
+	response := &pluginpb.CodeGeneratorResponse{}
+	if err := proto.Unmarshal(output.Bytes(), response); err != nil {
+		return xerrors.Errorf("proto.Unmarshal failed! plugin:%v, error:%w", pluginName, err)
+	}
+	if response.Error != nil {
+		return xerrors.Errorf("Generate code failed! plugin:%v, error:%v", pluginName, response.GetError())
+	}
+	return writeResponseFiles(response, outputPath)
+}
+
+// runPlugin runs the plugin with request and writes the generated files to output path like protoc's '--xxx_out'
+func runPlugin(request *pluginpb.CodeGeneratorRequest, outputPath string, generate func(*protogen.Plugin) error) error {
+	plugin, err := protogen.Options{}.New(request)
+	if err != nil {
+		return xerrors.Errorf("protogen.Options.New failed! error:%w", err)
+	}
+	if err := generate(plugin); err != nil {
+		plugin.Error(err)
+	}
+
+	response := plugin.Response()
+	if response.Error != nil {
+		return xerrors.Errorf("Generate code failed! error:%v", response.GetError())
+	}
+	return writeResponseFiles(response, outputPath)
+}
+
+// writeResponseFiles writes the files generated by plugin to output path
+func writeResponseFiles(response *pluginpb.CodeGeneratorResponse, outputPath string) error {
+	for _, file := range response.File {
+		filePath := filepath.Join(outputPath, filepath.FromSlash(file.GetName()))
+		if err := fsutil.CreatePath(filepath.Dir(filePath), false); err != nil {
+			return xerrors.Errorf("fsutil.CreatePath failed! error:%w", err)
+		}
+		if err := fsutil.WriteFile(filePath, []byte(file.GetContent()), 0666); err != nil {
+			return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package pb
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files with the output of protoc, protoc & the plugins must be in PATH
+var update = flag.Bool("update", false, "update the golden files of native compiler by protoc")
+
+// goldenPath contains the proto & the files generated from it by protoc, protoc-gen-go & protoc-gen-go-grpc
+const goldenPath = "testdata/golden"
+
+// goldenFiles are the files generated from greeter.proto
+var goldenFiles = []string{"greeter.pb.go", "greeter_grpc.pb.go"}
+
+// TestNativeCompilerGolden checks the native compiler generates the same code as protoc does, it guards the
+// internal_gengo package & the port of protoc-gen-go-grpc against upgrades of google.golang.org/protobuf
+func TestNativeCompilerGolden(t *testing.T) {
+	native := compileGolden(t, &NativeCompiler{})
+
+	// compare with protoc directly if it's installed, the golden files are updated from its output
+	if hasProtocPlugins() {
+		protoc := compileGolden(t, &ProtocCompiler{ProtocPath: "protoc"})
+		for _, name := range goldenFiles {
+			if normalizeGenerated(native[name]) != normalizeGenerated(protoc[name]) {
+				t.Errorf("native %v differs from protoc:\n%v\nwant\n%v", name, native[name], protoc[name])
+			}
+			if *update {
+				if err := ioutil.WriteFile(filepath.Join(goldenPath, name+".golden"), []byte(protoc[name]),
+					0644); err != nil {
+					t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+				}
+			}
+		}
+	} else if *update {
+		t.Fatalf("protoc, protoc-gen-go & protoc-gen-go-grpc must be in PATH to update the golden files")
+	}
+
+	for _, name := range goldenFiles {
+		golden, err := ioutil.ReadFile(filepath.Join(goldenPath, name+".golden"))
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+		}
+		if normalizeGenerated(native[name]) != normalizeGenerated(string(golden)) {
+			t.Errorf("native %v differs from golden file:\n%v\nwant\n%v", name, native[name], golden)
+		}
+	}
+}
+
+// compileGolden compiles the golden proto in a temporary directory, the generated files are returned by name
+func compileGolden(t *testing.T, compiler Compiler) map[string]string {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	content, err := ioutil.ReadFile(filepath.Join(goldenPath, "greeter.proto"))
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+	}
+	protoFilePath := filepath.Join(root, "greeter.proto")
+	if err := ioutil.WriteFile(protoFilePath, content, 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+	}
+	if err := compiler.CompileGRPC(protoFilePath, []string{root}); err != nil {
+		t.Fatalf("CompileGRPC failed! error:%v", err)
+	}
+
+	generated := make(map[string]string)
+	for _, name := range goldenFiles {
+		content, err := ioutil.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+		}
+		generated[name] = string(content)
+	}
+	return generated
+}
+
+// hasProtocPlugins checks if protoc & the plugins run by ProtocCompiler are in PATH
+func hasProtocPlugins() bool {
+	for _, name := range []string{"protoc", "protoc-gen-go", "protoc-gen-go-grpc"} {
+		if _, err := exec.LookPath(name); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeGenerated removes the version lines of generated code, e.g.: '// 	protoc        v3.12.3',
+// the native compiler reports protoc as unknown
+func normalizeGenerated(content string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "// \tprotoc") || strings.HasPrefix(line, "// - protoc") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,27 +1,182 @@
 package pb
 
 import (
+	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
 	"golang.org/x/xerrors"
 )
 
+// proto tools used to compile .proto files
+const (
+	// ProtoToolProtoc executes protoc & protoc-gen-go & protoc-gen-go-grpc binaries
+	ProtoToolProtoc = "protoc"
+	// ProtoToolNative parses .proto files and generates code in process, only go toolchain is needed
+	ProtoToolNative = "native"
+	// ProtoToolBuf executes buf with buf.yaml & buf.gen.yaml in api/protobuf_spec
+	ProtoToolBuf = "buf"
+)
+
+// Compiler compiles protobuf file to gRPC service protobuf definition
+type Compiler interface {
+	CompileGRPC(protoFilePath string, protoFileIncludePath []string) error
+}
+
+// CompilerConfig represents the configuration of compilers
+type CompilerConfig struct {
+	ProtoTool  string
+	ProtocPath string
+	BufPath    string
+
+	// protoc runs in work dir
+	WorkDir string
+	// the generated files are written into the directory of go_package relative to work dir instead of next to
+	// the proto file if go module is specified, the go_package must be in go module
+	GoModule string
+	// run protoc-gen-grpc-gateway & protoc-gen-openapiv2 as well, the OpenAPI spec is always written next to the proto file
+	HTTPGateway bool
+
+	// buf module directory which contains buf.yaml & buf.gen.yaml, and the descriptor set built by buf
+	BufModulePath     string
+	DescriptorSetPath string
+	// check breaking changes against the descriptor set before compiling
+	BreakingCheck bool
+}
+
+// NewCompiler returns the compiler of proto tool
+func NewCompiler(config *CompilerConfig) (Compiler, error) {
+	switch config.ProtoTool {
+	case "", ProtoToolProtoc:
+		return &ProtocCompiler{ProtocPath: config.ProtocPath, WorkDir: config.WorkDir,
+			GoModule: config.GoModule, HTTPGateway: config.HTTPGateway}, nil
+	case ProtoToolNative:
+		return &NativeCompiler{WorkDir: config.WorkDir, GoModule: config.GoModule, HTTPGateway: config.HTTPGateway}, nil
+	case ProtoToolBuf:
+		return &BufCompiler{
+			BufPath:           config.BufPath,
+			ModulePath:        config.BufModulePath,
+			DescriptorSetPath: config.DescriptorSetPath,
+			BreakingCheck:     config.BreakingCheck,
+		}, nil
+	default:
+		return nil, xerrors.Errorf("Unknown proto tool! proto tool:%v, supported:[%v, %v, %v]",
+			config.ProtoTool, ProtoToolProtoc, ProtoToolNative, ProtoToolBuf)
+	}
+}
+
+// ProtocCompiler compiles protobuf file by executing protoc
+type ProtocCompiler struct {
+	ProtocPath  string
+	WorkDir     string
+	GoModule    string
+	HTTPGateway bool
+}
+
 // CompileGRPC compiles protobuf file to gRPC service protobuf definition
-func CompileGRPC(protocPath, protoFilePath string, protoFileIncludePath []string) error {
-	// build args which includes proto file include path
+func (compiler *ProtocCompiler) CompileGRPC(protoFilePath string, protoFileIncludePath []string) error {
+	return compileGRPC(compiler.ProtocPath, compiler.WorkDir, compiler.GoModule, protoFilePath, protoFileIncludePath,
+		compiler.HTTPGateway)
+}
+
+// compileGRPC executes protoc with the go & go-grpc plugins, and the grpc-gateway & openapiv2 plugins
+// if httpGateway is true, protoc runs in work dir and the generated files are written next to the proto file,
+// or into the directory of go_package relative to work dir if go module is not empty
+func compileGRPC(protocPath, workDir, goModule, protoFilePath string, protoFileIncludePath []string,
+	httpGateway bool) error {
+	// build args which includes proto file include path, paths are relative to work dir
 	args := []string{}
 	for _, path := range protoFileIncludePath {
-		arg := fmt.Sprintf("--proto_path=%v", path)
+		arg := fmt.Sprintf("--proto_path=%v", rebasePath(workDir, path))
 		args = append(args, arg)
 	}
-	args = append(args, "--go_out=.", "--go_opt=paths=source_relative", "--go-grpc_out=.", "--go-grpc_opt=paths=source_relative")
-	args = append(args, protoFilePath)
+	rootPath := rebasePath(workDir, GetProtoRootPath(protoFilePath, protoFileIncludePath))
+	outputPath, outputOption := rootPath, "paths=source_relative"
+	if goModule != "" {
+		outputPath, outputOption = ".", "module="+goModule
+	}
+	args = append(args,
+		fmt.Sprintf("--go_out=%v", outputPath), fmt.Sprintf("--go_opt=%v", outputOption),
+		fmt.Sprintf("--go-grpc_out=%v", outputPath), fmt.Sprintf("--go-grpc_opt=%v", outputOption))
+	if httpGateway {
+		args = append(args,
+			fmt.Sprintf("--grpc-gateway_out=%v", outputPath), fmt.Sprintf("--grpc-gateway_opt=%v", outputOption),
+			fmt.Sprintf("--openapiv2_out=%v", rootPath))
+	}
+	args = append(args, rebasePath(workDir, protoFilePath))
 
 	// execute protoc to generate .pb.go file
+	output := &bytes.Buffer{}
 	shellCmd := exec.Command(protocPath, args...)
+	shellCmd.Dir = workDir
+	shellCmd.Stdout = output
+	shellCmd.Stderr = output
 	if err := shellCmd.Run(); err != nil {
-		return xerrors.Errorf("%v %v failed! error:%v", protocPath, args, err.Error())
+		return &CompileError{
+			Command:     strings.Join(append([]string{protocPath}, args...), " "),
+			Output:      output.String(),
+			Diagnostics: parseDiagnostics(output.String(), workDir, protoFileIncludePath),
+			Err:         err,
+		}
 	}
 	return nil
 }
+
+// GetProtoFileName returns the name of proto file relative to the first include path containing it like protoc does,
+// e.g.: user/user.proto
+func GetProtoFileName(protoFilePath string, protoFileIncludePath []string) string {
+	for _, includePath := range protoFileIncludePath {
+		if relativePath, ok := getRelativePath(includePath, protoFilePath); ok {
+			return filepath.ToSlash(relativePath)
+		}
+	}
+	return filepath.ToSlash(protoFilePath)
+}
+
+// GetProtoRootPath returns the first include path containing proto file, e.g.: api/protobuf_spec
+func GetProtoRootPath(protoFilePath string, protoFileIncludePath []string) string {
+	for _, includePath := range protoFileIncludePath {
+		if _, ok := getRelativePath(includePath, protoFilePath); ok {
+			return includePath
+		}
+	}
+	return "."
+}
+
+// FindProtoFile returns the path of proto file in the first include path containing it, it's empty if not found
+func FindProtoFile(protoFileName string, protoFileIncludePath []string) string {
+	for _, includePath := range protoFileIncludePath {
+		protoFilePath := filepath.Join(includePath, filepath.FromSlash(protoFileName))
+		if isExist, err := fsutil.CheckPathExists(protoFilePath); err == nil && isExist {
+			return protoFilePath
+		}
+	}
+	return ""
+}
+
+// rebasePath returns path relative to work dir, the absolute path is returned if it's not under work dir
+func rebasePath(workDir, path string) string {
+	if workDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	relativePath, err := filepath.Rel(workDir, path)
+	if err != nil {
+		if absPath, err := filepath.Abs(path); err == nil {
+			return absPath
+		}
+		return path
+	}
+	return relativePath
+}
+
+// getRelativePath returns path relative to dir if path is under dir
+func getRelativePath(dir, path string) (string, bool) {
+	relativePath, err := filepath.Rel(dir, path)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return relativePath, true
+}
@@ -8,6 +8,10 @@ import (
 	"golang.org/x/xerrors"
 )
 
+func init() {
+	templates.Register("template-docker-file", DockerFileTemplate)
+}
+
 // DockerFileInfo represents docker file information
 type DockerFileInfo struct {
 	Opts    *option.Options
@@ -0,0 +1,247 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"golang.org/x/xerrors"
+)
+
+// openAPIVersion is the version of OpenAPI specification the document follows
+const openAPIVersion = "3.0.3"
+
+// OpenAPIDocument represents an OpenAPI 3 document, only the parts used by gRPC services are defined
+type OpenAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       *OpenAPIInfo                `json:"info"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents          `json:"components"`
+}
+
+// OpenAPIInfo represents the info object of OpenAPI document
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIPathItem represents the path item object, RPCs are always called by POST
+type OpenAPIPathItem struct {
+	Post *OpenAPIOperation `json:"post"`
+}
+
+// OpenAPIOperation represents the operation object, Streaming is the 'x-grpc-streaming' extension marking
+// the streaming mode of RPC, e.g.: server streaming
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Streaming   string                      `json:"x-grpc-streaming,omitempty"`
+}
+
+// OpenAPIRequestBody represents the request body object
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse represents the response object
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType represents the media type object
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIComponents represents the components object
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPISchema represents the schema object
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+}
+
+// NewOpenAPIDocument returns the OpenAPI document of gRPC service, the RPCs are described as
+// 'POST /{service full name}/{RPC}' with the JSON mapping of proto3 messages.
+//
+// OpenAPI 3 can't describe a stream of messages, so streaming RPCs are kept for their messages to be documented,
+// the streamed body is described by one message of the stream and the operation is marked by 'x-grpc-streaming'
+func NewOpenAPIDocument(service *ServiceDoc) *OpenAPIDocument {
+	document := &OpenAPIDocument{
+		OpenAPI:    openAPIVersion,
+		Info:       &OpenAPIInfo{Title: service.FullName, Description: service.Comment, Version: "1.0.0"},
+		Paths:      make(map[string]*OpenAPIPathItem),
+		Components: &OpenAPIComponents{Schemas: make(map[string]*OpenAPISchema)},
+	}
+
+	for _, rpc := range service.RPCs {
+		description, streaming := rpc.Comment, ""
+		if rpc.ClientStreaming || rpc.ServerStreaming {
+			description = strings.TrimSpace(fmt.Sprintf("%v\n\n%v RPC, it can't be called by plain HTTP/JSON, "+
+				"the streamed body is described by one message of the stream.", description, rpc.StreamingMode))
+			streaming = rpc.StreamingMode
+		}
+		document.Paths[fmt.Sprintf("/%v/%v", service.FullName, rpc.Name)] = &OpenAPIPathItem{
+			Post: &OpenAPIOperation{
+				OperationID: fmt.Sprintf("%v_%v", service.Name, rpc.Name),
+				Summary:     strings.SplitN(rpc.Comment, "\n", 2)[0],
+				Description: description,
+				RequestBody: &OpenAPIRequestBody{
+					Required: true,
+					Content:  newOpenAPIContent(getOpenAPIRef(rpc.RequestType)),
+				},
+				Responses: map[string]*OpenAPIResponse{
+					"200": {Description: "OK", Content: newOpenAPIContent(getOpenAPIRef(rpc.ResponseType))},
+				},
+				Streaming: streaming,
+			},
+		}
+	}
+
+	for _, message := range service.Messages {
+		schema := &OpenAPISchema{
+			Type:        "object",
+			Description: message.Comment,
+			Properties:  make(map[string]*OpenAPISchema),
+		}
+		for _, field := range message.Fields {
+			schema.Properties[field.JSONName] = getOpenAPIFieldSchema(field)
+		}
+		document.Components.Schemas[message.FullName] = schema
+	}
+
+	for _, enum := range service.Enums {
+		schema := &OpenAPISchema{Type: "string", Description: enum.Comment}
+		for _, value := range enum.Values {
+			schema.Enum = append(schema.Enum, value.Name)
+		}
+		document.Components.Schemas[enum.FullName] = schema
+	}
+	return document
+}
+
+// RenderOpenAPIFile marshals the OpenAPI document of service in JSON and output to file
+func (info *ServiceDocInfo) RenderOpenAPIFile(outputPath string) error {
+	isExist, err := fsutil.CheckPathExists(outputPath)
+	if err != nil {
+		return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if isExist && !info.Opts.Override {
+		if info.Opts.IgnoreExist {
+			return nil
+		}
+		return xerrors.Errorf("File already exists! this operation will override it! file path:%v", outputPath)
+	}
+
+	// comments are kept as they are, e.g.: '&' is not escaped to '\u0026'
+	content := &bytes.Buffer{}
+	encoder := json.NewEncoder(content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(NewOpenAPIDocument(info.Service)); err != nil {
+		return xerrors.Errorf("encoder.Encode failed! error:%w", err)
+	}
+	if err := fsutil.WriteFile(outputPath, content.Bytes(), 0666); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// newOpenAPIContent returns the JSON content of schema
+func newOpenAPIContent(schema *OpenAPISchema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+// getOpenAPIRef returns the reference to schema of message or enum in components
+func getOpenAPIRef(fullName string) *OpenAPISchema {
+	if schema, ok := wellKnownSchemas[fullName]; ok {
+		return schema
+	}
+	return &OpenAPISchema{Ref: "#/components/schemas/" + fullName}
+}
+
+// getOpenAPIFieldSchema returns the schema of field according to the JSON mapping of proto3
+func getOpenAPIFieldSchema(field *FieldDoc) *OpenAPISchema {
+	var valueSchema *OpenAPISchema
+	switch {
+	case field.ValueIsMessage || field.ValueIsEnum:
+		valueSchema = getOpenAPIRef(field.ValueType)
+	case scalarSchemas[field.ValueType] != nil:
+		valueSchema = scalarSchemas[field.ValueType]
+	default:
+		valueSchema = &OpenAPISchema{}
+	}
+
+	var schema *OpenAPISchema
+	switch {
+	case field.Map:
+		schema = &OpenAPISchema{Type: "object", AdditionalProperties: valueSchema}
+	case field.Repeated:
+		schema = &OpenAPISchema{Type: "array", Items: valueSchema}
+	case valueSchema.Ref != "":
+		// sibling keywords of $ref are ignored, so the description is not kept
+		return valueSchema
+	default:
+		copied := *valueSchema
+		schema = &copied
+	}
+	schema.Description = field.Comment
+	return schema
+}
+
+// scalarSchemas maps the proto3 scalar types to the JSON schemas, 64-bit integers are strings in JSON
+var scalarSchemas = map[string]*OpenAPISchema{
+	"double":   {Type: "number", Format: "double"},
+	"float":    {Type: "number", Format: "float"},
+	"int32":    {Type: "integer", Format: "int32"},
+	"sint32":   {Type: "integer", Format: "int32"},
+	"sfixed32": {Type: "integer", Format: "int32"},
+	"uint32":   {Type: "integer", Format: "int64"},
+	"fixed32":  {Type: "integer", Format: "int64"},
+	"int64":    {Type: "string", Format: "int64"},
+	"sint64":   {Type: "string", Format: "int64"},
+	"sfixed64": {Type: "string", Format: "int64"},
+	"uint64":   {Type: "string", Format: "uint64"},
+	"fixed64":  {Type: "string", Format: "uint64"},
+	"bool":     {Type: "boolean"},
+	"string":   {Type: "string"},
+	"bytes":    {Type: "string", Format: "byte"},
+}
+
+// wellKnownSchemas maps the well-known types with special JSON mapping to the JSON schemas
+var wellKnownSchemas = map[string]*OpenAPISchema{
+	"google.protobuf.Timestamp":   {Type: "string", Format: "date-time"},
+	"google.protobuf.Duration":    {Type: "string"},
+	"google.protobuf.Empty":       {Type: "object"},
+	"google.protobuf.Struct":      {Type: "object"},
+	"google.protobuf.Any":         {Type: "object"},
+	"google.protobuf.Value":       {},
+	"google.protobuf.ListValue":   {Type: "array", Items: &OpenAPISchema{}},
+	"google.protobuf.FieldMask":   {Type: "string"},
+	"google.protobuf.StringValue": {Type: "string"},
+	"google.protobuf.BytesValue":  {Type: "string", Format: "byte"},
+	"google.protobuf.BoolValue":   {Type: "boolean"},
+	"google.protobuf.Int32Value":  {Type: "integer", Format: "int32"},
+	"google.protobuf.UInt32Value": {Type: "integer", Format: "int64"},
+	"google.protobuf.Int64Value":  {Type: "string", Format: "int64"},
+	"google.protobuf.UInt64Value": {Type: "string", Format: "uint64"},
+	"google.protobuf.FloatValue":  {Type: "number", Format: "float"},
+	"google.protobuf.DoubleValue": {Type: "number", Format: "double"},
+}
@@ -0,0 +1,223 @@
+package docs
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-docs-markdown", MarkdownTemplate)
+	templates.Register("template-docs-html", HTMLTemplate)
+}
+
+// ServiceDoc represents the API document of a gRPC service
+type ServiceDoc struct {
+	// e.g.: UserService
+	Name string
+	// e.g.: user.UserService
+	FullName string
+	// proto file name relative to api/protobuf_spec, e.g.: user/user.proto
+	ProtoFile string
+	Comment   string
+	RPCs      []*RPCDoc
+	// messages & enums used by the RPCs including the nested ones, sorted by full name
+	Messages []*MessageDoc
+	Enums    []*EnumDoc
+}
+
+// RPCDoc represents the API document of a RPC
+type RPCDoc struct {
+	Name    string
+	Anchor  string
+	Comment string
+	// full name of request & response message, e.g.: user.AddUserRequest
+	RequestType  string
+	ResponseType string
+	// anchor of request & response message in document, empty if it's a well-known type
+	RequestAnchor  string
+	ResponseAnchor string
+	// e.g.: unary, server streaming
+	StreamingMode   string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// MessageDoc represents the API document of a message
+type MessageDoc struct {
+	// e.g.: user.AddUserRequest
+	FullName string
+	Anchor   string
+	Comment  string
+	Fields   []*FieldDoc
+}
+
+// FieldDoc represents the API document of a message field
+type FieldDoc struct {
+	Name     string
+	JSONName string
+	Number   int32
+	// e.g.: repeated string, map<string, user.User>
+	Type string
+	// comment in one line
+	Comment string
+	// anchor of the message or enum of value type, empty if it's scalar or not documented
+	Anchor   string
+	Repeated bool
+	Map      bool
+	// type of element, or of value if it's a map, e.g.: string, user.User
+	ValueType      string
+	ValueIsMessage bool
+	ValueIsEnum    bool
+}
+
+// EnumDoc represents the API document of an enum
+type EnumDoc struct {
+	// e.g.: user.Gender
+	FullName string
+	Anchor   string
+	Comment  string
+	Values   []*EnumValueDoc
+}
+
+// EnumValueDoc represents the API document of an enum value
+type EnumValueDoc struct {
+	Name   string
+	Number int32
+	// comment in one line
+	Comment string
+}
+
+// ServiceDocInfo represents the API document file information of a gRPC service
+type ServiceDocInfo struct {
+	Opts    *option.Options
+	Service *ServiceDoc
+}
+
+// NewServiceDocInfo returns a new ServiceDocInfo pointer
+func NewServiceDocInfo(service *ServiceDoc, opts ...option.Option) *ServiceDocInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &ServiceDocInfo{Opts: newOpts, Service: service}
+}
+
+// RenderMarkdownFile render markdown template and output to file
+func (info *ServiceDocInfo) RenderMarkdownFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, info.Opts.Override, info.Opts.IgnoreExist,
+		"template-docs-markdown", MarkdownTemplate, info); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// RenderHTMLFile render html template and output to file
+func (info *ServiceDocInfo) RenderHTMLFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, info.Opts.Override, info.Opts.IgnoreExist,
+		"template-docs-html", HTMLTemplate, info); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// MarkdownTemplate defines the API document of a gRPC service in markdown
+var MarkdownTemplate string = `<!-- Code generated by gofra docs. DO NOT EDIT. -->
+# {{.Service.Name}}
+
+{{if .Service.Comment}}{{.Service.Comment}}
+
+{{end}}- Service: ` + "`{{.Service.FullName}}`" + `
+- Proto: ` + "`{{.Service.ProtoFile}}`" + `
+
+## RPCs
+
+| RPC | Request | Response | Streaming |
+| --- | --- | --- | --- |
+{{range .Service.RPCs}}| [{{.Name}}](#{{.Anchor}}) | {{if .RequestAnchor}}[{{.RequestType}}](#{{.RequestAnchor}}){{else}}{{.RequestType}}{{end}} | {{if .ResponseAnchor}}[{{.ResponseType}}](#{{.ResponseAnchor}}){{else}}{{.ResponseType}}{{end}} | {{.StreamingMode}} |
+{{end}}
+{{range .Service.RPCs}}### rpc {{.Name}}
+
+{{if .Comment}}{{.Comment}}
+
+{{end}}` + "```" + `
+rpc {{.Name}}({{if .ClientStreaming}}stream {{end}}{{.RequestType}}) returns ({{if .ServerStreaming}}stream {{end}}{{.ResponseType}})
+` + "```" + `
+
+{{end}}## Messages
+{{range .Service.Messages}}
+### {{.FullName}}
+
+{{if .Comment}}{{.Comment}}
+
+{{end}}{{if .Fields}}| Field | Number | Type | Description |
+| --- | --- | --- | --- |
+{{range .Fields}}| {{.Name}} | {{.Number}} | {{if .Anchor}}[` + "`{{.Type}}`" + `](#{{.Anchor}}){{else}}` + "`{{.Type}}`" + `{{end}} | {{.Comment}} |
+{{end}}{{else}}No fields.
+{{end}}{{end}}{{if .Service.Enums}}
+## Enums
+{{range .Service.Enums}}
+### {{.FullName}}
+
+{{if .Comment}}{{.Comment}}
+
+{{end}}| Name | Number | Description |
+| --- | --- | --- |
+{{range .Values}}| {{.Name}} | {{.Number}} | {{.Comment}} |
+{{end}}{{end}}{{end}}`
+
+// HTMLTemplate defines the API document of a gRPC service in static html
+var HTMLTemplate string = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="generator" content="Code generated by gofra docs. DO NOT EDIT.">
+<title>{{.Service.Name}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 960px; color: #24292e; }
+table { border-collapse: collapse; margin: 1em 0; width: 100%; }
+th, td { border: 1px solid #dfe2e5; padding: 6px 12px; text-align: left; }
+th { background: #f6f8fa; }
+code, pre { background: #f6f8fa; font-family: SFMono-Regular, Consolas, Menlo, monospace; }
+pre { padding: 12px; }
+.comment { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Service.Name}}</h1>
+{{if .Service.Comment}}<p class="comment">{{.Service.Comment}}</p>
+{{end}}<ul>
+<li>Service: <code>{{.Service.FullName}}</code></li>
+<li>Proto: <code>{{.Service.ProtoFile}}</code></li>
+</ul>
+
+<h2>RPCs</h2>
+<table>
+<tr><th>RPC</th><th>Request</th><th>Response</th><th>Streaming</th></tr>
+{{range .Service.RPCs}}<tr><td><a href="#{{.Anchor}}">{{.Name}}</a></td><td>{{if .RequestAnchor}}<a href="#{{.RequestAnchor}}">{{.RequestType}}</a>{{else}}{{.RequestType}}{{end}}</td><td>{{if .ResponseAnchor}}<a href="#{{.ResponseAnchor}}">{{.ResponseType}}</a>{{else}}{{.ResponseType}}{{end}}</td><td>{{.StreamingMode}}</td></tr>
+{{end}}</table>
+{{range .Service.RPCs}}
+<h3 id="{{.Anchor}}">rpc {{.Name}}</h3>
+{{if .Comment}}<p class="comment">{{.Comment}}</p>
+{{end}}<pre>rpc {{.Name}}({{if .ClientStreaming}}stream {{end}}{{.RequestType}}) returns ({{if .ServerStreaming}}stream {{end}}{{.ResponseType}})</pre>
+{{end}}
+<h2>Messages</h2>
+{{range .Service.Messages}}
+<h3 id="{{.Anchor}}">{{.FullName}}</h3>
+{{if .Comment}}<p class="comment">{{.Comment}}</p>
+{{end}}{{if .Fields}}<table>
+<tr><th>Field</th><th>Number</th><th>Type</th><th>Description</th></tr>
+{{range .Fields}}<tr><td>{{.Name}}</td><td>{{.Number}}</td><td>{{if .Anchor}}<a href="#{{.Anchor}}">{{.Type}}</a>{{else}}{{.Type}}{{end}}</td><td>{{.Comment}}</td></tr>
+{{end}}</table>
+{{else}}<p>No fields.</p>
+{{end}}{{end}}{{if .Service.Enums}}
+<h2>Enums</h2>
+{{range .Service.Enums}}
+<h3 id="{{.Anchor}}">{{.FullName}}</h3>
+{{if .Comment}}<p class="comment">{{.Comment}}</p>
+{{end}}<table>
+<tr><th>Name</th><th>Number</th><th>Description</th></tr>
+{{range .Values}}<tr><td>{{.Name}}</td><td>{{.Number}}</td><td>{{.Comment}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}</body>
+</html>
+`
@@ -7,6 +7,10 @@ import (
 	"github.com/DarkMetrix/gofra/internal/pkg/templates"
 )
 
+func init() {
+	templates.Register("template-go-module", GoModuleTemplate)
+}
+
 // GoModuleInfo represents go module information
 type GoModuleInfo struct {
 	Opts      *option.Options
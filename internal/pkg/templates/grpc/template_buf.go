@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-buf-yaml", BufYAMLTemplate)
+	templates.Register("template-buf-gen-yaml", BufGenYAMLTemplate)
+}
+
+// BufYAMLInfo represents buf.yaml information
+type BufYAMLInfo struct {
+	Opts *option.Options
+}
+
+// NewBufYAMLInfo returns a new BufYAMLInfo pointer
+func NewBufYAMLInfo(opts ...option.Option) *BufYAMLInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &BufYAMLInfo{Opts: newOpts}
+}
+
+// RenderFile render template and output to file
+func (info *BufYAMLInfo) RenderFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, info.Opts.Override, info.Opts.IgnoreExist,
+		"template-buf-yaml", BufYAMLTemplate, info); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// BufYAMLTemplate defines the buf module of api/protobuf_spec
+var BufYAMLTemplate string = `# buf module configuration, see https://docs.buf.build/configuration/v1/buf-yaml
+#
+# deps
+#	Remote dependencies of the module instead of '--proto-include-path', run 'buf mod update' after changing.
+# 	eg:
+#		deps:
+#		  - buf.build/googleapis/googleapis
+# breaking
+#	Rules used by 'buf breaking' and 'gofra service add/update --breaking-check'.
+# lint
+#	Rules used by 'buf lint'.
+version: v1
+breaking:
+  use:
+    - FILE
+lint:
+  use:
+    - DEFAULT
+`
+
+// BufGenYAMLInfo represents buf.gen.yaml information
+type BufGenYAMLInfo struct {
+	Opts *option.Options
+}
+
+// NewBufGenYAMLInfo returns a new BufGenYAMLInfo pointer
+func NewBufGenYAMLInfo(opts ...option.Option) *BufGenYAMLInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &BufGenYAMLInfo{Opts: newOpts}
+}
+
+// RenderFile render template and output to file
+func (info *BufGenYAMLInfo) RenderFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, info.Opts.Override, info.Opts.IgnoreExist,
+		"template-buf-gen-yaml", BufGenYAMLTemplate, info); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// Render renders template to bytes, it's used to check if buf.gen.yaml is modified
+func (info *BufGenYAMLInfo) Render(outputPath string) ([]byte, error) {
+	content, err := templates.Render(outputPath, "template-buf-gen-yaml", BufGenYAMLTemplate, info)
+	if err != nil {
+		return nil, xerrors.Errorf("Render failed! error:%w", err)
+	}
+	return content, nil
+}
+
+// BufGenYAMLTemplate defines the code generation of buf, the generated files are written to the directory of
+// go_package in go module if it's known (out is the project root relative to api/protobuf_spec),
+// otherwise they're next to .proto files
+var BufGenYAMLTemplate string = `# buf code generation configuration, see https://docs.buf.build/configuration/v1/buf-gen-yaml
+{{- if .Opts.GoModule}}
+#
+# The go_package option of every proto must be in go module {{.Opts.GoModule}}.
+{{- end}}
+version: v1
+plugins:
+{{- if .Opts.GoModule}}
+  - plugin: go
+    out: ../..
+    opt: module={{.Opts.GoModule}}
+  - plugin: go-grpc
+    out: ../..
+    opt: module={{.Opts.GoModule}}
+{{- if .Opts.HTTPGateway}}
+  - plugin: grpc-gateway
+    out: ../..
+    opt: module={{.Opts.GoModule}}
+  - plugin: openapiv2
+    out: .
+{{- end}}
+{{- else}}
+  - plugin: go
+    out: .
+    opt: paths=source_relative
+  - plugin: go-grpc
+    out: .
+    opt: paths=source_relative
+{{- if .Opts.HTTPGateway}}
+  - plugin: grpc-gateway
+    out: .
+    opt: paths=source_relative
+  - plugin: openapiv2
+    out: .
+{{- end}}
+{{- end}}
+`
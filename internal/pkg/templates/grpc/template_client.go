@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-client", ClientTemplate)
+}
+
+// ClientInfo represents the client SDK file information of a gRPC service
+type ClientInfo struct {
+	Opts        *option.Options
+	PackageName string
+	ServiceName string
+	// e.g.: user.UserService
+	FullName string
+	// go package of the service proto, imported as pb
+	ImportedPackageName string
+	// go packages of request & response types defined in other protos
+	Imports []*GoImport
+	RPCs    []*ClientRPC
+}
+
+// ClientRPC represents a RPC of the client SDK
+type ClientRPC struct {
+	Name string
+	// qualified go types, e.g.: pb.AddUserRequest, emptypb.Empty
+	Request         string
+	Response        string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// NewClientInfo returns a new ClientInfo pointer
+func NewClientInfo(opts ...option.Option) *ClientInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &ClientInfo{
+		Opts:                newOpts,
+		PackageName:         newOpts.PackageName,
+		ServiceName:         newOpts.ServiceName,
+		ImportedPackageName: newOpts.ImportedPackageName,
+	}
+}
+
+// RenderFile render template and output to file
+func (client *ClientInfo) RenderFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, client.Opts.Override, client.Opts.IgnoreExist,
+		"template-client", ClientTemplate, client); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// ClientTemplate renders the typed client SDK of a gRPC service, the method signatures follow the generated
+// {{.ServiceName}}Client of protoc-gen-go-grpc
+var ClientTemplate string = `// Code generated by gofra. DO NOT EDIT.
+
+// Package {{.PackageName}} is the client SDK of {{.FullName}}, the connections are taken from
+// the gofra connection pool and every call runs through the opentracing, seelog & statsd interceptors
+package {{.PackageName}}
+
+import (
+	"context"
+
+	gofraClient "github.com/DarkMetrix/gofra/pkg/grpc-utils/client"
+	"google.golang.org/grpc"
+
+	pb "{{.ImportedPackageName}}"
+{{- range .Imports}}
+	{{.Alias}} "{{.Path}}"
+{{- end}}
+)
+
+// Option sets the timeouts, retries & interceptors of Client, e.g.: WithRetries(3)
+type Option = gofraClient.Option
+
+// options of Client, the defaults are described by gofraClient.NewOptions
+var (
+	WithTimeout            = gofraClient.WithTimeout
+	WithRPCTimeout         = gofraClient.WithRPCTimeout
+	WithRetries            = gofraClient.WithRetries
+	WithRetryBackoff       = gofraClient.WithRetryBackoff
+	WithRetryCodes         = gofraClient.WithRetryCodes
+	WithUnaryInterceptors  = gofraClient.WithUnaryInterceptors
+	WithStreamInterceptors = gofraClient.WithStreamInterceptors
+)
+
+// Client is the client of {{.FullName}}
+type Client struct {
+	client pb.{{.ServiceName}}Client
+}
+
+// NewClient returns a new Client pointer to the address of {{.ServiceName}}, e.g.: localhost:58888
+func NewClient(addr string, opts ...Option) *Client {
+	return &Client{client: pb.New{{.ServiceName}}Client(gofraClient.NewConn(addr, opts...))}
+}
+{{range .RPCs}}
+{{- if or .ClientStreaming .ServerStreaming}}
+{{- if .ClientStreaming}}
+// {{.Name}} starts the {{if .ServerStreaming}}bidirectional{{else}}client{{end}} streaming RPC {{$.FullName}}/{{.Name}}
+func (c *Client) {{.Name}}(ctx context.Context, opts ...grpc.CallOption) (pb.{{$.ServiceName}}_{{.Name}}Client, error) {
+	return c.client.{{.Name}}(ctx, opts...)
+}
+{{- else}}
+// {{.Name}} starts the server streaming RPC {{$.FullName}}/{{.Name}}
+func (c *Client) {{.Name}}(ctx context.Context, req *{{.Request}}, opts ...grpc.CallOption) (pb.{{$.ServiceName}}_{{.Name}}Client, error) {
+	return c.client.{{.Name}}(ctx, req, opts...)
+}
+{{- end}}
+{{- else}}
+// {{.Name}} calls the RPC {{$.FullName}}/{{.Name}}
+func (c *Client) {{.Name}}(ctx context.Context, req *{{.Request}}, opts ...grpc.CallOption) (*{{.Response}}, error) {
+	return c.client.{{.Name}}(ctx, req, opts...)
+}
+{{- end}}
+{{end}}`
@@ -7,6 +7,11 @@ import (
 	"github.com/DarkMetrix/gofra/internal/pkg/templates"
 )
 
+func init() {
+	templates.Register("template-config", ConfigTemplate)
+	templates.Register("template-config-yaml", ConfigYAMLTemplate)
+}
+
 // ConfigInfo represents the Config information
 type ConfigInfo struct {
 	Opts *option.Options
@@ -51,19 +56,39 @@ type Config struct {
 
 // ServerInfo definition
 type ServerInfo struct {
+	Name string "mapstructure:\"name\" json:\"name\""
 	Addr string "mapstructure:\"addr\" json:\"addr\""
+	Reflection bool "mapstructure:\"reflection\" json:\"reflection\""
+	HTTPAddr string "mapstructure:\"http_addr\" json:\"http_addr\""
+	OpenAPIPath string "mapstructure:\"openapi_path\" json:\"openapi_path\""
 }
 
 // ObservabilityInfo definition
 type ObservabilityInfo struct {
 	Log LogInfo "mapstructure:\"log\" json:\"log\""
+	Metrics MetricsInfo "mapstructure:\"metrics\" json:\"metrics\""
+	Tracing TracingInfo "mapstructure:\"tracing\" json:\"tracing\""
 }
 
 // LogInfo definition
-type LogInfo struct {}
+type LogInfo struct {
+	Enable bool "mapstructure:\"enable\" json:\"enable\""
+	Path string "mapstructure:\"path\" json:\"path\""
+}
 
 // MetricsInfo definition
-type MetricsInfo struct {}
+type MetricsInfo struct {
+	Enable bool "mapstructure:\"enable\" json:\"enable\""
+	Type string "mapstructure:\"type\" json:\"type\""
+	Addr string "mapstructure:\"addr\" json:\"addr\""
+}
+
+// TracingInfo definition
+type TracingInfo struct {
+	Enable bool "mapstructure:\"enable\" json:\"enable\""
+	Type string "mapstructure:\"type\" json:\"type\""
+	Addr string "mapstructure:\"addr\" json:\"addr\""
+}
 
 // PprofInfo definition
 type PprofInfo struct {
@@ -139,26 +164,69 @@ func (yaml *ConfigYAMLInfo) RenderFile(outputPath string) error {
 
 var ConfigYAMLTemplate string = `# Server configuration
 #
+# server.name
+#	Server's name, it's used as the project of logs & metrics and the service name of tracing
 # server.addr
 #	Server's address to listen on.
 # 	eg: 
 #		localhost:58888
 #		127.0.0.1:58888
 #		eth0:58888
+# server.reflection
+#	Is gRPC server reflection enabled or not, it helps tools like grpcurl & BloomRPC to inspect the services,
+#	it's recommended to disable it in production
+# server.http_addr
+#	Address of the http gateway to listen on, it's used if services are added with '--http-gateway'
+# 	eg:
+#		localhost:58880
+# server.openapi_path
+#	Directory of the generated OpenAPI specs served by the http gateway on '/openapi'
 server:
+  name: "{{.Opts.Project}}"
   addr: "localhost:58888"
+  reflection: true
+  http_addr: "localhost:58880"
+  openapi_path: "../api/protobuf_spec"
 
 # Observability configuration
+#
+# observability.log.enable
+#	Is request & response logging enabled or not, logs are written by seelog
+# observability.log.path
+#	seelog config file path, if empty logs are written to console
+# observability.metrics.enable
+#	Is metrics enabled or not
+# observability.metrics.type
+#	Metrics backend, available option [statsd, prometheus]
+# observability.metrics.addr
+#	statsd: address of the statsd agent, eg: 127.0.0.1:8125
+#	prometheus: http address to listen on for scraping, eg: localhost:9090, metrics are served on '/metrics'
+# observability.tracing.enable
+#	Is tracing enabled or not
+# observability.tracing.type
+#	Tracing backend, available option [jaeger, zipkin]
+# observability.tracing.addr
+#	jaeger: address of the jaeger agent in udp, eg: 127.0.0.1:6831
+#	zipkin: url of the zipkin collector, eg: http://127.0.0.1:9411/api/v2/spans
 observability:
   log:
+    enable: false
+    path: ""
   metrics:
+    enable: false
+    type: "statsd"
+    addr: "127.0.0.1:8125"
+  tracing:
+    enable: false
+    type: "jaeger"
+    addr: "127.0.0.1:6831"
 
 # Client configuration
 # [client]
 
 # pprof configuration
 #
-# pprof.active
+# pprof.enable
 #	Is pprof enabled or not
 # pprof.addr
 #	Http address to listen on for getting profile information
@@ -175,7 +243,7 @@ pprof:
 # performance.enable
 #	Is performance monitor enabled or not
 # performance.type
-#   Type to output the performance monitor information, available option [log, statsd]
+#   Type to output the performance monitor information, available option [log, statsd, prometheus]
 performance:
   enable: false
   type: "log"
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-gateway", GatewayTemplate)
+}
+
+// GatewayInfo represents the http gateway file information
+type GatewayInfo struct {
+	Opts              *option.Options
+	ConfigPackagePath string
+}
+
+// NewGatewayInfo returns a new GatewayInfo pointer
+func NewGatewayInfo(opts ...option.Option) *GatewayInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &GatewayInfo{Opts: newOpts, ConfigPackagePath: newOpts.ConfigPackagePath}
+}
+
+// RenderFile render template and output to file
+func (gateway *GatewayInfo) RenderFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, gateway.Opts.Override, gateway.Opts.IgnoreExist,
+		"template-gateway", GatewayTemplate, gateway); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// GatewayTemplate defines the http gateway which proxies REST/JSON requests to the gRPC server
+var GatewayTemplate string = `package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	seelogMiddleware "github.com/DarkMetrix/gofra/pkg/gin-utils/middleware/log_middleware/seelog"
+	prometheusMiddleware "github.com/DarkMetrix/gofra/pkg/gin-utils/middleware/monitor_middleware/prometheus"
+	statsdMiddleware "github.com/DarkMetrix/gofra/pkg/gin-utils/middleware/monitor_middleware/statsd"
+	recoveryMiddleware "github.com/DarkMetrix/gofra/pkg/gin-utils/middleware/recovery_middleware/recovery"
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+
+	config "{{.ConfigPackagePath}}"
+	// Code generated by gofra. DO NOT EDIT.
+	// @PROTO_STUB
+)
+
+// gatewayRegisterFunc registers the http handlers of a gRPC service, e.g.: fooProto.RegisterFooHandlerFromEndpoint
+type gatewayRegisterFunc func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+
+func getGatewayMiddlewares(conf *config.Config) []gin.HandlerFunc {
+	observability := conf.Observability
+
+	// recovery middleware is always the outermost one to catch panics from all the others
+	middlewares := []gin.HandlerFunc{recoveryMiddleware.GetMiddleware()}
+
+	if observability.Log.Enable {
+		middlewares = append(middlewares, seelogMiddleware.GetMiddleware())
+	}
+
+	if observability.Metrics.Enable {
+		switch observability.Metrics.Type {
+		case "statsd":
+			middlewares = append(middlewares, statsdMiddleware.GetMiddleware())
+		case "prometheus":
+			middlewares = append(middlewares, prometheusMiddleware.GetMiddleware())
+		}
+	}
+	return middlewares
+}
+
+func startHTTPGateway(conf *config.Config) (func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// register gateway handlers, requests are proxied to the gRPC server
+	registers := []gatewayRegisterFunc{
+		// Code generated by gofra. DO NOT EDIT.
+		// @GATEWAY_REGISTER_STUB
+	}
+
+	mux := runtime.NewServeMux()
+	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	for _, register := range registers {
+		if err := register(ctx, mux, conf.Server.Addr, dialOpts); err != nil {
+			cancel()
+			return nil, xerrors.Errorf("register gateway handler failed! error:%w", err)
+		}
+	}
+
+	// serve the generated OpenAPI specs, e.g.: /openapi/user/user.swagger.json, other requests go to the gateway
+	router := gin.New()
+	router.Use(getGatewayMiddlewares(conf)...)
+	router.Static("/openapi", conf.Server.OpenAPIPath)
+	router.NoRoute(func(c *gin.Context) {
+		// gin presets 404 before running NoRoute handlers, while the gateway doesn't write the header on success
+		c.Status(http.StatusOK)
+		mux.ServeHTTP(c.Writer, c.Request)
+	})
+
+	// run to serve
+	server := &http.Server{Addr: conf.Server.HTTPAddr, Handler: router}
+	go func() {
+		log.Infof("http gateway listen on %v", conf.Server.HTTPAddr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server.ListenAndServe failed! error:%v", err.Error())
+		}
+		log.Infof("http gateway quit!")
+	}()
+
+	return func() {
+		// stop http gateway gracefully before the gRPC server
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("server.Shutdown failed! error:%v", err)
+		}
+		cancel()
+		log.Infof("http gateway stopped gracefully!")
+	}, nil
+}
+`
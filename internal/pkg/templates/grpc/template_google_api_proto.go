@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-google-api-annotations-proto", GoogleAPIAnnotationsProtoTemplate)
+	templates.Register("template-google-api-http-proto", GoogleAPIHTTPProtoTemplate)
+}
+
+// GoogleAPIProtoInfo represents the google/api protobuf files information, they're imported by the protos
+// with google.api.http annotations, the go packages are provided by google.golang.org/genproto
+type GoogleAPIProtoInfo struct {
+	Opts *option.Options
+}
+
+// NewGoogleAPIProtoInfo returns a new GoogleAPIProtoInfo pointer
+func NewGoogleAPIProtoInfo(opts ...option.Option) *GoogleAPIProtoInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &GoogleAPIProtoInfo{Opts: newOpts}
+}
+
+// RenderAnnotationsFile render google/api/annotations.proto and output to file
+func (proto *GoogleAPIProtoInfo) RenderAnnotationsFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, proto.Opts.Override, proto.Opts.IgnoreExist,
+		"template-google-api-annotations-proto", GoogleAPIAnnotationsProtoTemplate, proto); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// RenderHTTPFile render google/api/http.proto and output to file
+func (proto *GoogleAPIProtoInfo) RenderHTTPFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, proto.Opts.Override, proto.Opts.IgnoreExist,
+		"template-google-api-http-proto", GoogleAPIHTTPProtoTemplate, proto); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// GoogleAPIAnnotationsProtoTemplate is google/api/annotations.proto of https://github.com/googleapis/googleapis
+var GoogleAPIAnnotationsProtoTemplate string = `// Copyright 2015 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+syntax = "proto3";
+
+package google.api;
+
+import "google/api/http.proto";
+import "google/protobuf/descriptor.proto";
+
+option go_package = "google.golang.org/genproto/googleapis/api/annotations;annotations";
+option java_multiple_files = true;
+option java_outer_classname = "AnnotationsProto";
+option java_package = "com.google.api";
+option objc_class_prefix = "GAPI";
+
+extend google.protobuf.MethodOptions {
+  // See ` + "`HttpRule`" + `.
+  HttpRule http = 72295728;
+}
+`
+
+// GoogleAPIHTTPProtoTemplate is google/api/http.proto of https://github.com/googleapis/googleapis with the comments shortened
+var GoogleAPIHTTPProtoTemplate string = `// Copyright 2015 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+syntax = "proto3";
+
+package google.api;
+
+option cc_enable_arenas = true;
+option go_package = "google.golang.org/genproto/googleapis/api/annotations;annotations";
+option java_multiple_files = true;
+option java_outer_classname = "HttpProto";
+option java_package = "com.google.api";
+option objc_class_prefix = "GAPI";
+
+// Defines the HTTP configuration for an API service.
+message Http {
+  repeated HttpRule rules = 1;
+  bool fully_decode_reserved_expansion = 2;
+}
+
+// Defines how an RPC method is mapped to HTTP/JSON REST API, see the original file for details.
+message HttpRule {
+  string selector = 1;
+  oneof pattern {
+    string get = 2;
+    string put = 3;
+    string post = 4;
+    string delete = 5;
+    string patch = 6;
+    CustomHttpPattern custom = 8;
+  }
+  string body = 7;
+  string response_body = 12;
+  repeated HttpRule additional_bindings = 11;
+}
+
+// A custom pattern is used for defining custom HTTP verb.
+message CustomHttpPattern {
+  string kind = 1;
+  string path = 2;
+}
+`
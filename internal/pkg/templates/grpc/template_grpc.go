@@ -1,6 +1,8 @@
 package grpc
 
 import (
+	"strings"
+
 	"github.com/DarkMetrix/gofra/internal/pkg/option"
 	"github.com/iancoleman/strcase"
 	"golang.org/x/xerrors"
@@ -8,6 +10,11 @@ import (
 	"github.com/DarkMetrix/gofra/internal/pkg/templates"
 )
 
+func init() {
+	templates.Register("template-service", ServiceTemplate)
+	templates.Register("template-rpc", RPCTemplate)
+}
+
 // ServiceInfo represents the gRPC service information
 type ServiceInfo struct {
 	Opts                *option.Options
@@ -48,6 +55,12 @@ type Implementation struct{
 }
 `
 
+// GoImport represents a go package imported by the generated code
+type GoImport struct {
+	Alias string
+	Path  string
+}
+
 // RPCInfo represents the gRPC RPC information
 type RPCInfo struct {
 	Opts                *option.Options
@@ -55,8 +68,13 @@ type RPCInfo struct {
 	ServiceName         string
 	ImportedPackageName string
 	RPCName             string
-	Request             string
-	Response            string
+	// qualified go types, e.g.: pb.AddUserRequest, emptypb.Empty
+	Request  string
+	Response string
+	// go packages of request & response types defined in other protos
+	Imports         []*GoImport
+	ClientStreaming bool
+	ServerStreaming bool
 }
 
 // NewRPCInfo returns a new RPCInfo pointer
@@ -69,11 +87,31 @@ func NewRPCInfo(opts ...option.Option) *RPCInfo {
 		ServiceName:         strcase.ToCamel(newOpts.ServiceName),
 		ImportedPackageName: newOpts.ImportedPackageName,
 		RPCName:             strcase.ToCamel(newOpts.RPCName),
-		Request:             strcase.ToCamel(newOpts.RequestName),
-		Response:            strcase.ToCamel(newOpts.ResponseName),
+		Request:             "pb." + strcase.ToCamel(newOpts.RequestName),
+		Response:            "pb." + strcase.ToCamel(newOpts.ResponseName),
+		ClientStreaming:     newOpts.ClientStreaming,
+		ServerStreaming:     newOpts.ServerStreaming,
 	}
 }
 
+// HasProtoType checks if request or response is defined in the go package of service proto, which is imported as pb
+func (rpc *RPCInfo) HasProtoType() bool {
+	return strings.HasPrefix(rpc.Request, "pb.") || strings.HasPrefix(rpc.Response, "pb.")
+}
+
+// HandlerImports returns the imports referenced by the handler, the request isn't referenced by the client &
+// bidirectional streaming handlers since it's received from stream
+func (rpc *RPCInfo) HandlerImports() []*GoImport {
+	imports := make([]*GoImport, 0, len(rpc.Imports))
+	for _, goImport := range rpc.Imports {
+		if strings.HasPrefix(rpc.Response, goImport.Alias+".") ||
+			(!rpc.ClientStreaming && strings.HasPrefix(rpc.Request, goImport.Alias+".")) {
+			imports = append(imports, goImport)
+		}
+	}
+	return imports
+}
+
 // RenderFile render template and output to file
 func (rpc *RPCInfo) RenderFile(outputPath string) error {
 	if err := templates.RenderToFile(outputPath, rpc.Opts.Override, rpc.Opts.IgnoreExist,
@@ -83,19 +121,81 @@ func (rpc *RPCInfo) RenderFile(outputPath string) error {
 	return nil
 }
 
+// RPCTemplate renders the handler stub of an RPC, the signature depends on the streaming mode:
+//
+//	unary:            (ctx, req) (resp, error)
+//	server streaming: (req, stream) error
+//	client streaming: (stream) error
+//	bidi streaming:   (stream) error
 var RPCTemplate string = `package {{.PackageName}}
 
 import (
+{{- if and (not .ClientStreaming) (not .ServerStreaming)}}
     "context"
-
+{{else if .ClientStreaming}}
+    "io"
+{{end}}
+{{- if or .ClientStreaming .ServerStreaming .HasProtoType}}
     pb "{{.ImportedPackageName}}"
+{{- end}}
+{{- range .HandlerImports}}
+    {{.Alias}} "{{.Path}}"
+{{- end}}
 )
+{{if and .ClientStreaming .ServerStreaming}}
+// {{.RPCName}} implements {{.ServiceName}} interface (bidirectional streaming)
+func (service Implementation) {{.RPCName}} (stream pb.{{.ServiceName}}_{{.RPCName}}Server) error {
+    for {
+        req, err := stream.Recv()
+        if err == io.EOF {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+
+        // TODO: implementation
+        _ = req
+        resp := &{{.Response}}{}
+        if err := stream.Send(resp); err != nil {
+            return err
+        }
+    }
+}
+{{- else if .ClientStreaming}}
+// {{.RPCName}} implements {{.ServiceName}} interface (client streaming)
+func (service Implementation) {{.RPCName}} (stream pb.{{.ServiceName}}_{{.RPCName}}Server) error {
+    for {
+        req, err := stream.Recv()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return err
+        }
+
+        // TODO: implementation
+        _ = req
+    }
+
+    resp := &{{.Response}}{}
+    return stream.SendAndClose(resp)
+}
+{{- else if .ServerStreaming}}
+// {{.RPCName}} implements {{.ServiceName}} interface (server streaming)
+func (service Implementation) {{.RPCName}} (req *{{.Request}}, stream pb.{{.ServiceName}}_{{.RPCName}}Server) error {
+    resp := &{{.Response}}{}
 
+    // TODO: implementation
+    return stream.Send(resp)
+}
+{{- else}}
 // {{.RPCName}} implements {{.ServiceName}} interface 
-func (service Implementation) {{.RPCName}} (ctx context.Context, req *pb.{{.Request}}) (*pb.{{.Response}}, error) {
-    resp := &pb.{{.Response}}{}
+func (service Implementation) {{.RPCName}} (ctx context.Context, req *{{.Request}}) (*{{.Response}}, error) {
+    resp := &{{.Response}}{}
 
     // TODO: implementation
     return resp, nil
 }
+{{- end}}
 `
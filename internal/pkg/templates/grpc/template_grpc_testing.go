@@ -0,0 +1,305 @@
+package grpc
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-service-test", ServiceTestTemplate)
+	templates.Register("template-rpc-test", RPCTestTemplate)
+}
+
+// RenderTestFile render the test helper template of service and output to file
+func (service *ServiceInfo) RenderTestFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, service.Opts.Override, service.Opts.IgnoreExist,
+		"template-service-test", ServiceTestTemplate, service); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// RenderTestFile render the test template of RPC and output to file
+func (rpc *RPCInfo) RenderTestFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, rpc.Opts.Override, rpc.Opts.IgnoreExist,
+		"template-rpc-test", RPCTestTemplate, rpc); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// ServiceTestTemplate renders the helper shared by the RPC tests of service, it serves Implementation
+// on an in-memory listener so that the tests don't need any network
+var ServiceTestTemplate string = `package {{.ServiceName}}
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "{{.ImportedPackageName}}"
+)
+
+// bufSize is the buffer size of the in-memory listener
+const bufSize = 1024 * 1024
+
+// newTestClient starts a server with Implementation registered on an in-memory listener and returns
+// the client connected to it, both are closed when the test finishes
+func newTestClient(t *testing.T) pb.{{.ServiceName}}Client {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	pb.Register{{.ServiceName}}Server(server, Implementation{})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("grpc.DialContext failed! error:%v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return pb.New{{.ServiceName}}Client(conn)
+}
+`
+
+// RPCTestTemplate renders the table-driven test of an RPC, the cases depend on the streaming mode:
+//
+//	unary:            req -> want
+//	server streaming: req -> []want
+//	client streaming: []req -> want
+//	bidi streaming:   []req -> []want
+//
+// the default case matches the handler stub rendered by RPCTemplate
+var RPCTestTemplate string = `package {{.PackageName}}
+
+import (
+	"context"
+{{- if .ServerStreaming}}
+	"io"
+{{- end}}
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+{{if or .HasProtoType .Imports}}
+{{end}}
+{{- if .HasProtoType}}
+	pb "{{.ImportedPackageName}}"
+{{- end}}
+{{- range .Imports}}
+	{{.Alias}} "{{.Path}}"
+{{- end}}
+)
+{{if and .ClientStreaming .ServerStreaming}}
+// Test{{.RPCName}} tests {{.ServiceName}}.{{.RPCName}} (bidirectional streaming)
+func Test{{.RPCName}}(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name     string
+		reqs     []*{{.Request}}
+		want     []*{{.Response}}
+		wantCode codes.Code
+	}{
+		// TODO: add test cases
+		{
+			name:     "empty request",
+			reqs:     []*{{.Request}}{ {} },
+			want:     []*{{.Response}}{ {} },
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.{{.RPCName}}(context.Background())
+			if err != nil {
+				t.Fatalf("{{.RPCName}}() failed! error:%v", err)
+			}
+			for _, req := range tt.reqs {
+				// the error of server is returned by Recv
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("stream.CloseSend() failed! error:%v", err)
+			}
+
+			var got []*{{.Response}}
+			for {
+				var resp *{{.Response}}
+				resp, err = stream.Recv()
+				if err != nil {
+					break
+				}
+				got = append(got, resp)
+			}
+			if err == io.EOF {
+				err = nil
+			}
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("{{.RPCName}}() code = %v, want %v, error:%v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("{{.RPCName}}() got %v responses, want %v", len(got), len(tt.want))
+			}
+			for index := range got {
+				if !proto.Equal(got[index], tt.want[index]) {
+					t.Errorf("{{.RPCName}}() response %v = %v, want %v", index, got[index], tt.want[index])
+				}
+			}
+		})
+	}
+}
+{{- else if .ClientStreaming}}
+// Test{{.RPCName}} tests {{.ServiceName}}.{{.RPCName}} (client streaming)
+func Test{{.RPCName}}(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name     string
+		reqs     []*{{.Request}}
+		want     *{{.Response}}
+		wantCode codes.Code
+	}{
+		// TODO: add test cases
+		{
+			name:     "empty request",
+			reqs:     []*{{.Request}}{ {} },
+			want:     &{{.Response}}{},
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.{{.RPCName}}(context.Background())
+			if err != nil {
+				t.Fatalf("{{.RPCName}}() failed! error:%v", err)
+			}
+			for _, req := range tt.reqs {
+				// the error of server is returned by CloseAndRecv
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+
+			resp, err := stream.CloseAndRecv()
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("{{.RPCName}}() code = %v, want %v, error:%v", code, tt.wantCode, err)
+			}
+			if err == nil && !proto.Equal(resp, tt.want) {
+				t.Errorf("{{.RPCName}}() = %v, want %v", resp, tt.want)
+			}
+		})
+	}
+}
+{{- else if .ServerStreaming}}
+// Test{{.RPCName}} tests {{.ServiceName}}.{{.RPCName}} (server streaming)
+func Test{{.RPCName}}(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name     string
+		req      *{{.Request}}
+		want     []*{{.Response}}
+		wantCode codes.Code
+	}{
+		// TODO: add test cases
+		{
+			name:     "empty request",
+			req:      &{{.Request}}{},
+			want:     []*{{.Response}}{ {} },
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.{{.RPCName}}(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("{{.RPCName}}() failed! error:%v", err)
+			}
+
+			var got []*{{.Response}}
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if code := status.Code(err); code != tt.wantCode {
+					t.Fatalf("{{.RPCName}}() code = %v, want %v, error:%v", code, tt.wantCode, err)
+				}
+				if err != nil {
+					return
+				}
+				got = append(got, resp)
+			}
+
+			if tt.wantCode != codes.OK {
+				t.Fatalf("{{.RPCName}}() code = %v, want %v", codes.OK, tt.wantCode)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("{{.RPCName}}() got %v responses, want %v", len(got), len(tt.want))
+			}
+			for index := range got {
+				if !proto.Equal(got[index], tt.want[index]) {
+					t.Errorf("{{.RPCName}}() response %v = %v, want %v", index, got[index], tt.want[index])
+				}
+			}
+		})
+	}
+}
+{{- else}}
+// Test{{.RPCName}} tests {{.ServiceName}}.{{.RPCName}}
+func Test{{.RPCName}}(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name     string
+		req      *{{.Request}}
+		want     *{{.Response}}
+		wantCode codes.Code
+	}{
+		// TODO: add test cases
+		{
+			name:     "empty request",
+			req:      &{{.Request}}{},
+			want:     &{{.Response}}{},
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.{{.RPCName}}(context.Background(), tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("{{.RPCName}}() code = %v, want %v, error:%v", code, tt.wantCode, err)
+			}
+			if err == nil && !proto.Equal(resp, tt.want) {
+				t.Errorf("{{.RPCName}}() = %v, want %v", resp, tt.want)
+			}
+		})
+	}
+}
+{{- end}}
+`
@@ -7,6 +7,10 @@ import (
 	"github.com/DarkMetrix/gofra/internal/pkg/templates"
 )
 
+func init() {
+	templates.Register("template-health-check-proto", HealthCheckProtoTemplate)
+}
+
 // HealthCheckProtoInfo represents the health check protobuf file information
 type HealthCheckProtoInfo struct {
 	Opts *option.Options
@@ -32,7 +36,9 @@ var HealthCheckProtoTemplate string = `syntax = "proto3";
 
 package common.health.check;
 
-option go_package = "./health_check";
+option go_package = "{{if and (eq .Opts.ProtoTool "buf") .Opts.GoModule -}}
+{{.Opts.GoModule}}/api/protobuf_spec/health_check;health_check
+{{- else}}./health_check{{end}}";
 
 // the health check service definition.
 service HealthCheck {
@@ -7,6 +7,10 @@ import (
 	"github.com/DarkMetrix/gofra/internal/pkg/templates"
 )
 
+func init() {
+	templates.Register("template-main", MainTemplate)
+}
+
 // MainInfo represents the main file information
 type MainInfo struct {
 	Opts              *option.Options
@@ -33,21 +37,37 @@ var MainTemplate string = `package main
 
 import (
 	"net"
+	"net/http"
+	_ "net/http/pprof"
 	"os"
 	"os/signal"
 
+	opentracingInterceptor "github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/opentracing_interceptor"
+	prometheusInterceptor "github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/prometheus_interceptor"
 	recoverInterceptor "github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/recover_interceptor"
+	seelogInterceptor "github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/seelog_interceptor"
+	statsdInterceptor "github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/statsd_interceptor"
+	seelogLogger "github.com/DarkMetrix/gofra/pkg/logger/seelog"
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	prometheusMonitor "github.com/DarkMetrix/gofra/pkg/monitor/prometheus"
+	statsdMonitor "github.com/DarkMetrix/gofra/pkg/monitor/statsd"
+	"github.com/DarkMetrix/gofra/pkg/performance"
+	jaegerTracing "github.com/DarkMetrix/gofra/pkg/tracing/jaeger"
+	zipkinTracing "github.com/DarkMetrix/gofra/pkg/tracing/zipkin"
 	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	log "github.com/sirupsen/logrus"
 	pflag "github.com/spf13/pflag"
 	viper "github.com/spf13/viper"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	config "{{.ConfigPackagePath}}"
-    // Code generated by gofra. DO NOT EDIT.
-    /*@PROTO_STUB*/
-    /*@HANDLER_STUB*/
+	// Code generated by gofra. DO NOT EDIT.
+	// @PROTO_STUB
+	// @HANDLER_STUB
 )
 
 func main() {
@@ -59,6 +79,17 @@ func main() {
 		log.Fatalf("initConfig failed! error:%+v", err)
 	}
 
+	// init log, metrics & tracing
+	closeObservabilityFunc, err := initObservability(conf)
+	if err != nil {
+		log.Fatalf("initObservability failed! error:%+v", err)
+	}
+	defer closeObservabilityFunc()
+
+	// start pprof & performance monitor
+	startPprof(conf)
+	startPerformance(conf)
+
 	// run to serve grpc
 	closeFunc, err := startGRPCServer(conf)
 	if err != nil {
@@ -90,14 +121,133 @@ func initConfig() (*config.Config, error) {
 	return conf, nil
 }
 
+func initObservability(conf *config.Config) (func(), error) {
+	observability := conf.Observability
+
+	// init log
+	if observability.Log.Enable {
+		if err := seelogLogger.Init(observability.Log.Path, conf.Server.Name); err != nil {
+			return nil, xerrors.Errorf("seelogLogger.Init failed! error:%w", err)
+		}
+	}
+
+	// init metrics
+	if observability.Metrics.Enable {
+		switch observability.Metrics.Type {
+		case "statsd":
+			if err := statsdMonitor.Init(observability.Metrics.Addr, conf.Server.Name); err != nil {
+				return nil, xerrors.Errorf("statsdMonitor.Init failed! error:%w", err)
+			}
+		case "prometheus":
+			if err := prometheusMonitor.Init(conf.Server.Name); err != nil {
+				return nil, xerrors.Errorf("prometheusMonitor.Init failed! error:%w", err)
+			}
+			go func() {
+				if err := prometheusMonitor.ListenAndServe(observability.Metrics.Addr); err != nil {
+					log.Errorf("prometheusMonitor.ListenAndServe failed! error:%v", err)
+				}
+			}()
+		default:
+			return nil, xerrors.Errorf("metrics type not supported! type:%v", observability.Metrics.Type)
+		}
+
+		if err := monitor.Use(observability.Metrics.Type); err != nil {
+			return nil, xerrors.Errorf("monitor.Use failed! error:%w", err)
+		}
+	}
+
+	// init tracing
+	closeFunc := func() {}
+	if observability.Tracing.Enable {
+		switch observability.Tracing.Type {
+		case "jaeger":
+			if err := jaegerTracing.Init(observability.Tracing.Addr, conf.Server.Name); err != nil {
+				return nil, xerrors.Errorf("jaegerTracing.Init failed! error:%w", err)
+			}
+			closeFunc = func() { _ = jaegerTracing.Close() }
+		case "zipkin":
+			if err := zipkinTracing.Init(observability.Tracing.Addr, conf.Server.Addr, conf.Server.Name); err != nil {
+				return nil, xerrors.Errorf("zipkinTracing.Init failed! error:%w", err)
+			}
+			closeFunc = func() { _ = zipkinTracing.Close() }
+		default:
+			return nil, xerrors.Errorf("tracing type not supported! type:%v", observability.Tracing.Type)
+		}
+	}
+	return closeFunc, nil
+}
+
+func startPprof(conf *config.Config) {
+	if !conf.Pprof.Enable {
+		return
+	}
+
+	go func() {
+		log.Infof("pprof listen on %v", conf.Pprof.Addr)
+		if err := http.ListenAndServe(conf.Pprof.Addr, nil); err != nil {
+			log.Errorf("pprof http.ListenAndServe failed! error:%v", err)
+		}
+	}()
+}
+
+func startPerformance(conf *config.Config) {
+	if !conf.Performance.Enable {
+		return
+	}
+
+	if conf.Performance.Type == "log" {
+		go performance.BeginMemoryPerformanceMonitorWithLog()
+		go performance.BeginGoroutinePerformanceMonitorWithLog()
+		return
+	}
+
+	performanceMonitor, ok := monitor.Get(conf.Performance.Type)
+	if !ok {
+		log.Errorf("performance type not supported! type:%v", conf.Performance.Type)
+		return
+	}
+	go performance.BeginMemoryPerformanceMonitor(performanceMonitor)
+	go performance.BeginGoroutinePerformanceMonitor(performanceMonitor)
+}
+
+func getServerInterceptors(conf *config.Config) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	observability := conf.Observability
+
+	// recover interceptor is always the outermost one to catch panics from all the others
+	unaryInterceptors := []grpc.UnaryServerInterceptor{recoverInterceptor.GetServerInterceptor()}
+	streamInterceptors := []grpc.StreamServerInterceptor{recoverInterceptor.GetStreamServerInterceptor()}
+
+	if observability.Tracing.Enable {
+		unaryInterceptors = append(unaryInterceptors, opentracingInterceptor.GetServerInterceptor())
+		streamInterceptors = append(streamInterceptors, opentracingInterceptor.GetStreamServerInterceptor())
+	}
+
+	if observability.Log.Enable {
+		unaryInterceptors = append(unaryInterceptors, seelogInterceptor.GetServerInterceptor())
+		streamInterceptors = append(streamInterceptors, seelogInterceptor.GetStreamServerInterceptor())
+	}
+
+	if observability.Metrics.Enable {
+		switch observability.Metrics.Type {
+		case "statsd":
+			unaryInterceptors = append(unaryInterceptors, statsdInterceptor.GetServerInterceptor())
+			streamInterceptors = append(streamInterceptors, statsdInterceptor.GetStreamServerInterceptor())
+		case "prometheus":
+			unaryInterceptors = append(unaryInterceptors, prometheusInterceptor.GetServerInterceptor())
+			streamInterceptors = append(streamInterceptors, prometheusInterceptor.GetStreamServerInterceptor())
+		}
+	}
+	return unaryInterceptors, streamInterceptors
+}
+
 func startGRPCServer(conf *config.Config) (func(), error) {
 	// set server interceptor
-	var serverInterceptors []grpc.UnaryServerInterceptor
-	serverInterceptors = append(serverInterceptors, recoverInterceptor.GetServerInterceptor())
+	unaryInterceptors, streamInterceptors := getServerInterceptors(conf)
 
 	serverOpts := make([]grpc.ServerOption, 0)
 	serverOpts = append(serverOpts,
-		grpc.UnaryInterceptor(grpcMiddleware.ChainUnaryServer(serverInterceptors...)))
+		grpc.UnaryInterceptor(grpcMiddleware.ChainUnaryServer(unaryInterceptors...)),
+		grpc.StreamInterceptor(grpcMiddleware.ChainStreamServer(streamInterceptors...)))
 
 	// listen
 	listen, err := net.Listen("tcp", conf.Server.Addr)
@@ -110,7 +260,19 @@ func startGRPCServer(conf *config.Config) (func(), error) {
 
 	// register services
 	// Code generated by gofra. DO NOT EDIT.
-	/*@REGISTER_STUB*/
+	// @REGISTER_STUB
+
+	// register standard health service (grpc.health.v1.Health) and mark every registered service as serving
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	for service := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	// register server reflection service, so that tools like grpcurl could list & call services
+	if conf.Server.Reflection {
+		reflection.Register(server)
+	}
 
 	// run to serve
 	go func() {
@@ -121,6 +283,9 @@ func startGRPCServer(conf *config.Config) (func(), error) {
 	}()
 
 	return func() {
+		// mark all services as not serving, so that health probes stop routing traffic before stopping
+		healthServer.Shutdown()
+
 		// stop grpc service gracefully
 		server.GracefulStop()
 		log.Infof("gRPC server stopped gracefully!")
@@ -0,0 +1,323 @@
+package grpc
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/option"
+	"github.com/DarkMetrix/gofra/internal/pkg/templates"
+)
+
+func init() {
+	templates.Register("template-mock", MockTemplate)
+	templates.Register("template-mock-main", MockMainTemplate)
+	templates.Register("template-mock-fixture", MockFixtureTemplate)
+}
+
+// MockInfo represents the mock server file information of a gRPC service
+type MockInfo struct {
+	Opts        *option.Options
+	PackageName string
+	ServiceName string
+	// e.g.: user.UserService
+	FullName string
+	// go package of the service proto, imported as pb
+	ImportedPackageName string
+	// package path of the mock server, imported by main.go
+	MockPackagePath string
+	// e.g.: pkg/mock/user_service/fixtures.yaml
+	FixturePath string
+	// go packages of request & response types defined in other protos, the same as the client SDK
+	Imports []*GoImport
+	RPCs    []*ClientRPC
+}
+
+// NewMockInfo returns a new MockInfo pointer
+func NewMockInfo(opts ...option.Option) *MockInfo {
+	// init options
+	newOpts := option.NewOptions(opts...)
+	return &MockInfo{
+		Opts:                newOpts,
+		PackageName:         newOpts.PackageName,
+		ServiceName:         newOpts.ServiceName,
+		ImportedPackageName: newOpts.ImportedPackageName,
+	}
+}
+
+// HasUnary checks if any RPC is unary, context is imported by the unary handlers
+func (mock *MockInfo) HasUnary() bool {
+	for _, rpc := range mock.RPCs {
+		if !rpc.ClientStreaming && !rpc.ServerStreaming {
+			return true
+		}
+	}
+	return false
+}
+
+// HasClientStreaming checks if any RPC is client streaming, io is imported to receive the requests
+func (mock *MockInfo) HasClientStreaming() bool {
+	for _, rpc := range mock.RPCs {
+		if rpc.ClientStreaming {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderFile render the mock server template and output to file
+func (mock *MockInfo) RenderFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, mock.Opts.Override, mock.Opts.IgnoreExist,
+		"template-mock", MockTemplate, mock); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// RenderMainFile render the mock server main template and output to file
+func (mock *MockInfo) RenderMainFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, mock.Opts.Override, mock.Opts.IgnoreExist,
+		"template-mock-main", MockMainTemplate, mock); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// RenderFixtureFile render the example fixture template and output to file
+func (mock *MockInfo) RenderFixtureFile(outputPath string) error {
+	if err := templates.RenderToFile(outputPath, mock.Opts.Override, mock.Opts.IgnoreExist,
+		"template-mock-fixture", MockFixtureTemplate, mock); err != nil {
+		return xerrors.Errorf("RenderToFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// MockTemplate renders the mock server of a gRPC service, the RPCs return the responses of fixtures and
+// record the requests through gofraMock.Mock
+var MockTemplate string = `// Code generated by gofra. DO NOT EDIT.
+
+// Package {{.PackageName}} is the mock server of {{.FullName}}, it returns the canned responses of fixtures
+// and records the requests, run it by cmd/main.go or start it in the tests of consumers
+package {{.PackageName}}
+
+import (
+{{- if .HasUnary}}
+	"context"
+{{- end}}
+{{- if .HasClientStreaming}}
+	"io"
+{{- end}}
+	"net"
+
+	gofraMock "github.com/DarkMetrix/gofra/pkg/grpc-utils/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	pb "{{.ImportedPackageName}}"
+{{- range .Imports}}
+	{{.Alias}} "{{.Path}}"
+{{- end}}
+)
+
+// Fixture, Fixtures & Call are described by gofraMock
+type (
+	Fixture  = gofraMock.Fixture
+	Fixtures = gofraMock.Fixtures
+	Call     = gofraMock.Call
+)
+
+// LoadFixtures loads the fixtures from YAML or JSON file
+var LoadFixtures = gofraMock.LoadFixtures
+
+// Server is the mock server of {{.FullName}}, the fixtures & calls are managed by the embedded Mock,
+// e.g.: server.SetFixtures("{{if .RPCs}}{{(index .RPCs 0).Name}}{{else}}RPC{{end}}", &Fixture{Code: "NotFound"})
+type Server struct {
+	*gofraMock.Mock
+	server *grpc.Server
+}
+
+// NewServer returns a new Server pointer with the fixtures
+func NewServer(fixtures Fixtures) *Server {
+	return &Server{Mock: gofraMock.New(fixtures)}
+}
+
+// NewServerFromFile returns a new Server pointer with the fixtures loaded from YAML or JSON file
+func NewServerFromFile(path string) (*Server, error) {
+	fixtures, err := LoadFixtures(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(fixtures), nil
+}
+
+// Register registers the mock service to the gRPC server, e.g.: a server listening on bufconn
+func (server *Server) Register(grpcServer *grpc.Server) {
+	pb.Register{{.ServiceName}}Server(grpcServer, &mockService{mock: server.Mock})
+}
+
+// Start serves on the address in background and returns the address listened, e.g.: "localhost:0" picks a free port
+func (server *Server) Start(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	server.server = grpc.NewServer()
+	server.Register(server.server)
+	go func() {
+		_ = server.server.Serve(listener)
+	}()
+	return listener.Addr().String(), nil
+}
+
+// Stop stops the server started by Start
+func (server *Server) Stop() {
+	if server.server != nil {
+		server.server.Stop()
+	}
+}
+
+// mockService implements {{.ServiceName}} interface
+type mockService struct {
+	pb.Unimplemented{{.ServiceName}}Server
+	mock *gofraMock.Mock
+}
+{{range .RPCs}}
+{{- if and .ClientStreaming .ServerStreaming}}
+// {{.Name}} sends the responses of fixture matched by each request (bidirectional streaming)
+func (service *mockService) {{.Name}}(stream pb.{{$.ServiceName}}_{{.Name}}Server) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		responses, err := service.mock.Handle("{{.Name}}", []proto.Message{req}, func() proto.Message {
+			return &{{.Response}}{}
+		})
+		for _, response := range responses {
+			if err := stream.Send(response.(*{{.Response}})); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+{{- else if .ClientStreaming}}
+// {{.Name}} returns the response of fixture matched by any of the requests (client streaming)
+func (service *mockService) {{.Name}}(stream pb.{{$.ServiceName}}_{{.Name}}Server) error {
+	requests := make([]proto.Message, 0)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		requests = append(requests, req)
+	}
+
+	responses, err := service.mock.Handle("{{.Name}}", requests, func() proto.Message {
+		return &{{.Response}}{}
+	})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(responses[0].(*{{.Response}}))
+}
+{{- else if .ServerStreaming}}
+// {{.Name}} sends the responses of fixture matched (server streaming)
+func (service *mockService) {{.Name}}(req *{{.Request}}, stream pb.{{$.ServiceName}}_{{.Name}}Server) error {
+	responses, err := service.mock.Handle("{{.Name}}", []proto.Message{req}, func() proto.Message {
+		return &{{.Response}}{}
+	})
+	for _, response := range responses {
+		if err := stream.Send(response.(*{{.Response}})); err != nil {
+			return err
+		}
+	}
+	return err
+}
+{{- else}}
+// {{.Name}} returns the response of fixture matched
+func (service *mockService) {{.Name}}(ctx context.Context, req *{{.Request}}) (*{{.Response}}, error) {
+	responses, err := service.mock.Handle("{{.Name}}", []proto.Message{req}, func() proto.Message {
+		return &{{.Response}}{}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0].(*{{.Response}}), nil
+}
+{{- end}}
+{{end}}`
+
+// MockMainTemplate renders the main of mock server, every call is logged and the server stops on signals
+var MockMainTemplate string = `// Code generated by gofra. DO NOT EDIT.
+
+// Command cmd runs the mock server of {{.FullName}}, e.g.:
+//
+//	go run {{.MockPackagePath}}/cmd --addr localhost:58888 --fixture {{.FixturePath}}
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	mock "{{.MockPackagePath}}"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:58888", "address to listen")
+	fixturePath := flag.String("fixture", "{{.FixturePath}}", "fixture file, YAML or JSON")
+	flag.Parse()
+
+	server, err := mock.NewServerFromFile(*fixturePath)
+	if err != nil {
+		log.Fatalf("mock.NewServerFromFile failed! error:%v", err)
+	}
+	server.OnCall(func(call *mock.Call) {
+		request, _ := protojson.Marshal(call.Request)
+		log.Printf("call received! method:%v, request:%s", call.Method, request)
+	})
+
+	listenAddr, err := server.Start(*addr)
+	if err != nil {
+		log.Fatalf("server.Start failed! error:%v", err)
+	}
+	log.Printf("mock server of {{.FullName}} is listening on %v", listenAddr)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	server.Stop()
+}
+`
+
+// MockFixtureTemplate renders the example fixtures returning empty responses for every RPC
+var MockFixtureTemplate string = `# Fixtures of {{.FullName}} keyed by RPC name, messages are written in the proto JSON mapping.
+# The first fixture whose match is satisfied by the request is used, otherwise the first one without match:
+#
+#   - match:              # optional, fields of request, e.g.: {name: foo}
+#     response: {}        # response of unary & client streaming RPCs
+#     responses: [{}]     # responses of server & bidirectional streaming RPCs, sent in order
+#     code: NotFound      # optional, status code returned after the responses
+#     message: not found  # optional, status message
+{{- range .RPCs}}
+{{.Name}}:
+{{- if .ServerStreaming}}
+  - responses:
+      - {}
+{{- else}}
+  - response: {}
+{{- end}}
+{{- end}}
+`
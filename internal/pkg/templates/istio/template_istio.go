@@ -6,6 +6,11 @@ import (
 	"golang.org/x/xerrors"
 )
 
+func init() {
+	templates.Register("template-istio-virtual-service", IstioVirtualServiceTemplate)
+	templates.Register("template-istio-destination-rule", IstioDestinationRuleTemplate)
+}
+
 // IstioVirtaulServiceInfo represents istio virtual-service information
 type IstioVirtaulServiceInfo struct {
 	Opts      *option.Options
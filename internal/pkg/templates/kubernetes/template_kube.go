@@ -6,6 +6,11 @@ import (
 	"golang.org/x/xerrors"
 )
 
+func init() {
+	templates.Register("template-k8s-deployment", KubeDeploymentTemplate)
+	templates.Register("template-k8s-service", KubeServiceTemplate)
+}
+
 // KubeDeploymentInfo represents kubernetes deployment information
 type KubeDeploymentInfo struct {
 	Opts          *option.Options
@@ -0,0 +1,118 @@
+package templates
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
+	"golang.org/x/xerrors"
+)
+
+// TemplateFileExt is the extension of template files in template directory, e.g.: template-main.tmpl
+const TemplateFileExt = ".tmpl"
+
+// registry of all the built-in templates, template packages register themselves on import
+var (
+	mtx         sync.RWMutex
+	builtins    = make(map[string]string)
+	templateDir string
+)
+
+// Register makes a built-in template available by name
+func Register(name, content string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	builtins[name] = content
+}
+
+// GetBuiltinNames returns the names of all built-in templates in sorted order
+func GetBuiltinNames() []string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetBuiltin returns the built-in template by name
+func GetBuiltin(name string) (string, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	content, ok := builtins[name]
+	return content, ok
+}
+
+// SetTemplateDir sets the directory from which templates are loaded before falling back to the built-in ones
+func SetTemplateDir(dir string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	templateDir = dir
+}
+
+// GetTemplateDir returns the template directory
+func GetTemplateDir() string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	return templateDir
+}
+
+// GetTemplateFilePath returns the template file path in dir, e.g.: dir/template-main.tmpl
+func GetTemplateFilePath(dir, name string) string {
+	return filepath.Join(dir, name+TemplateFileExt)
+}
+
+// loadTemplate returns the template with the same name in template directory if there is one, otherwise the built-in one
+func loadTemplate(name, builtin string) (string, error) {
+	dir := GetTemplateDir()
+	if dir == "" {
+		return builtin, nil
+	}
+
+	filePath := GetTemplateFilePath(dir, name)
+	isExist, err := fsutil.CheckPathExists(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+	}
+	if !isExist {
+		return builtin, nil
+	}
+
+	content, err := fsutil.ReadFile(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("fsutil.ReadFile failed! template file:%v, error:%w", filePath, err)
+	}
+	return string(content), nil
+}
+
+// ExportBuiltins writes all the built-in templates to dir as the starting point of customization
+func ExportBuiltins(dir string, override bool) error {
+	if err := fsutil.CreatePaths(false, dir); err != nil {
+		return xerrors.Errorf("fsutil.CreatePaths failed! error:%w", err)
+	}
+
+	for _, name := range GetBuiltinNames() {
+		filePath := GetTemplateFilePath(dir, name)
+		isExist, err := fsutil.CheckPathExists(filePath)
+		if err != nil {
+			return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
+		}
+		if isExist && !override {
+			return xerrors.Errorf("File already exists! this operation will override it! file path:%v", filePath)
+		}
+
+		content, _ := GetBuiltin(name)
+		if err := fsutil.WriteFile(filePath, []byte(content), 0666); err != nil {
+			return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+		}
+	}
+	return nil
+}
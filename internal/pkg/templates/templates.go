@@ -1,10 +1,14 @@
 package templates
 
 import (
-	"os"
+	"bytes"
+	"go/format"
+	htmlTemplate "html/template"
+	"io"
+	"path/filepath"
 	"text/template"
 
-	"github.com/DarkMetrix/gofra/pkg/utils"
+	"github.com/DarkMetrix/gofra/internal/pkg/utils/fsutil"
 	"golang.org/x/xerrors"
 )
 
@@ -13,13 +17,18 @@ type FileTemplate interface {
 	RenderFile(filePath string) error
 }
 
-// RenderToFile render templates to file
+// templateExecutor is implemented by both text/template and html/template
+type templateExecutor interface {
+	Execute(writer io.Writer, data interface{}) error
+}
+
+// RenderToFile render templates to file, .html files are rendered by html/template so that the content is escaped
 func RenderToFile(filePath string, override, ignoreExist bool,
 	templateName, templateContent string, content interface{}) error {
 	// check file is exist or not
-	isExist, err := utils.CheckPathExists(filePath)
+	isExist, err := fsutil.CheckPathExists(filePath)
 	if err != nil {
-		return xerrors.Errorf("utils.CheckPathExists failed! error:%w", err)
+		return xerrors.Errorf("fsutil.CheckPathExists failed! error:%w", err)
 	}
 
 	if isExist && !override {
@@ -29,26 +38,54 @@ func RenderToFile(filePath string, override, ignoreExist bool,
 		return xerrors.Errorf("File already exists! this operation will override it! file path:%v", filePath)
 	}
 
+	// render before touching the existing file so that it's kept if the template fails
+	rendered, err := Render(filePath, templateName, templateContent, content)
+	if err != nil {
+		return xerrors.Errorf("Render failed! error:%w", err)
+	}
+
 	if isExist && override {
-		if err := os.RemoveAll(filePath); err != nil {
-			return xerrors.Errorf("os.RemoveAll failed! file path:%v, error:%w", filePath, err)
+		if err := fsutil.RemoveAll(filePath); err != nil {
+			return xerrors.Errorf("fsutil.RemoveAll failed! file path:%v, error:%w", filePath, err)
 		}
 	}
 
-	// parse templates
-	templateToRender, err := template.New(templateName).Parse(templateContent)
+	if err := fsutil.WriteFile(filePath, rendered, 0666); err != nil {
+		return xerrors.Errorf("fsutil.WriteFile failed! error:%w", err)
+	}
+	return nil
+}
+
+// Render renders the template to bytes, filePath decides the escaping of .html & the formatting of .go
+func Render(filePath, templateName, templateContent string, content interface{}) ([]byte, error) {
+	// parse templates, the one in template directory takes precedence over the built-in one
+	templateContent, err := loadTemplate(templateName, templateContent)
+	if err != nil {
+		return nil, xerrors.Errorf("loadTemplate failed! template:%v, error:%w", templateName, err)
+	}
+	var templateToRender templateExecutor
+	if filepath.Ext(filePath) == ".html" {
+		templateToRender, err = htmlTemplate.New(templateName).Parse(templateContent)
+	} else {
+		templateToRender, err = template.New(templateName).Parse(templateContent)
+	}
 	if err != nil {
-		return xerrors.Errorf("Unable to parse templates! error:%w", err)
+		return nil, xerrors.Errorf("Unable to parse templates! template:%v, error:%w", templateName, err)
 	}
 
 	// execute templates
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0666)
-	if err != nil {
-		return xerrors.Errorf("os.OpenFile failed! error:%w", err)
+	buffer := &bytes.Buffer{}
+	if err := templateToRender.Execute(buffer, content); err != nil {
+		return nil, xerrors.Errorf("Unable to execute templates! error:%w", err)
 	}
 
-	if err := templateToRender.Execute(file, content); err != nil {
-		return xerrors.Errorf("Unable to execute templates! error:%w", err)
+	// keep generated Go source gofmt-clean
+	rendered := buffer.Bytes()
+	if filepath.Ext(filePath) == ".go" {
+		if rendered, err = format.Source(rendered); err != nil {
+			return nil, xerrors.Errorf("format.Source failed! file path:%v, error:%w", filePath, err)
+		}
 	}
-	return nil
+
+	return rendered, nil
 }
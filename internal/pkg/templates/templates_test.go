@@ -0,0 +1,51 @@
+package templates
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderToFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+		want     string
+	}{
+		{"rendered", "package main\nvar name = \"{{.}}\"\n", false, "package main\n\nvar name = \"gofra\"\n"},
+		{"parse error", "package main\nvar name = \"{{.\"\n", true, "package old\n"},
+		{"execute error", "package main\nvar name = \"{{.Name}}\"\n", true, "package old\n"},
+		{"format error", "package main\nvar name = {{.}}(\n", true, "package old\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			filePath := filepath.Join(root, "main.go")
+			if err := ioutil.WriteFile(filePath, []byte("package old\n"), 0644); err != nil {
+				t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+			}
+
+			err = RenderToFile(filePath, true, false, "template-main.tmpl", tt.template, "gofra")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderToFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// the existing file must be kept if rendering fails
+			content, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile failed! error:%v", err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("RenderToFile() content = %q, want %q", content, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package fsutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around every change
+const diffContext = 3
+
+// diffOp represents one line of diff, op is one of ' ', '-', '+'
+type diffOp struct {
+	op   byte
+	line string
+}
+
+// UnifiedDiff returns the unified diff between old and new content, it's empty if nothing changed
+func UnifiedDiff(oldName, newName string, old, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+
+	if bytes.IndexByte(old, 0) >= 0 || bytes.IndexByte(new, 0) >= 0 {
+		return fmt.Sprintf("Binary files %v and %v differ\n", oldName, newName)
+	}
+
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "--- %v\n+++ %v\n", oldName, newName)
+
+	// group changes into hunks, changes closer than 2*diffContext lines share the same hunk
+	changes := make([]int, 0)
+	for index, op := range ops {
+		if op.op != ' ' {
+			changes = append(changes, index)
+		}
+	}
+	for first := 0; first < len(changes); {
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*diffContext {
+			last++
+		}
+
+		begin, end := changes[first]-diffContext, changes[last]+1+diffContext
+		if begin < 0 {
+			begin = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		writeHunk(buffer, ops, begin, end)
+		first = last + 1
+	}
+	return buffer.String()
+}
+
+// writeHunk writes ops[begin:end] as a hunk
+func writeHunk(buffer *bytes.Buffer, ops []diffOp, begin, end int) {
+	oldLine, newLine := 1, 1
+	for _, op := range ops[:begin] {
+		if op.op != '+' {
+			oldLine++
+		}
+		if op.op != '-' {
+			newLine++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, op := range ops[begin:end] {
+		if op.op != '+' {
+			oldCount++
+		}
+		if op.op != '-' {
+			newCount++
+		}
+	}
+	if oldCount == 0 {
+		oldLine--
+	}
+	if newCount == 0 {
+		newLine--
+	}
+
+	fmt.Fprintf(buffer, "@@ -%v,%v +%v,%v @@\n", oldLine, oldCount, newLine, newCount)
+	for _, op := range ops[begin:end] {
+		buffer.WriteByte(op.op)
+		buffer.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			buffer.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// splitLines splits content into lines which keep the line endings
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the line operations transforming old to new based on the longest common subsequence
+func diffLines(old, new []string) []diffOp {
+	// lcs[i][j] is the length of LCS of old[i:] and new[j:]
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(old)+len(new))
+	i, j := 0, 0
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			ops = append(ops, diffOp{' ', old[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', old[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', new[j]})
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		ops = append(ops, diffOp{'-', old[i]})
+	}
+	for ; j < len(new); j++ {
+		ops = append(ops, diffOp{'+', new[j]})
+	}
+	return ops
+}
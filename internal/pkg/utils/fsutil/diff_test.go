@@ -0,0 +1,87 @@
+package fsutil
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// lines returns the numbers from begin to end one per line, the ones in replaces are replaced
+func lines(begin, end int, replaces map[string]string) string {
+	builder := &strings.Builder{}
+	for number := begin; number <= end; number++ {
+		line := strconv.Itoa(number)
+		if replace, ok := replaces[line]; ok {
+			line = replace
+		}
+		builder.WriteString(line + "\n")
+	}
+	return builder.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "nothing changed",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "created",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "deleted",
+			old:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "lines removed & added",
+			old:  "a\nb\nc\n",
+			new:  "a\nc\nd\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n c\n+d\n",
+		},
+		{
+			name: "distant changes in separate hunks",
+			old:  lines(1, 20, nil),
+			new:  lines(1, 20, map[string]string{"2": "two", "18": "eighteen"}),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+				"@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+eighteen\n 19\n 20\n",
+		},
+		{
+			name: "close changes in one hunk",
+			old:  lines(1, 12, nil),
+			new:  lines(1, 12, map[string]string{"3": "three", "9": "nine"}),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,12 +1,12 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n 7\n 8\n-9\n+nine\n 10\n 11\n 12\n",
+		},
+		{
+			name: "no newline at end of file",
+			old:  "a\n",
+			new:  "a",
+			want: "--- old\n+++ new\n@@ -1,1 +1,1 @@\n-a\n+a\n\\ No newline at end of file\n",
+		},
+		{
+			name: "binary",
+			old:  "a\x00",
+			new:  "b\x00",
+			want: "Binary files old and new differ\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", []byte(tt.old), []byte(tt.new)); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
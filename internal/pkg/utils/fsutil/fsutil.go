@@ -0,0 +1,353 @@
+package fsutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DarkMetrix/gofra/pkg/utils"
+)
+
+// All the file operations of generators go through this package, when dry-run is enabled
+// the changes are kept in memory so that later steps see what earlier steps wrote, and nothing touches the tree.
+
+// fileChange represents the content of file after the changes in dry-run mode
+type fileChange struct {
+	data    []byte
+	deleted bool
+}
+
+var (
+	mtx    sync.Mutex
+	dryRun bool
+
+	// path -> file content in memory
+	files = make(map[string]*fileChange)
+	// directories created in memory
+	dirs = make(map[string]bool)
+	// directories removed in memory
+	removedDirs = make(map[string]bool)
+)
+
+// SetDryRun enables or disables the dry-run mode
+func SetDryRun(enable bool) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	dryRun = enable
+}
+
+// IsDryRun returns true if the dry-run mode is enabled
+func IsDryRun() bool {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	return dryRun
+}
+
+// CheckPathExists checks if the path is exist or not
+func CheckPathExists(path string) (bool, error) {
+	if !IsDryRun() {
+		return utils.CheckPathExists(path)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	path = filepath.Clean(path)
+	if change, ok := files[path]; ok {
+		return !change.deleted, nil
+	}
+	if dirs[path] {
+		return true, nil
+	}
+	for filePath, change := range files {
+		if !change.deleted && isUnder(filePath, path) {
+			return true, nil
+		}
+	}
+	if isRemoved(path) {
+		return false, nil
+	}
+	return utils.CheckPathExists(path)
+}
+
+// CreatePath creates path directory and if override is specified the directory will be removed first
+func CreatePath(path string, override bool) error {
+	if !IsDryRun() {
+		return utils.CreatePath(path, override)
+	}
+
+	if override {
+		if err := RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	dirs[filepath.Clean(path)] = true
+	return nil
+}
+
+// CreatePaths creates a batch of directories
+func CreatePaths(override bool, paths ...string) error {
+	for _, path := range paths {
+		if err := CreatePath(path, override); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile reads the file, the content written in dry-run mode is returned if there is any
+func ReadFile(path string) ([]byte, error) {
+	if !IsDryRun() {
+		return ioutil.ReadFile(path)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	cleanPath := filepath.Clean(path)
+	if change, ok := files[cleanPath]; ok {
+		if change.deleted {
+			return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+		}
+		return append([]byte{}, change.data...), nil
+	}
+	if isRemoved(cleanPath) {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return ioutil.ReadFile(path)
+}
+
+// Open opens the file for reading, it's used as the file accessor of proto parser
+func Open(path string) (io.ReadCloser, error) {
+	data, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// WriteFile writes data to file
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if !IsDryRun() {
+		return ioutil.WriteFile(path, data, perm)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	files[filepath.Clean(path)] = &fileChange{data: append([]byte{}, data...)}
+	return nil
+}
+
+// CopyFile copies file from src to dest
+func CopyFile(src, dest string) error {
+	data, err := ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return WriteFile(dest, data, os.ModePerm)
+}
+
+// Remove removes the file
+func Remove(path string) error {
+	if !IsDryRun() {
+		return os.Remove(path)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	files[filepath.Clean(path)] = &fileChange{deleted: true}
+	return nil
+}
+
+// RemoveAll removes path and any children it contains
+func RemoveAll(path string) error {
+	if !IsDryRun() {
+		return os.RemoveAll(path)
+	}
+
+	// files on disk
+	diskFiles := make([]string, 0)
+	if !isRemovedLocked(path) {
+		err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.IsDir() {
+				diskFiles = append(diskFiles, filepath.Clean(filePath))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	path = filepath.Clean(path)
+	for _, filePath := range diskFiles {
+		if _, ok := files[filePath]; !ok {
+			files[filePath] = &fileChange{deleted: true}
+		}
+	}
+	for filePath, change := range files {
+		if isUnder(filePath, path) {
+			change.deleted = true
+			change.data = nil
+		}
+	}
+	for dir := range dirs {
+		if isUnder(dir, path) {
+			delete(dirs, dir)
+		}
+	}
+	removedDirs[path] = true
+	return nil
+}
+
+// ListFiles returns all the files under dir recursively in sorted order,
+// the files written or removed in dry-run mode are taken into account
+func ListFiles(dir string) ([]string, error) {
+	// files on disk
+	filePaths := make(map[string]bool)
+	if !isRemovedLocked(dir) {
+		err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.IsDir() {
+				filePaths[filepath.Clean(filePath)] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if IsDryRun() {
+		mtx.Lock()
+		dir = filepath.Clean(dir)
+		for filePath := range filePaths {
+			if isRemoved(filePath) {
+				delete(filePaths, filePath)
+			}
+		}
+		for filePath, change := range files {
+			if isUnder(filePath, dir) {
+				filePaths[filePath] = !change.deleted
+			}
+		}
+		mtx.Unlock()
+	}
+
+	sortedPaths := make([]string, 0, len(filePaths))
+	for filePath, exist := range filePaths {
+		if exist {
+			sortedPaths = append(sortedPaths, filePath)
+		}
+	}
+	sort.Strings(sortedPaths)
+	return sortedPaths, nil
+}
+
+// isRemovedLocked checks if path is removed in dry-run mode
+func isRemovedLocked(path string) bool {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	return isRemoved(filepath.Clean(path))
+}
+
+// isRemoved checks if path is under any removed directory which is not created again, mtx must be held
+func isRemoved(path string) bool {
+	for dir := range removedDirs {
+		if isUnder(path, dir) {
+			for created := range dirs {
+				if isUnder(path, created) && isUnder(created, dir) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// isUnder checks if path equals to dir or is inside dir
+func isUnder(path, dir string) bool {
+	if dir == "." {
+		return !filepath.IsAbs(path) && !strings.HasPrefix(path, "..")
+	}
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// PrintDryRunReport prints the files would be created, overwritten or deleted and the unified diff of every file
+func PrintDryRunReport(writer io.Writer) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	type report struct {
+		action   string
+		path     string
+		old, new []byte
+	}
+	reports := make([]*report, 0, len(paths))
+	for _, path := range paths {
+		change := files[path]
+		old, err := ioutil.ReadFile(path)
+		existed := err == nil
+
+		switch {
+		case change.deleted && existed:
+			reports = append(reports, &report{action: "delete", path: path, old: old})
+		case change.deleted:
+		case !existed:
+			reports = append(reports, &report{action: "create", path: path, new: change.data})
+		case !bytes.Equal(old, change.data):
+			reports = append(reports, &report{action: "overwrite", path: path, old: old, new: change.data})
+		}
+	}
+
+	fmt.Fprintf(writer, "dry-run: %v file(s) would be changed, nothing is written\n", len(reports))
+	for _, r := range reports {
+		fmt.Fprintf(writer, "  %-9v %v\n", r.action, r.path)
+	}
+	for _, r := range reports {
+		oldName, newName := "a/"+filepath.ToSlash(r.path), "b/"+filepath.ToSlash(r.path)
+		switch r.action {
+		case "create":
+			oldName = "/dev/null"
+		case "delete":
+			newName = "/dev/null"
+		}
+		fmt.Fprint(writer, UnifiedDiff(oldName, newName, r.old, r.new))
+	}
+}
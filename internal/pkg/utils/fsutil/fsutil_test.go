@@ -0,0 +1,134 @@
+package fsutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	root, err := ioutil.TempDir("", "gofra")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed! error:%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for name, content := range map[string]string{
+		"overwrite.go": "package a\n",
+		"unchanged.go": "package a\n",
+		"delete.go":    "package a\n",
+		"dir/file.go":  "package dir\n",
+	} {
+		filePath := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed! error:%v", err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+		}
+	}
+
+	SetDryRun(true)
+	defer func() {
+		SetDryRun(false)
+		files, dirs, removedDirs = make(map[string]*fileChange), make(map[string]bool), make(map[string]bool)
+	}()
+
+	tests := []struct {
+		name string
+		do   func() error
+	}{
+		{name: "create", do: func() error {
+			if err := CreatePath(filepath.Join(root, "new"), false); err != nil {
+				return err
+			}
+			return WriteFile(filepath.Join(root, "new", "create.go"), []byte("package new\n"), 0644)
+		}},
+		{name: "overwrite", do: func() error {
+			return WriteFile(filepath.Join(root, "overwrite.go"), []byte("package b\n"), 0644)
+		}},
+		{name: "unchanged", do: func() error {
+			return WriteFile(filepath.Join(root, "unchanged.go"), []byte("package a\n"), 0644)
+		}},
+		{name: "delete", do: func() error { return Remove(filepath.Join(root, "delete.go")) }},
+		{name: "delete directory", do: func() error { return RemoveAll(filepath.Join(root, "dir")) }},
+	}
+	for _, tt := range tests {
+		if err := tt.do(); err != nil {
+			t.Fatalf("%v failed! error:%v", tt.name, err)
+		}
+	}
+
+	// later steps see what earlier steps wrote
+	checks := []struct {
+		path        string
+		wantExist   bool
+		wantContent string
+	}{
+		{path: "new/create.go", wantExist: true, wantContent: "package new\n"},
+		{path: "overwrite.go", wantExist: true, wantContent: "package b\n"},
+		{path: "delete.go"},
+		{path: "dir"},
+		{path: "dir/file.go"},
+	}
+	for _, check := range checks {
+		isExist, err := CheckPathExists(filepath.Join(root, check.path))
+		if err != nil {
+			t.Fatalf("CheckPathExists() failed! error:%v", err)
+		}
+		if isExist != check.wantExist {
+			t.Errorf("CheckPathExists(%v) = %v, want %v", check.path, isExist, check.wantExist)
+		}
+		if !check.wantExist {
+			continue
+		}
+		content, err := ReadFile(filepath.Join(root, check.path))
+		if err != nil {
+			t.Fatalf("ReadFile() failed! error:%v", err)
+		}
+		if string(content) != check.wantContent {
+			t.Errorf("ReadFile(%v) = %q, want %q", check.path, content, check.wantContent)
+		}
+	}
+
+	// the files written & removed in memory are listed instead of the ones on disk
+	filePaths, err := ListFiles(root)
+	if err != nil {
+		t.Fatalf("ListFiles() failed! error:%v", err)
+	}
+	wantPaths := []string{filepath.Join(root, "new", "create.go"), filepath.Join(root, "overwrite.go"),
+		filepath.Join(root, "unchanged.go")}
+	if strings.Join(filePaths, ",") != strings.Join(wantPaths, ",") {
+		t.Errorf("ListFiles() = %v, want %v", filePaths, wantPaths)
+	}
+
+	// nothing touches the tree
+	if _, err := os.Stat(filepath.Join(root, "new")); !os.IsNotExist(err) {
+		t.Errorf("dry-run created %v", filepath.Join(root, "new"))
+	}
+	if content, _ := ioutil.ReadFile(filepath.Join(root, "overwrite.go")); string(content) != "package a\n" {
+		t.Errorf("dry-run overwrote overwrite.go with %q", content)
+	}
+
+	buffer := &bytes.Buffer{}
+	PrintDryRunReport(buffer)
+	path := func(name string) string { return filepath.ToSlash(filepath.Join(root, name)) }
+	want := "dry-run: 4 file(s) would be changed, nothing is written\n" +
+		"  delete    " + filepath.Join(root, "delete.go") + "\n" +
+		"  delete    " + filepath.Join(root, "dir", "file.go") + "\n" +
+		"  create    " + filepath.Join(root, "new", "create.go") + "\n" +
+		"  overwrite " + filepath.Join(root, "overwrite.go") + "\n" +
+		"--- a/" + path("delete.go") + "\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-package a\n" +
+		"--- a/" + path("dir/file.go") + "\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-package dir\n" +
+		"--- /dev/null\n+++ b/" + path("new/create.go") + "\n@@ -0,0 +1,1 @@\n+package new\n" +
+		"--- a/" + path("overwrite.go") + "\n+++ b/" + path("overwrite.go") + "\n@@ -1,1 +1,1 @@\n-package a\n+package b\n"
+	if got := buffer.String(); got != want {
+		t.Errorf("PrintDryRunReport() =\n%v\nwant\n%v", got, want)
+	}
+	if strings.Contains(buffer.String(), "unchanged.go") {
+		t.Errorf("PrintDryRunReport() reported unchanged.go")
+	}
+}
@@ -0,0 +1,5 @@
+package version
+
+// Version is the version of gofra generator, it's set at build time, e.g.:
+// go build -ldflags "-X github.com/DarkMetrix/gofra/internal/pkg/version.Version=v1.0.0" ./cmd
+var Version = "dev"
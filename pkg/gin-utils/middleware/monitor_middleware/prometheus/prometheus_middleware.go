@@ -0,0 +1,51 @@
+package prometheus
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	"github.com/DarkMetrix/gofra/pkg/monitor/prometheus"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMiddleware returns the middleware reporting to prometheus
+func GetMiddleware() gin.HandlerFunc {
+	return GetMiddlewareWithMonitor(prometheus.NewMonitor())
+}
+
+// metrics are reported as 'http_server_*' with 'path', 'method' and 'code' tags,
+// to m or to the default monitor chosen by monitor.Use if m is nil
+func GetMiddlewareWithMonitor(m monitor.Monitor) gin.HandlerFunc {
+	m = monitor.OrDefault(m)
+
+	return func(ctx *gin.Context) {
+		// before request
+		start := time.Now()
+
+		// use the matched route instead of the request URI to keep the label cardinality bounded
+		path := ctx.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+
+		// switch to another middleware handler
+		ctx.Next()
+
+		// after request
+		tags := monitor.Tags{
+			"path":   path,
+			"method": ctx.Request.Method,
+			"code":   strconv.Itoa(ctx.Writer.Status()),
+		}
+
+		m.Increment("http_server_requests_total", tags)
+		m.Timing("http_server_handling_seconds", time.Since(start), tags)
+	}
+}
+
+// GetMetricsHandler returns the handler to serve prometheus metrics, e.g.: engine.GET("/metrics", GetMetricsHandler())
+func GetMetricsHandler() gin.HandlerFunc {
+	return gin.WrapH(prometheus.Handler())
+}
@@ -1,49 +1,44 @@
 package statsd
 
 import (
-	"fmt"
+	"time"
 
-	monitor "github.com/DarkMetrix/gofra/pkg/monitor/statsd"
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	"github.com/DarkMetrix/gofra/pkg/monitor/statsd"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GetMiddleware() gin.HandlerFunc{
-	return func (ctx *gin.Context) {
-		client := monitor.GetStatsd()
-
-		if client != nil {
-			// before request
-			defer monitor.GetStatsd().NewTiming().Send(fmt.Sprintf("%v|Time,type=Server.Time", ctx.Request.RequestURI))
-
-			monitor.Increment(fmt.Sprintf("%v,type=Server.Total", ctx.Request.RequestURI))
+// GetMiddleware returns the middleware reporting to statsd
+func GetMiddleware() gin.HandlerFunc {
+	return GetMiddlewareWithMonitor(statsd.NewMonitor())
+}
 
-			// switch to another middleware handler
-			ctx.Next()
+// metrics are reported with the request URI as the name and the result as the 'type' tag, e.g.: '/foo,type=Server.Total',
+// to m or to the default monitor chosen by monitor.Use if m is nil
+func GetMiddlewareWithMonitor(m monitor.Monitor) gin.HandlerFunc {
+	m = monitor.OrDefault(m)
 
-			// after request
-			status := ctx.Writer.Status()
+	return func(ctx *gin.Context) {
+		// before request
+		uri := ctx.Request.RequestURI
+		start := time.Now()
+		defer func() {
+			m.Timing(uri+"|Time", time.Since(start), monitor.Tags{"type": "Server.Time"})
+		}()
 
-			if status != 200 {
-				monitor.Increment(fmt.Sprintf("%v,type=Server.Fail", ctx.Request.RequestURI))
-			} else {
-				monitor.Increment(fmt.Sprintf("%v,type=Server.Success", ctx.Request.RequestURI))
-			}
-		} else {
-			monitor.Increment(fmt.Sprintf("%v,type=Server.Total", ctx.Request.RequestURI))
+		m.Increment(uri, monitor.Tags{"type": "Server.Total"})
 
-			// switch to another middleware handler
-			ctx.Next()
+		// switch to another middleware handler
+		ctx.Next()
 
-			// after request
-			status := ctx.Writer.Status()
+		// after request
+		status := ctx.Writer.Status()
 
-			if status != 200 {
-				monitor.Increment(fmt.Sprintf("%v,type=Server.Fail", ctx.Request.RequestURI))
-			} else {
-				monitor.Increment(fmt.Sprintf("%v,type=Server.Success", ctx.Request.RequestURI))
-			}
+		if status != 200 {
+			m.Increment(uri, monitor.Tags{"type": "Server.Fail"})
+		} else {
+			m.Increment(uri, monitor.Tags{"type": "Server.Success"})
 		}
 	}
 }
-
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"path"
+	"time"
+
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/opentracing_interceptor"
+	"github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/seelog_interceptor"
+	"github.com/DarkMetrix/gofra/pkg/grpc-utils/interceptor/statsd_interceptor"
+	"github.com/DarkMetrix/gofra/pkg/grpc-utils/pool"
+)
+
+// Options represents the options of the calls made by Conn
+type Options struct {
+	// default timeout of each attempt of unary RPC, no timeout if it's 0
+	Timeout time.Duration
+	// timeouts of specific RPCs by RPC name, e.g.: AddUser
+	RPCTimeouts map[string]time.Duration
+
+	// retry times of unary RPC after the first attempt fails with one of RetryCodes
+	Retries      int
+	RetryBackoff time.Duration
+	RetryCodes   []codes.Code
+
+	// interceptors run around each call, the first one is the outermost
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+}
+
+// Option sets the options of Conn
+type Option func(*Options)
+
+// NewOptions returns the options with defaults: 3s timeout, no retry (codes.Unavailable is retried once
+// the retries are set) and the opentracing, seelog & statsd interceptors
+func NewOptions(opts ...Option) *Options {
+	options := &Options{
+		Timeout:      3 * time.Second,
+		RPCTimeouts:  make(map[string]time.Duration),
+		RetryBackoff: 100 * time.Millisecond,
+		RetryCodes:   []codes.Code{codes.Unavailable},
+		UnaryInterceptors: []grpc.UnaryClientInterceptor{
+			opentracing_interceptor.GetClientInterceptor(),
+			seelog_interceptor.GetClientInterceptor(),
+			statsd_interceptor.GetClientInterceptor(),
+		},
+		StreamInterceptors: []grpc.StreamClientInterceptor{
+			opentracing_interceptor.GetStreamClientInterceptor(),
+			seelog_interceptor.GetStreamClientInterceptor(),
+			statsd_interceptor.GetStreamClientInterceptor(),
+		},
+	}
+	for _, optionFunc := range opts {
+		optionFunc(options)
+	}
+	return options
+}
+
+// WithTimeout sets the default timeout of unary RPCs
+func WithTimeout(timeout time.Duration) Option {
+	return func(options *Options) {
+		options.Timeout = timeout
+	}
+}
+
+// WithRPCTimeout sets the timeout of a specific unary RPC, e.g.: WithRPCTimeout("AddUser", time.Second)
+func WithRPCTimeout(rpc string, timeout time.Duration) Option {
+	return func(options *Options) {
+		options.RPCTimeouts[rpc] = timeout
+	}
+}
+
+// WithRetries sets the retry times of unary RPCs
+func WithRetries(retries int) Option {
+	return func(options *Options) {
+		options.Retries = retries
+	}
+}
+
+// WithRetryBackoff sets the wait before each retry, it grows linearly with the attempts
+func WithRetryBackoff(backoff time.Duration) Option {
+	return func(options *Options) {
+		options.RetryBackoff = backoff
+	}
+}
+
+// WithRetryCodes sets the status codes to retry on
+func WithRetryCodes(retryCodes ...codes.Code) Option {
+	return func(options *Options) {
+		options.RetryCodes = retryCodes
+	}
+}
+
+// WithUnaryInterceptors replaces the default unary interceptors
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) Option {
+	return func(options *Options) {
+		options.UnaryInterceptors = interceptors
+	}
+}
+
+// WithStreamInterceptors replaces the default stream interceptors
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) Option {
+	return func(options *Options) {
+		options.StreamInterceptors = interceptors
+	}
+}
+
+// Conn implements grpc.ClientConnInterface on the connections of pool.GetConnectionPool(),
+// so that it could be passed to the New...Client functions generated by protoc-gen-go-grpc
+type Conn struct {
+	addr    string
+	options *Options
+
+	unaryInterceptor  grpc.UnaryClientInterceptor
+	streamInterceptor grpc.StreamClientInterceptor
+}
+
+var _ grpc.ClientConnInterface = (*Conn)(nil)
+
+// NewConn returns a new Conn pointer to the address, connections are created by the pool on demand
+func NewConn(addr string, opts ...Option) *Conn {
+	options := NewOptions(opts...)
+	return &Conn{
+		addr:              addr,
+		options:           options,
+		unaryInterceptor:  grpcMiddleware.ChainUnaryClient(options.UnaryInterceptors...),
+		streamInterceptor: grpcMiddleware.ChainStreamClient(options.StreamInterceptors...),
+	}
+}
+
+// Invoke performs a unary RPC through the interceptors, each attempt has its own timeout
+func (conn *Conn) Invoke(ctx context.Context, method string, req, reply interface{}, opts ...grpc.CallOption) error {
+	clientConn, err := pool.GetConnectionPool().GetConnection(ctx, conn.addr)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "get connection failed! addr:%v, error:%v", conn.addr, err)
+	}
+	return conn.unaryInterceptor(ctx, method, req, reply, clientConn, conn.invokeWithRetry, opts...)
+}
+
+// NewStream begins a streaming RPC through the interceptors, streams are neither timed out nor retried,
+// use the context to cancel them
+func (conn *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	clientConn, err := pool.GetConnectionPool().GetConnection(ctx, conn.addr)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "get connection failed! addr:%v, error:%v", conn.addr, err)
+	}
+	return conn.streamInterceptor(ctx, desc, clientConn, method, streamer, opts...)
+}
+
+// invokeWithRetry invokes the RPC and retries on the retry codes until the retries run out
+func (conn *Conn) invokeWithRetry(ctx context.Context, method string, req, reply interface{},
+	clientConn *grpc.ClientConn, opts ...grpc.CallOption) error {
+	var err error
+	for attempt := 0; attempt <= conn.options.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(conn.options.RetryBackoff * time.Duration(attempt)):
+			}
+		}
+
+		err = conn.invokeWithTimeout(ctx, method, req, reply, clientConn, opts...)
+		if err == nil || !conn.isRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// invokeWithTimeout invokes the RPC with the timeout of RPC
+func (conn *Conn) invokeWithTimeout(ctx context.Context, method string, req, reply interface{},
+	clientConn *grpc.ClientConn, opts ...grpc.CallOption) error {
+	timeout, ok := conn.options.RPCTimeouts[path.Base(method)]
+	if !ok {
+		timeout = conn.options.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return clientConn.Invoke(ctx, method, req, reply, opts...)
+}
+
+// isRetryable checks if the status code of error is one of the retry codes
+func (conn *Conn) isRetryable(err error) bool {
+	code := status.Code(err)
+	for _, retryCode := range conn.options.RetryCodes {
+		if code == retryCode {
+			return true
+		}
+	}
+	return false
+}
+
+// streamer creates the stream on the connection
+func streamer(ctx context.Context, desc *grpc.StreamDesc, clientConn *grpc.ClientConn,
+	method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return clientConn.NewStream(ctx, desc, method, opts...)
+}
@@ -30,3 +30,29 @@ func NullServerInterceptorFunc(ctx context.Context, req interface{}, info *grpc.
 	// process
 	return handler(ctx, req)
 }
+
+func GetStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return NullStreamClientInterceptor
+}
+
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return NullStreamServerInterceptor
+}
+
+// null stream client interceptor
+var NullStreamClientInterceptor grpc.StreamClientInterceptor = NullStreamClientInterceptorFunc
+
+func NullStreamClientInterceptorFunc(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+	// Create stream
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// null stream server interceptor
+var NullStreamServerInterceptor grpc.StreamServerInterceptor = NullStreamServerInterceptorFunc
+
+func NullStreamServerInterceptorFunc(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	// process
+	return handler(srv, stream)
+}
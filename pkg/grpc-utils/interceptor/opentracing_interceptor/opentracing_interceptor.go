@@ -1,9 +1,13 @@
 package opentracing_interceptor
 
 import (
+	"io"
+
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
+	"github.com/opentracing/opentracing-go"
 )
 
 func GetClientInterceptor() grpc.UnaryClientInterceptor {
@@ -13,3 +17,85 @@ func GetClientInterceptor() grpc.UnaryClientInterceptor {
 func GetServerInterceptor() grpc.UnaryServerInterceptor {
 	return grpc_opentracing.UnaryServerInterceptor()
 }
+
+// GetStreamClientInterceptor traces the whole stream as one span and logs every message sent or received on it
+func GetStreamClientInterceptor() grpc.StreamClientInterceptor {
+	tracingInterceptor := grpc_opentracing.StreamClientInterceptor()
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		// the span is only available in the context passed to the streamer
+		tracedStreamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+			method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			clientStream, err := streamer(ctx, desc, cc, method, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return &tracedClientStream{ClientStream: clientStream, span: opentracing.SpanFromContext(ctx)}, nil
+		}
+
+		return tracingInterceptor(ctx, desc, cc, method, tracedStreamer, opts...)
+	}
+}
+
+// GetStreamServerInterceptor traces the whole stream as one span and logs every message sent or received on it
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	tracingInterceptor := grpc_opentracing.StreamServerInterceptor()
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tracedHandler := func(srv interface{}, stream grpc.ServerStream) error {
+			return handler(srv, &tracedServerStream{
+				ServerStream: stream, span: opentracing.SpanFromContext(stream.Context())})
+		}
+
+		return tracingInterceptor(srv, stream, info, tracedHandler)
+	}
+}
+
+// tracedClientStream logs message events to the span of client stream
+type tracedClientStream struct {
+	grpc.ClientStream
+	span opentracing.Span
+}
+
+func (s *tracedClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	logMessageEvent(s.span, "send", err)
+	return err
+}
+
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	logMessageEvent(s.span, "recv", err)
+	return err
+}
+
+// tracedServerStream logs message events to the span of server stream
+type tracedServerStream struct {
+	grpc.ServerStream
+	span opentracing.Span
+}
+
+func (s *tracedServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	logMessageEvent(s.span, "send", err)
+	return err
+}
+
+func (s *tracedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	logMessageEvent(s.span, "recv", err)
+	return err
+}
+
+func logMessageEvent(span opentracing.Span, event string, err error) {
+	if span == nil {
+		return
+	}
+
+	if err != nil && err != io.EOF {
+		span.LogKV("event", event+" message failed", "error", err.Error())
+	} else {
+		span.LogKV("event", event+" message")
+	}
+}
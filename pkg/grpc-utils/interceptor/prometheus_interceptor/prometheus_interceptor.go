@@ -0,0 +1,251 @@
+package prometheus_interceptor
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	"github.com/DarkMetrix/gofra/pkg/monitor/prometheus"
+)
+
+// metrics are always reported to prometheus as 'grpc_{client,server}_*' with 'method' and 'code' tags,
+// any other monitor.Monitor could be used by Get...InterceptorWithMonitor
+var prometheusMonitor monitor.Monitor = prometheus.NewMonitor()
+
+func GetClientInterceptor() grpc.UnaryClientInterceptor {
+	return GofraClientInterceptor
+}
+
+func GetServerInterceptor() grpc.UnaryServerInterceptor {
+	return GofraServerInterceptor
+}
+
+func GetStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return GofraStreamClientInterceptor
+}
+
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return GofraStreamServerInterceptor
+}
+
+// GetClientInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetClientInterceptorWithMonitor(m monitor.Monitor) grpc.UnaryClientInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return interceptClient(m, ctx, method, req, reply, cc, invoker, opts...)
+	}
+}
+
+// GetServerInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetServerInterceptorWithMonitor(m monitor.Monitor) grpc.UnaryServerInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return interceptServer(m, ctx, req, info, handler)
+	}
+}
+
+// GetStreamClientInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetStreamClientInterceptorWithMonitor(m monitor.Monitor) grpc.StreamClientInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return interceptStreamClient(m, ctx, desc, cc, method, streamer, opts...)
+	}
+}
+
+// GetStreamServerInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetStreamServerInterceptorWithMonitor(m monitor.Monitor) grpc.StreamServerInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptStreamServer(m, srv, stream, info, handler)
+	}
+}
+
+// prometheus client interceptor
+var GofraClientInterceptor grpc.UnaryClientInterceptor = GofraClientInterceptorFunc
+
+func GofraClientInterceptorFunc(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return interceptClient(prometheusMonitor, ctx, method, req, reply, cc, invoker, opts...)
+}
+
+// prometheus server interceptor
+var GofraServerInterceptor grpc.UnaryServerInterceptor = GofraServerInterceptorFunc
+
+func GofraServerInterceptorFunc(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (reply interface{}, err error) {
+	return interceptServer(prometheusMonitor, ctx, req, info, handler)
+}
+
+// prometheus stream client interceptor
+var GofraStreamClientInterceptor grpc.StreamClientInterceptor = GofraStreamClientInterceptorFunc
+
+func GofraStreamClientInterceptorFunc(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return interceptStreamClient(prometheusMonitor, ctx, desc, cc, method, streamer, opts...)
+}
+
+// prometheus stream server interceptor
+var GofraStreamServerInterceptor grpc.StreamServerInterceptor = GofraStreamServerInterceptorFunc
+
+func GofraStreamServerInterceptorFunc(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return interceptStreamServer(prometheusMonitor, srv, stream, info, handler)
+}
+
+func interceptClient(m monitor.Monitor, ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+
+	// monitor method enter total
+	m.Increment("grpc_client_started_total", monitor.Tags{"method": method})
+
+	// Invoke remote
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	// monitor method handled total & latency by code
+	handled(m, "grpc_client", method, start, err)
+
+	return err
+}
+
+func interceptServer(m monitor.Monitor, ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	// monitor method enter total
+	m.Increment("grpc_server_started_total", monitor.Tags{"method": info.FullMethod})
+
+	// Process
+	reply, err := handler(ctx, req)
+
+	// monitor method handled total & latency by code
+	handled(m, "grpc_server", info.FullMethod, start, err)
+
+	return reply, err
+}
+
+func interceptStreamClient(m monitor.Monitor, ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+
+	// monitor stream enter total
+	m.Increment("grpc_client_started_total", monitor.Tags{"method": method})
+
+	// Create stream
+	clientStream, err := streamer(ctx, desc, cc, method, opts...)
+
+	if err != nil {
+		handled(m, "grpc_client", method, start, err)
+		return nil, err
+	}
+
+	return &monitoredClientStream{
+		ClientStream: clientStream, monitor: m, method: method, serverStreams: desc.ServerStreams, start: start}, nil
+}
+
+func interceptStreamServer(m monitor.Monitor, srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	// monitor stream enter total
+	m.Increment("grpc_server_started_total", monitor.Tags{"method": info.FullMethod})
+
+	// Process
+	err := handler(srv, &monitoredServerStream{ServerStream: stream, monitor: m, method: info.FullMethod})
+
+	// monitor stream handled total & latency by code
+	handled(m, "grpc_server", info.FullMethod, start, err)
+
+	return err
+}
+
+// handled reports the handled total & latency labelled by method and code, e.g.: 'grpc_server_handled_total'
+func handled(m monitor.Monitor, prefix, method string, start time.Time, err error) {
+	tags := monitor.Tags{"method": method, "code": status.Code(err).String()}
+
+	m.Increment(prefix+"_handled_total", tags)
+	m.Timing(prefix+"_handling_seconds", time.Since(start), tags)
+}
+
+// monitoredClientStream counts every message sent or received by client stream
+type monitoredClientStream struct {
+	grpc.ClientStream
+	monitor       monitor.Monitor
+	method        string
+	serverStreams bool
+
+	start      time.Time
+	finishOnce sync.Once
+}
+
+func (s *monitoredClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+
+	if err == nil {
+		s.monitor.Increment("grpc_client_msg_sent_total", monitor.Tags{"method": s.method})
+	}
+
+	return err
+}
+
+func (s *monitoredClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	if err == io.EOF {
+		s.finish(nil)
+	} else if err != nil {
+		s.finish(err)
+	} else {
+		s.monitor.Increment("grpc_client_msg_received_total", monitor.Tags{"method": s.method})
+
+		// non server streaming calls finish after the only response message
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+	}
+
+	return err
+}
+
+// finish records the stream result once the stream is over
+func (s *monitoredClientStream) finish(err error) {
+	s.finishOnce.Do(func() {
+		handled(s.monitor, "grpc_client", s.method, s.start, err)
+	})
+}
+
+// monitoredServerStream counts every message sent or received by server stream
+type monitoredServerStream struct {
+	grpc.ServerStream
+	monitor monitor.Monitor
+	method  string
+}
+
+func (s *monitoredServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+
+	if err == nil {
+		s.monitor.Increment("grpc_server_msg_sent_total", monitor.Tags{"method": s.method})
+	}
+
+	return err
+}
+
+func (s *monitoredServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if err == nil {
+		s.monitor.Increment("grpc_server_msg_received_total", monitor.Tags{"method": s.method})
+	}
+
+	return err
+}
@@ -6,6 +6,8 @@ import (
 	log "github.com/cihub/seelog"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 )
@@ -22,3 +24,18 @@ func GetServerInterceptor() grpc.UnaryServerInterceptor {
 
 	return grpc_recovery.UnaryServerInterceptor(opts...)
 }
+
+// GetStreamServerInterceptor fails the stream with codes.Internal on panic, a nil error would end the stream
+// as if the handler succeeded
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	recoverFunc := func (p interface{}) error {
+		log.Errorf("Got panic! error:%v, stack:%v", p, string(debug.Stack()))
+		return status.Errorf(codes.Internal, "panic recovered! error:%v", p)
+	}
+
+	opts := []grpc_recovery.Option{
+		grpc_recovery.WithRecoveryHandler(recoverFunc),
+	}
+
+	return grpc_recovery.StreamServerInterceptor(opts...)
+}
@@ -0,0 +1,51 @@
+package recover_interceptor
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInterceptorRecover(t *testing.T) {
+	// the unary interceptor keeps returning a nil error on panic
+	tests := []struct {
+		name           string
+		panicked       bool
+		wantUnaryCode  codes.Code
+		wantStreamCode codes.Code
+	}{
+		{name: "no panic", wantUnaryCode: codes.OK, wantStreamCode: codes.OK},
+		{name: "panic", panicked: true, wantUnaryCode: codes.OK, wantStreamCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				if tt.panicked {
+					panic("unary")
+				}
+				return req, nil
+			}
+			_, err := GetServerInterceptor()(context.Background(), nil,
+				&grpc.UnaryServerInfo{FullMethod: "/test.Test/Unary"}, unaryHandler)
+			if code := status.Code(err); code != tt.wantUnaryCode {
+				t.Errorf("unary interceptor got code %v, want %v", code, tt.wantUnaryCode)
+			}
+
+			streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+				if tt.panicked {
+					panic("stream")
+				}
+				return nil
+			}
+			err = GetStreamServerInterceptor()(nil, nil,
+				&grpc.StreamServerInfo{FullMethod: "/test.Test/Stream"}, streamHandler)
+			if code := status.Code(err); code != tt.wantStreamCode {
+				t.Errorf("stream interceptor got code %v, want %v", code, tt.wantStreamCode)
+			}
+		})
+	}
+}
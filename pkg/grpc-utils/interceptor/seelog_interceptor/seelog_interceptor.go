@@ -2,6 +2,7 @@ package seelog_interceptor
 
 import (
 	"fmt"
+	"io"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -55,3 +56,112 @@ func GofraServerInterceptorFunc(ctx context.Context, req interface{}, info *grpc
 
 	return reply, err
 }
+
+func GetStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return GofraStreamClientInterceptor
+}
+
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return GofraStreamServerInterceptor
+}
+
+// seelog stream client interceptor
+var GofraStreamClientInterceptor grpc.StreamClientInterceptor = GofraStreamClientInterceptorFunc
+
+func GofraStreamClientInterceptorFunc(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	// Create stream
+	clientStream, err := streamer(ctx, desc, cc, method, opts...)
+
+	ctxInfo := fmt.Sprintf("%v", ctx)
+	log.Tracef("context:%v", ctxInfo)
+
+	if err != nil {
+		log.Warnf("create stream failed! method=%v, error:%v", method, err)
+		return nil, err
+	}
+
+	log.Debugf("create stream success! method=%v", method)
+	return &loggedClientStream{ClientStream: clientStream, method: method}, nil
+}
+
+// seelog stream server interceptor
+var GofraStreamServerInterceptor grpc.StreamServerInterceptor = GofraStreamServerInterceptorFunc
+
+func GofraStreamServerInterceptorFunc(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	// process
+	err := handler(srv, &loggedServerStream{ServerStream: stream, method: info.FullMethod})
+
+	ctxInfo := fmt.Sprintf("%v", stream.Context())
+	log.Tracef("context:%v", ctxInfo)
+
+	if err != nil {
+		log.Warnf("handle stream failed! method=%v, error:%v", info.FullMethod, err)
+	} else {
+		log.Debugf("handle stream success! method=%v", info.FullMethod)
+	}
+
+	return err
+}
+
+// loggedClientStream logs every message sent or received by client stream
+type loggedClientStream struct {
+	grpc.ClientStream
+	method string
+}
+
+func (s *loggedClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+
+	if err != nil {
+		log.Warnf("send message failed! method=%v, msg=%v, error:%v", s.method, m, err)
+	} else {
+		log.Tracef("send message success! method=%v, msg=%v", s.method, m)
+	}
+
+	return err
+}
+
+func (s *loggedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	if err == io.EOF {
+		log.Debugf("stream finished! method=%v", s.method)
+	} else if err != nil {
+		log.Warnf("recv message failed! method=%v, error:%v", s.method, err)
+	} else {
+		log.Tracef("recv message success! method=%v, msg=%v", s.method, m)
+	}
+
+	return err
+}
+
+// loggedServerStream logs every message sent or received by server stream
+type loggedServerStream struct {
+	grpc.ServerStream
+	method string
+}
+
+func (s *loggedServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+
+	if err != nil {
+		log.Warnf("send message failed! method=%v, msg=%v, error:%v", s.method, m, err)
+	} else {
+		log.Tracef("send message success! method=%v, msg=%v", s.method, m)
+	}
+
+	return err
+}
+
+func (s *loggedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if err != nil && err != io.EOF {
+		log.Warnf("recv message failed! method=%v, error:%v", s.method, err)
+	} else if err == nil {
+		log.Tracef("recv message success! method=%v, msg=%v", s.method, m)
+	}
+
+	return err
+}
@@ -1,12 +1,22 @@
 package statsd_interceptor
 
 import (
+	"io"
+	"sync"
+	"time"
+
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
-	monitor "github.com/DarkMetrix/gofra/pkg/monitor/statsd"
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	"github.com/DarkMetrix/gofra/pkg/monitor/statsd"
 )
 
+// metrics are reported with the method as the name and the result as the 'type' tag, e.g.: '/foo.Bar/Baz,type=Server.Total',
+// which is the bucket layout of statsd, so they're always reported to statsd,
+// any other monitor.Monitor could be used by Get...InterceptorWithMonitor
+var statsdMonitor monitor.Monitor = statsd.NewMonitor()
+
 func GetClientInterceptor() grpc.UnaryClientInterceptor {
 	return GofraClientInterceptor
 }
@@ -15,91 +25,256 @@ func GetServerInterceptor() grpc.UnaryServerInterceptor {
 	return GofraServerInterceptor
 }
 
+func GetStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return GofraStreamClientInterceptor
+}
+
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return GofraStreamServerInterceptor
+}
+
+// GetClientInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetClientInterceptorWithMonitor(m monitor.Monitor) grpc.UnaryClientInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return interceptClient(m, ctx, method, req, reply, cc, invoker, opts...)
+	}
+}
+
+// GetServerInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetServerInterceptorWithMonitor(m monitor.Monitor) grpc.UnaryServerInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return interceptServer(m, ctx, req, info, handler)
+	}
+}
+
+// GetStreamClientInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetStreamClientInterceptorWithMonitor(m monitor.Monitor) grpc.StreamClientInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return interceptStreamClient(m, ctx, desc, cc, method, streamer, opts...)
+	}
+}
+
+// GetStreamServerInterceptorWithMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func GetStreamServerInterceptorWithMonitor(m monitor.Monitor) grpc.StreamServerInterceptor {
+	m = monitor.OrDefault(m)
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptStreamServer(m, srv, stream, info, handler)
+	}
+}
+
 // statsd client interceptor
 var GofraClientInterceptor grpc.UnaryClientInterceptor = GofraClientInterceptorFunc
 
 func GofraClientInterceptorFunc(ctx context.Context, method string, req, reply interface{},
 	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return interceptClient(statsdMonitor, ctx, method, req, reply, cc, invoker, opts...)
+}
 
-	client := monitor.GetStatsd()
+// statsd server interceptor
+var GofraServerInterceptor grpc.UnaryServerInterceptor = GofraServerInterceptorFunc
 
-	if client != nil {
-		defer monitor.GetStatsd().NewTiming().Send(method + "|Time,type=Client.Time")
+func GofraServerInterceptorFunc(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (reply interface{}, err error) {
+	return interceptServer(statsdMonitor, ctx, req, info, handler)
+}
 
-		// monitor method enter total
-		monitor.Increment(method + ",type=Client.Total")
+// statsd stream client interceptor
+var GofraStreamClientInterceptor grpc.StreamClientInterceptor = GofraStreamClientInterceptorFunc
 
-		// Invoke remote
-		err := invoker(ctx, method, req, reply, cc, opts...)
+func GofraStreamClientInterceptorFunc(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return interceptStreamClient(statsdMonitor, ctx, desc, cc, method, streamer, opts...)
+}
 
-		if err != nil {
-			// monitor method fail total
-			monitor.Increment(method + ",type=Client.Fail")
-		} else {
-			// monitor method success total
-			monitor.Increment(method + ",type=Client.Success")
-		}
+// statsd stream server interceptor
+var GofraStreamServerInterceptor grpc.StreamServerInterceptor = GofraStreamServerInterceptorFunc
+
+func GofraStreamServerInterceptorFunc(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return interceptStreamServer(statsdMonitor, srv, stream, info, handler)
+}
 
-		return err
+func interceptClient(m monitor.Monitor, ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	defer sendTiming(m, method, "Client.Time", time.Now())
+
+	// monitor method enter total
+	m.Increment(method, monitor.Tags{"type": "Client.Total"})
+
+	// Invoke remote
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	if err != nil {
+		// monitor method fail total
+		m.Increment(method, monitor.Tags{"type": "Client.Fail"})
 	} else {
-		// monitor method enter total
-		monitor.Increment(method + ",type=Client.Total")
+		// monitor method success total
+		m.Increment(method, monitor.Tags{"type": "Client.Success"})
+	}
 
-		// Invoke remote
-		err := invoker(ctx, method, req, reply, cc, opts...)
+	return err
+}
 
-		if err != nil {
-			// monitor method fail total
-			monitor.Increment(method + ",type=Client.Fail")
-		} else {
-			// monitor method success total
-			monitor.Increment(method + ",type=Client.Success")
-		}
+func interceptServer(m monitor.Monitor, ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	defer sendTiming(m, info.FullMethod, "Server.Time", time.Now())
+
+	// monitor method enter total
+	m.Increment(info.FullMethod, monitor.Tags{"type": "Server.Total"})
+
+	// Process
+	reply, err := handler(ctx, req)
 
-		return err
+	if err != nil {
+		// monitor method fail total
+		m.Increment(info.FullMethod, monitor.Tags{"type": "Server.Fail"})
+	} else {
+		// monitor method success total
+		m.Increment(info.FullMethod, monitor.Tags{"type": "Server.Success"})
 	}
+
+	return reply, err
 }
 
-// statsd server interceptor
-var GofraServerInterceptor grpc.UnaryServerInterceptor = GofraServerInterceptorFunc
+func interceptStreamClient(m monitor.Monitor, ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	// the stream lasts until the last message is received, so timing is sent when the stream finishes
+	start := time.Now()
 
-func GofraServerInterceptorFunc(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (reply interface{}, err error) {
+	// monitor stream enter total
+	m.Increment(method, monitor.Tags{"type": "Client.Stream.Total"})
 
-	client := monitor.GetStatsd()
+	// Create stream
+	clientStream, err := streamer(ctx, desc, cc, method, opts...)
 
-	if client != nil {
-		defer monitor.GetStatsd().NewTiming().Send(info.FullMethod + "|Time,type=Server.Time")
+	if err != nil {
+		// monitor stream fail total
+		m.Increment(method, monitor.Tags{"type": "Client.Stream.Fail"})
+		sendTiming(m, method, "Client.Stream.Time", start)
+		return nil, err
+	}
 
-		// monitor method enter total
-		monitor.Increment(info.FullMethod + ",type=Server.Total")
+	return &monitoredClientStream{
+		ClientStream: clientStream, monitor: m, method: method, serverStreams: desc.ServerStreams, start: start}, nil
+}
 
-		// Process
-		reply, err = handler(ctx, req)
+func interceptStreamServer(m monitor.Monitor, srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	defer sendTiming(m, info.FullMethod, "Server.Stream.Time", time.Now())
 
-		if err != nil {
-			// monitor method fail total
-			monitor.Increment(info.FullMethod + ",type=Server.Fail")
-		} else {
-			// monitor method success total
-			monitor.Increment(info.FullMethod + ",type=Server.Success")
-		}
+	// monitor stream enter total
+	m.Increment(info.FullMethod, monitor.Tags{"type": "Server.Stream.Total"})
+
+	// Process
+	err := handler(srv, &monitoredServerStream{ServerStream: stream, monitor: m, method: info.FullMethod})
+
+	if err != nil {
+		// monitor stream fail total
+		m.Increment(info.FullMethod, monitor.Tags{"type": "Server.Stream.Fail"})
+	} else {
+		// monitor stream success total
+		m.Increment(info.FullMethod, monitor.Tags{"type": "Server.Stream.Success"})
+	}
+
+	return err
+}
+
+// sendTiming sends the time elapsed since start, e.g.: '/foo.Bar/Baz|Time,type=Server.Time'
+func sendTiming(m monitor.Monitor, method, timingType string, start time.Time) {
+	m.Timing(method+"|Time", time.Since(start), monitor.Tags{"type": timingType})
+}
+
+// monitoredClientStream counts every message sent or received by client stream
+type monitoredClientStream struct {
+	grpc.ClientStream
+	monitor       monitor.Monitor
+	method        string
+	serverStreams bool
+
+	start      time.Time
+	finishOnce sync.Once
+}
+
+func (s *monitoredClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
 
-		return reply, err
+	if err != nil {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Client.Stream.Send.Fail"})
+		s.finish(err)
 	} else {
-		// monitor method enter total
-		monitor.Increment(info.FullMethod + ",type=Server.Total")
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Client.Stream.Send.Success"})
+	}
+
+	return err
+}
+
+func (s *monitoredClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	if err == io.EOF {
+		s.finish(nil)
+	} else if err != nil {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Client.Stream.Recv.Fail"})
+		s.finish(err)
+	} else {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Client.Stream.Recv.Success"})
+
+		// non server streaming calls finish after the only response message
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+	}
+
+	return err
+}
 
-		// Process
-		reply, err = handler(ctx, req)
+// finish records the stream result once the stream is over
+func (s *monitoredClientStream) finish(err error) {
+	s.finishOnce.Do(func() {
+		sendTiming(s.monitor, s.method, "Client.Stream.Time", s.start)
 
 		if err != nil {
-			// monitor method fail total
-			monitor.Increment(info.FullMethod + ",type=Server.Fail")
+			s.monitor.Increment(s.method, monitor.Tags{"type": "Client.Stream.Fail"})
 		} else {
-			// monitor method success total
-			monitor.Increment(info.FullMethod + ",type=Server.Success")
+			s.monitor.Increment(s.method, monitor.Tags{"type": "Client.Stream.Success"})
 		}
+	})
+}
+
+// monitoredServerStream counts every message sent or received by server stream
+type monitoredServerStream struct {
+	grpc.ServerStream
+	monitor monitor.Monitor
+	method  string
+}
 
-		return reply, err
+func (s *monitoredServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+
+	if err != nil {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Server.Stream.Send.Fail"})
+	} else {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Server.Stream.Send.Success"})
 	}
+
+	return err
+}
+
+func (s *monitoredServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if err != nil && err != io.EOF {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Server.Stream.Recv.Fail"})
+	} else if err == nil {
+		s.monitor.Increment(s.method, monitor.Tags{"type": "Server.Stream.Recv.Success"})
+	}
+
+	return err
 }
@@ -2,6 +2,7 @@ package std_interceptor
 
 import (
 	"fmt"
+	"io"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -47,3 +48,90 @@ func StdServerInterceptorFunc(ctx context.Context, req interface{}, info *grpc.U
 
 	return reply, err
 }
+
+func GetStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return StdStreamClientInterceptor
+}
+
+func GetStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return StdStreamServerInterceptor
+}
+
+// std stream client interceptor
+var StdStreamClientInterceptor grpc.StreamClientInterceptor = StdStreamClientInterceptorFunc
+
+func StdStreamClientInterceptorFunc(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	// Create stream
+	clientStream, err := streamer(ctx, desc, cc, method, opts...)
+
+	if err != nil {
+		fmt.Printf("context=%v, method=%v, create stream failed!!! error:%v\r\n", ctx, method, err)
+		return nil, err
+	}
+
+	fmt.Printf("context=%v, method=%v, create stream success!!!\r\n", ctx, method)
+	return &stdClientStream{ClientStream: clientStream, method: method}, nil
+}
+
+// std stream server interceptor
+var StdStreamServerInterceptor grpc.StreamServerInterceptor = StdStreamServerInterceptorFunc
+
+func StdStreamServerInterceptorFunc(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	// Process
+	err := handler(srv, &stdServerStream{ServerStream: stream, method: info.FullMethod})
+
+	if err != nil {
+		fmt.Printf("context=%v, method=%v, handle stream failed!!! error:%v\r\n", stream.Context(), info.FullMethod, err)
+	} else {
+		fmt.Printf("context=%v, method=%v, handle stream success!!!\r\n", stream.Context(), info.FullMethod)
+	}
+
+	return err
+}
+
+// stdClientStream prints every message sent or received by client stream
+type stdClientStream struct {
+	grpc.ClientStream
+	method string
+}
+
+func (s *stdClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	printMessage(s.method, "send", m, err)
+	return err
+}
+
+func (s *stdClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	printMessage(s.method, "recv", m, err)
+	return err
+}
+
+// stdServerStream prints every message sent or received by server stream
+type stdServerStream struct {
+	grpc.ServerStream
+	method string
+}
+
+func (s *stdServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	printMessage(s.method, "send", m, err)
+	return err
+}
+
+func (s *stdServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	printMessage(s.method, "recv", m, err)
+	return err
+}
+
+func printMessage(method, event string, m interface{}, err error) {
+	if err == io.EOF {
+		fmt.Printf("method=%v, stream finished!!!\r\n", method)
+	} else if err != nil {
+		fmt.Printf("method=%v, %v message failed!!! error:%v\r\n", method, event, err)
+	} else {
+		fmt.Printf("method=%v, %v message success!!! msg:%v\r\n", method, event, m)
+	}
+}
@@ -0,0 +1,314 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture represents a canned response of RPC, messages are written in the proto JSON mapping
+type Fixture struct {
+	// fields the request must have, e.g.: {"name": "foo"}, nested messages are matched partially,
+	// the fixture without match is the default one of RPC
+	Match map[string]interface{} `yaml:"match" json:"match,omitempty"`
+	// response of unary & client streaming RPCs, an empty one is returned if it's not set
+	Response interface{} `yaml:"response" json:"response,omitempty"`
+	// responses sent in order by server & bidirectional streaming RPCs
+	Responses []interface{} `yaml:"responses" json:"responses,omitempty"`
+	// status code returned after the responses, e.g.: NotFound or NOT_FOUND, it's OK if empty
+	Code    string `yaml:"code" json:"code,omitempty"`
+	Message string `yaml:"message" json:"message,omitempty"`
+}
+
+// Fixtures represents the canned responses of service keyed by RPC name, e.g.: AddUser
+type Fixtures map[string][]*Fixture
+
+// LoadFixtures loads the fixtures from YAML or JSON file
+func LoadFixtures(path string) (Fixtures, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("ioutil.ReadFile failed! error:%w", err)
+	}
+
+	// JSON is parsed as YAML as well
+	fixtures := make(Fixtures)
+	if err := yaml.Unmarshal(data, &fixtures); err != nil {
+		return nil, xerrors.Errorf("yaml.Unmarshal failed! path:%v, error:%w", path, err)
+	}
+	for method, methodFixtures := range fixtures {
+		for index, fixture := range methodFixtures {
+			if _, err := parseCode(fixture.Code); err != nil {
+				return nil, xerrors.Errorf("invalid fixture! path:%v, method:%v, index:%v, error:%w",
+					path, method, index, err)
+			}
+		}
+	}
+	return fixtures, nil
+}
+
+// Call represents a request received by Mock
+type Call struct {
+	// RPC name, e.g.: AddUser
+	Method  string
+	Request proto.Message
+}
+
+// Mock returns the responses of fixtures and records the requests, it's safe for concurrent use
+type Mock struct {
+	mutex    sync.Mutex
+	fixtures Fixtures
+	calls    []*Call
+	onCall   func(*Call)
+}
+
+// New returns a new Mock pointer
+func New(fixtures Fixtures) *Mock {
+	if fixtures == nil {
+		fixtures = make(Fixtures)
+	}
+	return &Mock{fixtures: fixtures}
+}
+
+// SetFixtures replaces the fixtures of RPC
+func (mock *Mock) SetFixtures(method string, fixtures ...*Fixture) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.fixtures[method] = fixtures
+}
+
+// OnCall sets the hook called with every request received, e.g.: logging
+func (mock *Mock) OnCall(hook func(*Call)) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.onCall = hook
+}
+
+// Calls returns the requests received in order, the ones of all RPCs are returned if method is empty
+func (mock *Mock) Calls(method string) []*Call {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	calls := make([]*Call, 0, len(mock.calls))
+	for _, call := range mock.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clears the requests received
+func (mock *Mock) Reset() {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.calls = nil
+}
+
+// Handle records the requests and returns the responses of the fixture matched, the error is the status of fixture,
+// the first fixture matched by any of the requests is used, otherwise the first one without match.
+// Unary & client streaming RPCs get at least one response if the error is nil.
+func (mock *Mock) Handle(method string, requests []proto.Message,
+	newResponse func() proto.Message) ([]proto.Message, error) {
+	mock.mutex.Lock()
+	fixtures := mock.fixtures[method]
+	onCall := mock.onCall
+	calls := make([]*Call, 0, len(requests))
+	for _, request := range requests {
+		calls = append(calls, &Call{Method: method, Request: request})
+	}
+	mock.calls = append(mock.calls, calls...)
+	mock.mutex.Unlock()
+
+	if onCall != nil {
+		for _, call := range calls {
+			onCall(call)
+		}
+	}
+
+	fixture, err := findFixture(fixtures, requests)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "match fixture failed! method:%v, error:%v", method, err)
+	}
+	if fixture == nil {
+		return nil, status.Errorf(codes.Unimplemented, "no fixture matched! method:%v", method)
+	}
+
+	values := fixture.Responses
+	if len(values) == 0 && fixture.Response != nil {
+		values = []interface{}{fixture.Response}
+	}
+	code, _ := parseCode(fixture.Code)
+	if len(values) == 0 && code == codes.OK {
+		values = []interface{}{map[string]interface{}{}}
+	}
+
+	responses := make([]proto.Message, 0, len(values))
+	for _, value := range values {
+		response := newResponse()
+		if err := unmarshalMessage(value, response); err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid fixture response! method:%v, error:%v", method, err)
+		}
+		responses = append(responses, response)
+	}
+	if code != codes.OK {
+		return responses, status.Error(code, fixture.Message)
+	}
+	return responses, nil
+}
+
+// findFixture returns the first fixture matched by any of the requests, otherwise the first one without match
+func findFixture(fixtures []*Fixture, requests []proto.Message) (*Fixture, error) {
+	// the requests are matched against both the JSON names & the proto names of fields
+	values := make([]interface{}, 0, len(requests)*2)
+	for _, request := range requests {
+		for _, options := range []protojson.MarshalOptions{
+			{EmitUnpopulated: true},
+			{EmitUnpopulated: true, UseProtoNames: true},
+		} {
+			data, err := options.Marshal(request)
+			if err != nil {
+				return nil, xerrors.Errorf("protojson.Marshal failed! error:%w", err)
+			}
+			var value interface{}
+			if err := json.Unmarshal(data, &value); err != nil {
+				return nil, xerrors.Errorf("json.Unmarshal failed! error:%w", err)
+			}
+			values = append(values, value)
+		}
+	}
+
+	var defaultFixture *Fixture
+	for _, fixture := range fixtures {
+		if len(fixture.Match) == 0 {
+			if defaultFixture == nil {
+				defaultFixture = fixture
+			}
+			continue
+		}
+		for _, value := range values {
+			if matchValue(normalizeValue(fixture.Match), value) {
+				return fixture, nil
+			}
+		}
+	}
+	return defaultFixture, nil
+}
+
+// matchValue checks if the value has the fields of want, numbers are compared by value & other scalars by their
+// string forms since int64 & enums are strings in the proto JSON mapping, e.g.: 1000000 matches "1000000" & 1e+06
+func matchValue(want, value interface{}) bool {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, wantField := range want {
+			field, ok := object[key]
+			if !ok || !matchValue(wantField, field) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		array, ok := value.([]interface{})
+		if !ok || len(array) != len(want) {
+			return false
+		}
+		for index := range want {
+			if !matchValue(want[index], array[index]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return value == nil
+	default:
+		if wantNumber, number, ok := parseNumbers(want, value); ok {
+			return wantNumber.Cmp(number) == 0
+		}
+		return fmt.Sprint(want) == fmt.Sprint(value)
+	}
+}
+
+// parseNumbers parses both scalars as numbers if at least one of them is a number,
+// strings are compared as they are, e.g.: "007" doesn't match "7"
+func parseNumbers(want, value interface{}) (*big.Rat, *big.Rat, bool) {
+	_, wantIsString := want.(string)
+	_, valueIsString := value.(string)
+	if wantIsString && valueIsString {
+		return nil, nil, false
+	}
+
+	wantNumber, ok := new(big.Rat).SetString(fmt.Sprint(want))
+	if !ok {
+		return nil, nil, false
+	}
+	number, ok := new(big.Rat).SetString(fmt.Sprint(value))
+	if !ok {
+		return nil, nil, false
+	}
+	return wantNumber, number, true
+}
+
+// unmarshalMessage sets the message from the value parsed from YAML or JSON
+func unmarshalMessage(value interface{}, message proto.Message) error {
+	data, err := json.Marshal(normalizeValue(value))
+	if err != nil {
+		return xerrors.Errorf("json.Marshal failed! error:%w", err)
+	}
+	if err := protojson.Unmarshal(data, message); err != nil {
+		return xerrors.Errorf("protojson.Unmarshal failed! error:%w", err)
+	}
+	return nil
+}
+
+// normalizeValue converts the maps parsed by YAML into the ones encoding/json could marshal
+func normalizeValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			object[fmt.Sprint(key)] = normalizeValue(field)
+		}
+		return object
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			object[key] = normalizeValue(field)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			array = append(array, normalizeValue(item))
+		}
+		return array
+	default:
+		return value
+	}
+}
+
+// parseCode parses the status code name, e.g.: NotFound or NOT_FOUND, it's OK if empty
+func parseCode(name string) (codes.Code, error) {
+	if name == "" {
+		return codes.OK, nil
+	}
+	normalized := strings.ToLower(strings.Replace(name, "_", "", -1))
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if strings.ToLower(code.String()) == normalized {
+			return code, nil
+		}
+	}
+	return codes.Unknown, xerrors.Errorf("Unknown status code! code:%v", name)
+}
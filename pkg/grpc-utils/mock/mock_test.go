@@ -0,0 +1,344 @@
+package mock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestMatchValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  interface{}
+		value interface{}
+		match bool
+	}{
+		{name: "int matches float", want: 1000000, value: float64(1000000), match: true},
+		{name: "float matches int64 string", want: 1e6, value: "1000000", match: true},
+		{name: "int matches int64 string", want: 1000000, value: "1000000", match: true},
+		{name: "number string matches float", want: "1000000", value: 1e6, match: true},
+		{name: "different numbers", want: 1, value: float64(2)},
+		{name: "number doesn't match text", want: 1, value: "one"},
+		{name: "strings compared as they are", want: "007", value: "7"},
+		{name: "enum", want: "LABEL_REPEATED", value: "LABEL_REPEATED", match: true},
+		{name: "bool", want: true, value: true, match: true},
+		{name: "bool doesn't match string", want: true, value: "false"},
+		{name: "nil", want: nil, value: nil, match: true},
+		{name: "nil doesn't match zero", want: nil, value: float64(0)},
+		{
+			name: "fields matched partially",
+			want: map[string]interface{}{"name": "id", "options": map[string]interface{}{"packed": true}},
+			value: map[string]interface{}{"name": "id", "number": float64(1),
+				"options": map[string]interface{}{"packed": true, "lazy": false}},
+			match: true,
+		},
+		{name: "missing field", want: map[string]interface{}{"name": "id"},
+			value: map[string]interface{}{"number": float64(1)}},
+		{name: "object doesn't match scalar", want: map[string]interface{}{"name": "id"}, value: "id"},
+		{name: "array", want: []interface{}{1, "a"}, value: []interface{}{float64(1), "a"}, match: true},
+		{name: "array of different length", want: []interface{}{1}, value: []interface{}{float64(1), float64(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if match := matchValue(tt.want, tt.value); match != tt.match {
+				t.Errorf("matchValue(%#v, %#v) = %v, want %v", tt.want, tt.value, match, tt.match)
+			}
+		})
+	}
+}
+
+func TestFindFixture(t *testing.T) {
+	byName := &Fixture{Match: map[string]interface{}{"name": "id"}}
+	byProtoName := &Fixture{Match: map[string]interface{}{"json_name": "userId"}}
+	byNumber := &Fixture{Match: map[string]interface{}{"number": 1000000}}
+	byEnum := &Fixture{Match: map[string]interface{}{"label": "LABEL_REPEATED"}}
+	byOptions := &Fixture{Match: map[string]interface{}{"options": map[interface{}]interface{}{"packed": true}}}
+	defaultFixture, secondDefault := &Fixture{}, &Fixture{}
+
+	tests := []struct {
+		name     string
+		fixtures []*Fixture
+		requests []proto.Message
+		want     *Fixture
+	}{
+		{
+			name:     "json name",
+			fixtures: []*Fixture{defaultFixture, byName},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{Name: proto.String("id")}},
+			want:     byName,
+		},
+		{
+			name:     "proto name",
+			fixtures: []*Fixture{byProtoName},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{JsonName: proto.String("userId")}},
+			want:     byProtoName,
+		},
+		{
+			name:     "number",
+			fixtures: []*Fixture{byName, byNumber},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{Number: proto.Int32(1000000)}},
+			want:     byNumber,
+		},
+		{
+			name:     "enum",
+			fixtures: []*Fixture{byEnum},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{
+				Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()}},
+			want: byEnum,
+		},
+		{
+			name:     "nested message parsed from YAML",
+			fixtures: []*Fixture{byEnum, byOptions},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{
+				Options: &descriptorpb.FieldOptions{Packed: proto.Bool(true)}}},
+			want: byOptions,
+		},
+		{
+			name:     "first matched by any of the requests",
+			fixtures: []*Fixture{defaultFixture, byNumber, byName},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{Name: proto.String("name")},
+				&descriptorpb.FieldDescriptorProto{Name: proto.String("id")}},
+			want: byName,
+		},
+		{
+			name:     "first default",
+			fixtures: []*Fixture{byName, defaultFixture, secondDefault},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{Name: proto.String("name")}},
+			want:     defaultFixture,
+		},
+		{
+			name:     "no fixture matched",
+			fixtures: []*Fixture{byName},
+			requests: []proto.Message{&descriptorpb.FieldDescriptorProto{Name: proto.String("name")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, err := findFixture(tt.fixtures, tt.requests)
+			if err != nil {
+				t.Fatalf("findFixture failed! error:%v", err)
+			}
+			if fixture != tt.want {
+				t.Errorf("findFixture() = %+v, want %+v", fixture, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    codes.Code
+		wantErr bool
+	}{
+		{name: "", want: codes.OK},
+		{name: "NotFound", want: codes.NotFound},
+		{name: "NOT_FOUND", want: codes.NotFound},
+		{name: "not_found", want: codes.NotFound},
+		{name: "Unauthenticated", want: codes.Unauthenticated},
+		{name: "Missing", want: codes.Unknown, wantErr: true},
+		{name: "Code(17)", want: codes.Unknown, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := parseCode(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCode() error:%v, want error:%v", err, tt.wantErr)
+			}
+			if code != tt.want {
+				t.Errorf("parseCode() = %v, want %v", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	request := &descriptorpb.FieldDescriptorProto{Name: proto.String("id")}
+	tests := []struct {
+		name      string
+		fixtures  []*Fixture
+		requests  []proto.Message
+		wantNames []string
+		wantCode  codes.Code
+	}{
+		{
+			name:      "unary",
+			fixtures:  []*Fixture{{Response: map[interface{}]interface{}{"name": "foo"}}},
+			requests:  []proto.Message{request},
+			wantNames: []string{"foo"},
+		},
+		{
+			name:      "empty response",
+			fixtures:  []*Fixture{{}},
+			requests:  []proto.Message{request},
+			wantNames: []string{""},
+		},
+		{
+			name:     "error",
+			fixtures: []*Fixture{{Code: "NOT_FOUND", Message: "user not found"}},
+			requests: []proto.Message{request},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "client streaming",
+			fixtures: []*Fixture{
+				{Match: map[string]interface{}{"name": "last"}, Response: map[string]interface{}{"name": "matched"}},
+				{Response: map[string]interface{}{"name": "default"}},
+			},
+			requests:  []proto.Message{request, &descriptorpb.FieldDescriptorProto{Name: proto.String("last")}},
+			wantNames: []string{"matched"},
+		},
+		{
+			name: "server streaming with error",
+			fixtures: []*Fixture{{Responses: []interface{}{map[string]interface{}{"name": "a"},
+				map[string]interface{}{"json_name": "b"}}, Code: "Aborted"}},
+			requests:  []proto.Message{request},
+			wantNames: []string{"a", ""},
+			wantCode:  codes.Aborted,
+		},
+		{
+			name:     "bidirectional streaming without requests",
+			fixtures: []*Fixture{{Match: map[string]interface{}{"name": "id"}}},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name:     "invalid response",
+			fixtures: []*Fixture{{Response: map[string]interface{}{"unknown": 1}}},
+			requests: []proto.Message{request},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := New(nil)
+			mock.SetFixtures("AddField", tt.fixtures...)
+			called := 0
+			mock.OnCall(func(*Call) { called++ })
+
+			responses, err := mock.Handle("AddField", tt.requests, func() proto.Message {
+				return &descriptorpb.FieldDescriptorProto{}
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Handle() code:%v, want:%v, error:%v", code, tt.wantCode, err)
+			}
+			if len(responses) != len(tt.wantNames) {
+				t.Fatalf("Handle() = %v, want names:%v", responses, tt.wantNames)
+			}
+			for index, response := range responses {
+				if name := response.(*descriptorpb.FieldDescriptorProto).GetName(); name != tt.wantNames[index] {
+					t.Errorf("Handle() response %v name:%v, want:%v", index, name, tt.wantNames[index])
+				}
+			}
+
+			if calls := mock.Calls("AddField"); len(calls) != len(tt.requests) || called != len(tt.requests) {
+				t.Errorf("Calls() = %v & %v hooked, want %v", calls, called, len(tt.requests))
+			}
+			if calls := mock.Calls("Other"); len(calls) != 0 {
+				t.Errorf("Calls() of other RPC = %v, want none", calls)
+			}
+			mock.Reset()
+			if calls := mock.Calls(""); len(calls) != 0 {
+				t.Errorf("Calls() after Reset = %v, want none", calls)
+			}
+		})
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			file: "fixtures.yaml",
+			content: `AddField:
+  - match:
+      number: 1000000
+    response:
+      name: id
+      label: LABEL_REPEATED
+  - code: NotFound
+    message: field not found
+`,
+		},
+		{
+			name: "json",
+			file: "fixtures.json",
+			content: `{"AddField": [
+  {"match": {"number": 1000000}, "response": {"name": "id", "label": "LABEL_REPEATED"}},
+  {"code": "NotFound", "message": "field not found"}
+]}`,
+		},
+		{
+			name:    "invalid code",
+			file:    "fixtures.yaml",
+			content: "AddField:\n  - code: Missing\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid file",
+			file:    "fixtures.json",
+			content: `{"AddField": [`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			path := filepath.Join(root, tt.file)
+			if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("ioutil.WriteFile failed! error:%v", err)
+			}
+
+			fixtures, err := LoadFixtures(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadFixtures() error:%v, want error:%v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// both formats are handled the same way
+			mock := New(fixtures)
+			newResponse := func() proto.Message { return &descriptorpb.FieldDescriptorProto{} }
+			responses, err := mock.Handle("AddField",
+				[]proto.Message{&descriptorpb.FieldDescriptorProto{Number: proto.Int32(1000000)}}, newResponse)
+			if err != nil || len(responses) != 1 {
+				t.Fatalf("Handle() = %v, error:%v, want 1 response", responses, err)
+			}
+			response := responses[0].(*descriptorpb.FieldDescriptorProto)
+			if response.GetName() != "id" || response.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+				t.Errorf("Handle() response:%v, want name id & label LABEL_REPEATED", response)
+			}
+
+			_, err = mock.Handle("AddField",
+				[]proto.Message{&descriptorpb.FieldDescriptorProto{Number: proto.Int32(1)}}, newResponse)
+			if status.Code(err) != codes.NotFound || status.Convert(err).Message() != "field not found" {
+				t.Errorf("Handle() error:%v, want NotFound with message", err)
+			}
+		})
+	}
+
+	if _, err := LoadFixtures(filepath.Join(os.TempDir(), "gofra-missing-fixtures.yaml")); err == nil {
+		t.Errorf("LoadFixtures() of missing file should fail")
+	}
+}
@@ -1 +1,156 @@
 package monitor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tags represents the tags attached to a metric, e.g.: {"method": "/foo.Bar/Baz", "type": "Server.Total"}
+type Tags map[string]string
+
+// Keys returns the tag keys in sorted order
+func (tags Tags) Keys() []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Monitor defines the interface every metrics backend should implement
+type Monitor interface {
+	// Increment increases the counter by one
+	Increment(name string, tags Tags)
+	// Count increases the counter by value
+	Count(name string, value float64, tags Tags)
+	// Gauge sets the gauge to value
+	Gauge(name string, value float64, tags Tags)
+	// Timing records the duration of an operation
+	Timing(name string, duration time.Duration, tags Tags)
+	// Histogram records value to the distribution
+	Histogram(name string, value float64, tags Tags)
+}
+
+// NoopMonitor drops all the metrics, it's the default monitor before any backend is chosen
+type NoopMonitor struct{}
+
+func (NoopMonitor) Increment(name string, tags Tags)                      {}
+func (NoopMonitor) Count(name string, value float64, tags Tags)           {}
+func (NoopMonitor) Gauge(name string, value float64, tags Tags)           {}
+func (NoopMonitor) Timing(name string, duration time.Duration, tags Tags) {}
+func (NoopMonitor) Histogram(name string, value float64, tags Tags)       {}
+
+// registry of all the available monitors, backends register themselves on import, e.g.: 'statsd', 'prometheus'
+var mtx sync.RWMutex
+var monitors = make(map[string]Monitor)
+var defaultMonitor Monitor = NoopMonitor{}
+var defaultSet bool
+
+// Register makes a monitor available by name
+func Register(name string, monitor Monitor) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if monitor == nil {
+		panic("monitor: Register monitor is nil")
+	}
+	monitors[name] = monitor
+}
+
+// Get returns the monitor registered by name
+func Get(name string) (Monitor, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	monitor, ok := monitors[name]
+	return monitor, ok
+}
+
+// Use sets the monitor registered by name as the default monitor
+func Use(name string) error {
+	monitor, ok := Get(name)
+	if !ok {
+		return errors.New(fmt.Sprintf("monitor not registered! name:%v", name))
+	}
+
+	SetDefault(monitor)
+	return nil
+}
+
+// SetDefault sets the default monitor, nil resets it to unset so that the fallback of DefaultMonitor is used again
+func SetDefault(monitor Monitor) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if monitor == nil {
+		defaultMonitor, defaultSet = NoopMonitor{}, false
+		return
+	}
+	defaultMonitor, defaultSet = monitor, true
+}
+
+// GetDefault returns the default monitor
+func GetDefault() Monitor {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	return defaultMonitor
+}
+
+// DefaultMonitor forwards the metrics to the default monitor resolved at call time, so it could be created
+// before Use or SetDefault is called, e.g.: by the generic ...WithMonitor(nil) entry points of consumers
+type DefaultMonitor struct {
+	fallback Monitor
+}
+
+// NewDefaultMonitor returns a DefaultMonitor pointer, fallback is used until the default monitor is set
+func NewDefaultMonitor(fallback Monitor) *DefaultMonitor {
+	if fallback == nil {
+		fallback = NoopMonitor{}
+	}
+	return &DefaultMonitor{fallback: fallback}
+}
+
+func (m *DefaultMonitor) Increment(name string, tags Tags) {
+	m.get().Increment(name, tags)
+}
+
+func (m *DefaultMonitor) Count(name string, value float64, tags Tags) {
+	m.get().Count(name, value, tags)
+}
+
+func (m *DefaultMonitor) Gauge(name string, value float64, tags Tags) {
+	m.get().Gauge(name, value, tags)
+}
+
+func (m *DefaultMonitor) Timing(name string, duration time.Duration, tags Tags) {
+	m.get().Timing(name, duration, tags)
+}
+
+func (m *DefaultMonitor) Histogram(name string, value float64, tags Tags) {
+	m.get().Histogram(name, value, tags)
+}
+
+// OrDefault returns m, or a DefaultMonitor without fallback if m is nil
+func OrDefault(m Monitor) Monitor {
+	if m == nil {
+		return NewDefaultMonitor(nil)
+	}
+	return m
+}
+
+// get returns the default monitor, or the fallback one if it's not set yet
+func (m *DefaultMonitor) get() Monitor {
+	mtx.RLock()
+	isSet := defaultSet
+	mtx.RUnlock()
+
+	if !isSet {
+		return m.fallback
+	}
+	return GetDefault()
+}
@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+// recordMonitor records the names of metrics received
+type recordMonitor struct {
+	names []string
+}
+
+func (m *recordMonitor) Increment(name string, tags Tags)            { m.names = append(m.names, name) }
+func (m *recordMonitor) Count(name string, value float64, tags Tags) { m.names = append(m.names, name) }
+func (m *recordMonitor) Gauge(name string, value float64, tags Tags) { m.names = append(m.names, name) }
+func (m *recordMonitor) Timing(name string, duration time.Duration, tags Tags) {
+	m.names = append(m.names, name)
+}
+func (m *recordMonitor) Histogram(name string, value float64, tags Tags) {
+	m.names = append(m.names, name)
+}
+
+func TestDefaultMonitor(t *testing.T) {
+	tests := []struct {
+		name         string
+		use          string
+		setNil       bool
+		wantFallback int
+		wantDefault  int
+	}{
+		{name: "fallback before default is set", wantFallback: 5},
+		{name: "default resolved after Use", use: "record", wantDefault: 5},
+		{name: "fallback after default is reset", use: "record", setNil: true, wantFallback: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				defaultMonitor, defaultSet = NoopMonitor{}, false
+			}()
+
+			fallback, record := &recordMonitor{}, &recordMonitor{}
+			Register("record", record)
+
+			// the monitor is created before the default one is chosen, like the package level interceptors
+			m := NewDefaultMonitor(fallback)
+			if tt.use != "" {
+				if err := Use(tt.use); err != nil {
+					t.Fatalf("Use() failed! error:%v", err)
+				}
+			}
+			if tt.setNil {
+				SetDefault(nil)
+			}
+
+			m.Increment("increment", nil)
+			m.Count("count", 1, nil)
+			m.Gauge("gauge", 1, nil)
+			m.Timing("timing", time.Second, nil)
+			m.Histogram("histogram", 1, nil)
+
+			if len(fallback.names) != tt.wantFallback {
+				t.Errorf("fallback got %v metrics, want %v", len(fallback.names), tt.wantFallback)
+			}
+			if len(record.names) != tt.wantDefault {
+				t.Errorf("default got %v metrics, want %v", len(record.names), tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestOrDefault(t *testing.T) {
+	defer func() {
+		defaultMonitor, defaultSet = NoopMonitor{}, false
+	}()
+
+	record, other := &recordMonitor{}, &recordMonitor{}
+	if m := OrDefault(other); m != other {
+		t.Errorf("OrDefault() = %v, want the monitor passed", m)
+	}
+
+	// the default monitor is resolved at call time, nothing is reported before it's set
+	m := OrDefault(nil)
+	m.Increment("before", nil)
+	SetDefault(record)
+	m.Increment("after", nil)
+	if len(record.names) != 1 || record.names[0] != "after" {
+		t.Errorf("default got %q, want [after]", record.names)
+	}
+}
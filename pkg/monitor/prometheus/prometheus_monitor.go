@@ -0,0 +1,383 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Buckets follow the same format as statsd buckets with InfluxDB tags, e.g.: 'grpc_server_handled_total,method=/foo.Bar/Baz,code=OK'
+// the name is used as the metric name and the tags become labels, every metric also has a constant 'project' label
+// the label names of a metric are fixed by its first report, later reports missing some of them are padded with
+// empty values and reports with unknown labels are rejected
+
+var project string = "Default"
+
+var mtx sync.Mutex
+var counters = make(map[string]*metricVec)
+var gauges = make(map[string]*metricVec)
+var histograms = make(map[string]*metricVec)
+
+// metricVec is a registered metric vector with the label names it was created with
+type metricVec struct {
+	collector  prometheus.Collector
+	labelNames []string
+}
+
+// init prometheus monitor, project is set as the constant label of all metrics
+func InitPrometheus(projectName string) error {
+	if len(projectName) == 0 {
+		return errors.New("project is empty")
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(counters) != 0 || len(gauges) != 0 || len(histograms) != 0 {
+		return errors.New("metrics already registered, prometheus monitor must be initialized before use")
+	}
+
+	project = projectName
+
+	log.Tracef(fmt.Sprintf("init prometheus success! project:%v", project))
+
+	return nil
+}
+
+// init
+func Init(args ...string) error {
+	if len(args) < 1 {
+		return errors.New(fmt.Sprintf("param invalid! args:%v", args))
+	}
+
+	return InitPrometheus(args[0])
+}
+
+// handler exposes all the metrics in prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// listen and serve '/metrics' on addr, it blocks until the server fails
+func ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	return http.ListenAndServe(addr, mux)
+}
+
+// increment
+func Increment(bucket string) {
+	Count(bucket, 1)
+}
+
+// count
+func Count(bucket string, number interface{}) {
+	value, err := toFloat64(number)
+
+	if err != nil {
+		log.Errorf("monitor count failed! bucket:%v, count:%v, error:%v", bucket, number, err)
+		return
+	}
+
+	name, labels := parseBucket(bucket)
+	count(name, value, labels)
+}
+
+func count(name string, value float64, labels prometheus.Labels) {
+	counter, labels, err := getCounter(name, labels)
+
+	if err != nil {
+		log.Errorf("monitor count failed! name:%v, labels:%v, count:%v, error:%v", name, labels, value, err)
+		return
+	}
+
+	log.Tracef("monitor count success! name:%v, labels:%v, count:%v", name, labels, value)
+
+	counter.With(labels).Add(value)
+}
+
+// gauge
+func Gauge(bucket string, value interface{}) {
+	gaugeValue, err := toFloat64(value)
+
+	if err != nil {
+		log.Errorf("monitor gauge failed! bucket:%v, value:%v, error:%v", bucket, value, err)
+		return
+	}
+
+	name, labels := parseBucket(bucket)
+	gauge(name, gaugeValue, labels)
+}
+
+func gauge(name string, value float64, labels prometheus.Labels) {
+	gaugeVec, labels, err := getGauge(name, labels)
+
+	if err != nil {
+		log.Errorf("monitor gauge failed! name:%v, labels:%v, value:%v, error:%v", name, labels, value, err)
+		return
+	}
+
+	log.Tracef("monitor gauge success! name:%v, labels:%v, value:%v", name, labels, value)
+
+	gaugeVec.With(labels).Set(value)
+}
+
+// histogram
+func Histogram(bucket string, value interface{}) {
+	observeValue, err := toFloat64(value)
+
+	if err != nil {
+		log.Errorf("monitor histogram failed! bucket:%v, value:%v, error:%v", bucket, value, err)
+		return
+	}
+
+	name, labels := parseBucket(bucket)
+	histogram(name, observeValue, labels)
+}
+
+func histogram(name string, value float64, labels prometheus.Labels) {
+	histogramVec, labels, err := getHistogram(name, labels)
+
+	if err != nil {
+		log.Errorf("monitor histogram failed! name:%v, labels:%v, value:%v, error:%v", name, labels, value, err)
+		return
+	}
+
+	log.Tracef("monitor histogram success! name:%v, labels:%v, value:%v", name, labels, value)
+
+	histogramVec.With(labels).Observe(value)
+}
+
+type MonitorTiming struct {
+	start time.Time
+}
+
+// timing
+func NewTiming() *MonitorTiming {
+	log.Tracef("monitor NewTiming success!")
+
+	return &MonitorTiming{start: time.Now()}
+}
+
+// send the duration since the timing was created to the latency histogram in seconds
+func (timing *MonitorTiming) Send(bucket string) {
+	Histogram(bucket, time.Since(timing.start).Seconds())
+}
+
+// sent timing
+func (timing *MonitorTiming) SendTiming(bucket string) {
+	log.Tracef("monitor SendTiming success! bucket:%v", bucket)
+
+	timing.Send(bucket)
+}
+
+// PrometheusMonitor implements monitor.Monitor, tags are used as labels and timings are observed in seconds
+type PrometheusMonitor struct{}
+
+// NewMonitor returns a monitor.Monitor exposing metrics to prometheus
+func NewMonitor() *PrometheusMonitor {
+	return &PrometheusMonitor{}
+}
+
+func init() {
+	monitor.Register("prometheus", NewMonitor())
+}
+
+func (m *PrometheusMonitor) Increment(name string, tags monitor.Tags) {
+	count(sanitize(name), 1, toLabels(tags))
+}
+
+func (m *PrometheusMonitor) Count(name string, value float64, tags monitor.Tags) {
+	count(sanitize(name), value, toLabels(tags))
+}
+
+func (m *PrometheusMonitor) Gauge(name string, value float64, tags monitor.Tags) {
+	gauge(sanitize(name), value, toLabels(tags))
+}
+
+func (m *PrometheusMonitor) Timing(name string, duration time.Duration, tags monitor.Tags) {
+	histogram(sanitize(name), duration.Seconds(), toLabels(tags))
+}
+
+func (m *PrometheusMonitor) Histogram(name string, value float64, tags monitor.Tags) {
+	histogram(sanitize(name), value, toLabels(tags))
+}
+
+// toLabels converts tags to labels
+func toLabels(tags monitor.Tags) prometheus.Labels {
+	labels := prometheus.Labels{}
+	for key, value := range tags {
+		labels[sanitize(key)] = value
+	}
+	return labels
+}
+
+// parseBucket splits bucket into metric name and labels
+func parseBucket(bucket string) (string, prometheus.Labels) {
+	parts := strings.Split(bucket, ",")
+	labels := prometheus.Labels{}
+
+	for _, tag := range parts[1:] {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+		labels[sanitize(kv[0])] = kv[1]
+	}
+
+	return sanitize(parts[0]), labels
+}
+
+// sanitize replaces characters which are not allowed in metric or label names
+func sanitize(name string) string {
+	name = strings.TrimLeft(name, "/")
+
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// labelNames returns the sorted label names, the same metric must always be reported with the same label names
+func labelNames(labels prometheus.Labels) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// labels returns labels aligned to the label names of the vector, missing labels are padded with empty values
+// and unknown labels are rejected because prometheus requires the same label names for every report of a metric
+func (vec *metricVec) labels(name string, labels prometheus.Labels) (prometheus.Labels, error) {
+	aligned := make(prometheus.Labels, len(vec.labelNames))
+	for _, labelName := range vec.labelNames {
+		aligned[labelName] = labels[labelName]
+	}
+
+	for labelName := range labels {
+		if _, ok := aligned[labelName]; !ok {
+			return nil, errors.New(fmt.Sprintf("label not registered! name:%v, label:%v, registered labels:%v",
+				name, labelName, vec.labelNames))
+		}
+	}
+
+	return aligned, nil
+}
+
+// getMetricVec returns the vector registered by name in vecs, or registers the one created by newCollector
+func getMetricVec(vecs map[string]*metricVec, name string, labels prometheus.Labels,
+	newCollector func(labelNames []string) prometheus.Collector) (*metricVec, prometheus.Labels, error) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	vec, ok := vecs[name]
+	if !ok {
+		names := labelNames(labels)
+		collector := newCollector(names)
+
+		if err := prometheus.Register(collector); err != nil {
+			return nil, nil, errors.New(fmt.Sprintf("register metric failed! name:%v, error:%v", name, err))
+		}
+
+		vec = &metricVec{collector: collector, labelNames: names}
+		vecs[name] = vec
+	}
+
+	aligned, err := vec.labels(name, labels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vec, aligned, nil
+}
+
+func getCounter(name string, labels prometheus.Labels) (*prometheus.CounterVec, prometheus.Labels, error) {
+	vec, labels, err := getMetricVec(counters, name, labels, func(labelNames []string) prometheus.Collector {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        name,
+			Help:        name,
+			ConstLabels: prometheus.Labels{"project": project},
+		}, labelNames)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vec.collector.(*prometheus.CounterVec), labels, nil
+}
+
+func getGauge(name string, labels prometheus.Labels) (*prometheus.GaugeVec, prometheus.Labels, error) {
+	vec, labels, err := getMetricVec(gauges, name, labels, func(labelNames []string) prometheus.Collector {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        name,
+			Help:        name,
+			ConstLabels: prometheus.Labels{"project": project},
+		}, labelNames)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vec.collector.(*prometheus.GaugeVec), labels, nil
+}
+
+func getHistogram(name string, labels prometheus.Labels) (*prometheus.HistogramVec, prometheus.Labels, error) {
+	vec, labels, err := getMetricVec(histograms, name, labels, func(labelNames []string) prometheus.Collector {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        name,
+			Help:        name,
+			ConstLabels: prometheus.Labels{"project": project},
+			Buckets:     prometheus.DefBuckets,
+		}, labelNames)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vec.collector.(*prometheus.HistogramVec), labels, nil
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case time.Duration:
+		return v.Seconds(), nil
+	}
+
+	return 0, errors.New(fmt.Sprintf("value type not supported! value:%v", value))
+}
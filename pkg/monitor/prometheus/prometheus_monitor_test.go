@@ -0,0 +1,140 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseBucket(t *testing.T) {
+	tests := []struct {
+		bucket     string
+		wantName   string
+		wantLabels prometheus.Labels
+	}{
+		{bucket: "requests", wantName: "requests", wantLabels: prometheus.Labels{}},
+		{bucket: "grpc_server_handled_total,method=/foo.Bar/Baz,code=OK", wantName: "grpc_server_handled_total",
+			wantLabels: prometheus.Labels{"method": "/foo.Bar/Baz", "code": "OK"}},
+		{bucket: "/foo.Bar/Baz,type=Server.Total,invalid,=empty", wantName: "foo_Bar_Baz",
+			wantLabels: prometheus.Labels{"type": "Server.Total"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucket, func(t *testing.T) {
+			name, labels := parseBucket(tt.bucket)
+
+			if name != tt.wantName {
+				t.Fatalf("parseBucket name:%v, want:%v", name, tt.wantName)
+			}
+
+			if len(labels) != len(tt.wantLabels) {
+				t.Fatalf("parseBucket labels:%v, want:%v", labels, tt.wantLabels)
+			}
+
+			for key, value := range tt.wantLabels {
+				if labels[key] != value {
+					t.Fatalf("parseBucket labels:%v, want:%v", labels, tt.wantLabels)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		first   monitor.Tags
+		second  monitor.Tags
+		wantErr bool
+		want    prometheus.Labels
+	}{
+		{name: "same labels", first: monitor.Tags{"method": "a"}, second: monitor.Tags{"method": "b"},
+			want: prometheus.Labels{"method": "b"}},
+		{name: "missing labels padded", first: monitor.Tags{"method": "a", "code": "OK"}, second: monitor.Tags{"method": "b"},
+			want: prometheus.Labels{"method": "b", "code": ""}},
+		{name: "unknown labels rejected", first: monitor.Tags{"method": "a"}, second: monitor.Tags{"method": "b", "code": "OK"},
+			wantErr: true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// every case reports to its own metric since the default registry is shared
+			name := sanitize("test_labels_" + string(rune('a'+i)))
+
+			if _, _, err := getCounter(name, toLabels(tt.first)); err != nil {
+				t.Fatalf("getCounter failed! error:%v", err)
+			}
+
+			counter, labels, err := getCounter(name, toLabels(tt.second))
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("getCounter should fail! labels:%v", labels)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("getCounter failed! error:%v", err)
+			}
+
+			if len(labels) != len(tt.want) {
+				t.Fatalf("getCounter labels:%v, want:%v", labels, tt.want)
+			}
+
+			for key, value := range tt.want {
+				if got, ok := labels[key]; !ok || got != value {
+					t.Fatalf("getCounter labels:%v, want:%v", labels, tt.want)
+				}
+			}
+
+			counter.With(labels).Add(2)
+
+			if value := testutil.ToFloat64(counter.With(labels)); value != 2 {
+				t.Fatalf("counter value:%v, want:2", value)
+			}
+		})
+	}
+}
+
+func TestPrometheusMonitor(t *testing.T) {
+	m := NewMonitor()
+
+	m.Increment("test_monitor_total", monitor.Tags{"method": "/foo.Bar/Baz"})
+	m.Count("test_monitor_total", 2, monitor.Tags{"method": "/foo.Bar/Baz"})
+	// reported with an unknown label, it's rejected and the counter is unchanged
+	m.Count("test_monitor_total", 4, monitor.Tags{"method": "/foo.Bar/Baz", "code": "OK"})
+	m.Gauge("test_monitor_gauge", 3, nil)
+	m.Timing("test_monitor_latency", 1500*time.Millisecond, nil)
+
+	counter, labels, err := getCounter("test_monitor_total", prometheus.Labels{"method": "/foo.Bar/Baz"})
+	if err != nil {
+		t.Fatalf("getCounter failed! error:%v", err)
+	}
+
+	if value := testutil.ToFloat64(counter.With(labels)); value != 3 {
+		t.Fatalf("counter value:%v, want:3", value)
+	}
+
+	gauge, labels, err := getGauge("test_monitor_gauge", prometheus.Labels{})
+	if err != nil {
+		t.Fatalf("getGauge failed! error:%v", err)
+	}
+
+	if value := testutil.ToFloat64(gauge.With(labels)); value != 3 {
+		t.Fatalf("gauge value:%v, want:3", value)
+	}
+
+	// the same name can't be registered as another metric type
+	if _, _, err := getGauge("test_monitor_total", prometheus.Labels{}); err == nil {
+		t.Fatalf("getGauge should fail for a name registered as counter")
+	}
+
+	if count := testutil.CollectAndCount(histograms["test_monitor_latency"].collector); count != 1 {
+		t.Fatalf("histogram count:%v, want:1", count)
+	}
+}
@@ -3,9 +3,13 @@ package statsd
 import (
 	"fmt"
 	"errors"
+	"strings"
+	"time"
 
 	log "github.com/cihub/seelog"
 
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+
 	"github.com/alexcesaro/statsd"
 )
 
@@ -115,6 +119,34 @@ func Gauge(bucket string, value interface{}) {
 	statsd.Gauge(bucket, value)
 }
 
+// timing, value is in milliseconds
+func Timing(bucket string, value interface{}) {
+	statsd := GetStatsd()
+
+	if statsd == nil {
+		log.Tracef("monitor timing failed! bucket:%v, value:%v", bucket, value)
+		return
+	}
+
+	log.Tracef("monitor timing success! bucket:%v, value:%v", bucket, value)
+
+	statsd.Timing(bucket, value)
+}
+
+// histogram
+func Histogram(bucket string, value interface{}) {
+	statsd := GetStatsd()
+
+	if statsd == nil {
+		log.Tracef("monitor histogram failed! bucket:%v, value:%v", bucket, value)
+		return
+	}
+
+	log.Tracef("monitor histogram success! bucket:%v, value:%v", bucket, value)
+
+	statsd.Histogram(bucket, value)
+}
+
 type MonitorTiming struct {
 	statsd.Timing
 }
@@ -140,3 +172,50 @@ func (timing *MonitorTiming) SendTiming(bucket string) {
 
 	timing.Send(bucket)
 }
+
+// StatsdMonitor implements monitor.Monitor, tags are appended to the bucket in InfluxDB format, e.g.: 'name,k1=v1,k2=v2'
+type StatsdMonitor struct{}
+
+// NewMonitor returns a monitor.Monitor sending metrics to statsd
+func NewMonitor() *StatsdMonitor {
+	return &StatsdMonitor{}
+}
+
+func init() {
+	monitor.Register("statsd", NewMonitor())
+}
+
+func (m *StatsdMonitor) Increment(name string, tags monitor.Tags) {
+	Increment(formatBucket(name, tags))
+}
+
+func (m *StatsdMonitor) Count(name string, value float64, tags monitor.Tags) {
+	Count(formatBucket(name, tags), value)
+}
+
+func (m *StatsdMonitor) Gauge(name string, value float64, tags monitor.Tags) {
+	Gauge(formatBucket(name, tags), value)
+}
+
+// Timing sends the duration in milliseconds, the fraction is kept so that sub-millisecond durations are not dropped
+func (m *StatsdMonitor) Timing(name string, duration time.Duration, tags monitor.Tags) {
+	Timing(formatBucket(name, tags), float64(duration)/float64(time.Millisecond))
+}
+
+func (m *StatsdMonitor) Histogram(name string, value float64, tags monitor.Tags) {
+	Histogram(formatBucket(name, tags), value)
+}
+
+// formatBucket appends tags to name
+func formatBucket(name string, tags monitor.Tags) string {
+	var builder strings.Builder
+	builder.WriteString(name)
+
+	for _, key := range tags.Keys() {
+		builder.WriteString(",")
+		builder.WriteString(key)
+		builder.WriteString("=")
+		builder.WriteString(tags[key])
+	}
+	return builder.String()
+}
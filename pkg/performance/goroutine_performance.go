@@ -4,13 +4,23 @@ import (
 	"runtime"
 	"time"
 
-	monitor "github.com/DarkMetrix/gofra/pkg/monitor/statsd"
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	"github.com/DarkMetrix/gofra/pkg/monitor/statsd"
 	log "github.com/cihub/seelog"
 )
 
 func BeginGoroutinePerformanceMonitorWithStatsd() {
 	log.Infof("Begin Goroutine Performance Monitor with Statsd")
 
+	BeginGoroutinePerformanceMonitor(statsd.NewMonitor())
+}
+
+// BeginGoroutinePerformanceMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func BeginGoroutinePerformanceMonitor(m monitor.Monitor) {
+	log.Infof("Begin Goroutine Performance Monitor")
+
+	m = monitor.OrDefault(m)
+
 	ticker := time.NewTicker(time.Second * 10)
 
 	for {
@@ -18,7 +28,7 @@ func BeginGoroutinePerformanceMonitorWithStatsd() {
 		case t := <-ticker.C:
 			log.Tracef("Ticker triggered! time:%v", t)
 
-			monitor.Gauge("/application/performance/goroutine,type=number", runtime.NumGoroutine())
+			m.Gauge("/application/performance/goroutine", float64(runtime.NumGoroutine()), monitor.Tags{"type": "number"})
 		}
 	}
 }
@@ -37,4 +47,3 @@ func BeginGoroutinePerformanceMonitorWithLog() {
 		}
 	}
 }
-
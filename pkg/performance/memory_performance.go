@@ -4,13 +4,23 @@ import (
 	"runtime"
 	"time"
 
-	monitor "github.com/DarkMetrix/gofra/pkg/monitor/statsd"
+	"github.com/DarkMetrix/gofra/pkg/monitor"
+	"github.com/DarkMetrix/gofra/pkg/monitor/statsd"
 	log "github.com/cihub/seelog"
 )
 
 func BeginMemoryPerformanceMonitorWithStatsd() {
 	log.Infof("Begin Memory Performance Monitor with Statsd")
 
+	BeginMemoryPerformanceMonitor(statsd.NewMonitor())
+}
+
+// BeginMemoryPerformanceMonitor reports to m, or to the default monitor chosen by monitor.Use if m is nil
+func BeginMemoryPerformanceMonitor(m monitor.Monitor) {
+	log.Infof("Begin Memory Performance Monitor")
+
+	m = monitor.OrDefault(m)
+
 	ticker := time.NewTicker(time.Second * 10)
 
 	var lastMemStats runtime.MemStats
@@ -23,19 +33,19 @@ func BeginMemoryPerformanceMonitorWithStatsd() {
 			memStats := &runtime.MemStats{}
 			runtime.ReadMemStats(memStats)
 
-			monitor.Gauge("/application/performance/memory,type=heap_objects", int64(memStats.HeapObjects))
-			monitor.Gauge("/application/performance/memory,type=heap_alloc_bytes", int64(memStats.HeapAlloc))
-			monitor.Gauge("/application/performance/memory,type=heap_sys_bytes", int64(memStats.HeapSys))
-			monitor.Gauge("/application/performance/memory,type=heap_idle_bytes", int64(memStats.HeapIdle))
-			monitor.Gauge("/application/performance/memory,type=heap_in_use_bytes", int64(memStats.HeapInuse))
-			monitor.Gauge("/application/performance/memory,type=heap_released_bytes", int64(memStats.HeapReleased))
+			m.Gauge("/application/performance/memory", float64(memStats.HeapObjects), monitor.Tags{"type": "heap_objects"})
+			m.Gauge("/application/performance/memory", float64(memStats.HeapAlloc), monitor.Tags{"type": "heap_alloc_bytes"})
+			m.Gauge("/application/performance/memory", float64(memStats.HeapSys), monitor.Tags{"type": "heap_sys_bytes"})
+			m.Gauge("/application/performance/memory", float64(memStats.HeapIdle), monitor.Tags{"type": "heap_idle_bytes"})
+			m.Gauge("/application/performance/memory", float64(memStats.HeapInuse), monitor.Tags{"type": "heap_in_use_bytes"})
+			m.Gauge("/application/performance/memory", float64(memStats.HeapReleased), monitor.Tags{"type": "heap_released_bytes"})
 
 			if memStats.NumGC <= lastMemStats.NumGC {
-				monitor.Gauge("/application/performance/gc,type=gc_counts", int64(0))
-				monitor.Gauge("/application/performance/gc,type=gc_pause", int64(0))
+				m.Gauge("/application/performance/gc", 0, monitor.Tags{"type": "gc_counts"})
+				m.Gauge("/application/performance/gc", 0, monitor.Tags{"type": "gc_pause"})
 			} else {
-				monitor.Gauge("/application/performance/gc,type=gc_counts", int64(memStats.NumGC - lastMemStats.NumGC))
-				monitor.Gauge("/application/performance/gc,type=gc_pause", int64(memStats.PauseNs[(memStats.NumGC + 255) % 256]))
+				m.Gauge("/application/performance/gc", float64(memStats.NumGC - lastMemStats.NumGC), monitor.Tags{"type": "gc_counts"})
+				m.Gauge("/application/performance/gc", float64(memStats.PauseNs[(memStats.NumGC + 255) % 256]), monitor.Tags{"type": "gc_pause"})
 			}
 
 			lastMemStats = *memStats
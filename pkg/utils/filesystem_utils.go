@@ -44,7 +44,8 @@ func CreatePath(path string, override bool) error {
 		}
 	}
 
-	if err := os.MkdirAll(path, os.ModeDir); err != nil {
+	// the parents are created with the same permission as path, os.ModeDir has no permission bits at all
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
 		return err
 	}
 
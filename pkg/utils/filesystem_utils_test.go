@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreatePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		existing string
+		override bool
+	}{
+		{name: "single directory", path: "a"},
+		{name: "nested directories", path: "a/b/c"},
+		{name: "existing parent", path: "a/b/c", existing: "a"},
+		{name: "override existing", path: "a/b", existing: "a/b/c", override: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "gofra")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir failed! error:%v", err)
+			}
+			defer os.RemoveAll(root)
+
+			// a directory created with os.ModePerm has the permission 0777 &^ umask
+			if err := os.Mkdir(filepath.Join(root, "umask"), os.ModePerm); err != nil {
+				t.Fatalf("os.Mkdir failed! error:%v", err)
+			}
+			info, err := os.Stat(filepath.Join(root, "umask"))
+			if err != nil {
+				t.Fatalf("os.Stat failed! error:%v", err)
+			}
+			want := info.Mode().Perm()
+
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Join(root, tt.existing), os.ModePerm); err != nil {
+					t.Fatalf("os.MkdirAll failed! error:%v", err)
+				}
+			}
+			if err := CreatePath(filepath.Join(root, tt.path), tt.override); err != nil {
+				t.Fatalf("CreatePath() failed! error:%v", err)
+			}
+
+			// the parents created are checked, the path itself is changed to os.ModePerm regardless of umask
+			for dir := filepath.Dir(filepath.Join(root, tt.path)); dir != root; dir = filepath.Dir(dir) {
+				info, err := os.Stat(dir)
+				if err != nil {
+					t.Fatalf("os.Stat failed! error:%v", err)
+				}
+				if !info.IsDir() || info.Mode().Perm() != want {
+					t.Errorf("CreatePath() %v mode = %v, want directory of %v", dir, info.Mode(), want)
+				}
+			}
+			if tt.override {
+				if _, err := os.Stat(filepath.Join(root, tt.existing)); !os.IsNotExist(err) {
+					t.Errorf("CreatePath() kept %v, want removed", tt.existing)
+				}
+			}
+		})
+	}
+}